@@ -10,17 +10,21 @@ import (
 	"time"
 
 	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/domain/notification"
 	"teacher_notification_bot/internal/infra/config"
 	idb "teacher_notification_bot/internal/infra/database"
+	"teacher_notification_bot/internal/infra/httpserver"
 	"teacher_notification_bot/internal/infra/logger"
 	"teacher_notification_bot/internal/infra/scheduler"
 	"teacher_notification_bot/internal/infra/telegram"
+	"teacher_notification_bot/internal/version"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/telebot.v3"
 )
 
 func main() {
+	version.MarkStarted(time.Now())
 	fmt.Println("Teacher Notification Bot starting...")
 
 	ctx := context.Background()
@@ -33,8 +37,9 @@ func main() {
 
 	// Initialize Logger (AFTER config is loaded)
 	logger.Init(cfg)
+	notification.SetDateLayout(cfg.DateFormat) // Before anything can send a message
 
-	logger.Log.Info("Teacher Notification Bot starting...")
+	logger.Log.WithFields(logrus.Fields{"version": version.Version, "commit": version.Commit, "build_time": version.BuildTime}).Info("Teacher Notification Bot starting...")
 	logger.Log.Infof("Configuration loaded. LogLevel: %s, Environment: %s, Admin ID: %d, Manager ID: %d", cfg.LogLevel, cfg.Environment, cfg.AdminTelegramID, cfg.ManagerTelegramID)
 
 	// Initialize Database Connection
@@ -46,14 +51,16 @@ func main() {
 	logger.Log.Info("Database connection established successfully.")
 
 	// Initialize Repositories
-	teacherRepo := idb.NewPostgresTeacherRepository(db)
-	notificationRepo := idb.NewPostgresNotificationRepository(db)
+	dbRetry := idb.RetryPolicy{MaxAttempts: cfg.DBRetryAttempts, BaseDelay: cfg.DBRetryBaseDelay}
+	teacherRepo := idb.NewPostgresTeacherRepository(db, logger.Log.WithField("repository", "teachers")).WithRetry(dbRetry)
+	settingsRepo := idb.NewPostgresSettingsRepository(db)
+	var notificationRepo notification.Repository = idb.NewPostgresNotificationRepository(db, logger.Log.WithField("repository", "notifications")).WithRetry(dbRetry).WithStrictIdentity(cfg.StrictStatusUpdates)
+	if cfg.CycleCacheTTL > 0 {
+		notificationRepo = idb.NewCachedNotificationRepository(notificationRepo, cfg.CycleCacheTTL)
+		logger.Log.Infof("Cycle lookup cache enabled with TTL %s", cfg.CycleCacheTTL)
+	}
 	logger.Log.Info("Repositories initialized.")
 
-	// Initialize AdminService
-	adminLogger := logger.Log.WithField("service", "AdminService")
-	adminService := app.NewAdminService(teacherRepo, cfg.AdminTelegramID, adminLogger)
-
 	// Initialize Telegram Bot
 	pref := telebot.Settings{
 		Token:  cfg.TelegramToken,
@@ -85,9 +92,61 @@ func main() {
 	if err != nil {
 		logger.Log.Fatalf("FATAL: Could not create Telegram bot: %v", err)
 	}
+	// Middleware only applies to handlers registered after it, so it goes first. The first one listed runs outermost.
+	bot.Use(
+		telegram.Recover(logger.Log.WithField("component", "handler_recovery")),
+		telegram.LogRequests(logger.Log.WithField("component", "handler_requests")),
+		telegram.ChatAllowlist(cfg.AllowedChatIDs, logger.Log.WithField("component", "chat_allowlist")),
+		telegram.RequireSender(logger.Log.WithField("component", "require_sender")),
+	)
 
 	// Create TelebotAdapter
-	telegramClientAdapter := telegram.NewTelebotAdapter(bot)
+	var sandboxRecipientID int64
+	if cfg.SandboxMode {
+		sandboxRecipientID = cfg.AdminTelegramID
+		logger.Log.Warn("SANDBOX_MODE is on: all outgoing messages are redirected to the admin.")
+	}
+	telegramClientAdapter := telegram.NewTelebotAdapter(bot, sandboxRecipientID).WithCircuitBreaker(cfg.TelegramBreakerThreshold, cfg.TelegramBreakerCooldown)
+
+	// Initialize AdminService
+	adminLogger := logger.Log.WithField("service", "AdminService")
+	adminService := app.NewAdminService(
+		teacherRepo,
+		telegramClientAdapter,
+		cfg.AdminTelegramID,
+		adminLogger,
+		app.AdminSettings{
+			WelcomeMessageEnabled:       cfg.WelcomeMessageEnabled,
+			WelcomeMessageTemplate:      cfg.WelcomeMessageTemplate,
+			DeactivationMessageEnabled:  cfg.DeactivationMessageEnabled,
+			DeactivationMessageTemplate: cfg.DeactivationMessageTemplate,
+			MaxNameLength:               cfg.MaxTeacherNameLength,
+		},
+	)
+
+	// Manager ID can be changed at runtime via /set_manager; MANAGER_TELEGRAM_ID is the fallback.
+	// Likewise /set_critical_reports overrides MANAGER_CRITICAL_REPORTS.
+	managerCriticalKeys, err := app.ParseCriticalReportKeys(cfg.ManagerCriticalReports)
+	if err != nil {
+		logger.Log.Fatalf("FATAL: Invalid MANAGER_CRITICAL_REPORTS: %v", err)
+	}
+	managerSettings := app.NewManagerSettingsService(
+		settingsRepo,
+		telegramClientAdapter,
+		cfg.AdminTelegramID,
+		cfg.ManagerTelegramID,
+		managerCriticalKeys,
+		logger.Log.WithField("service", "ManagerSettingsService"),
+	)
+	if managerID, err := managerSettings.CurrentManagerID(context.Background()); err != nil {
+		logger.Log.WithError(err).Warn("Could not resolve the current manager at startup.")
+	} else if managerID == 0 {
+		logger.Log.Warn("No manager is configured. Manager-facing messages will be skipped until one is set with /set_manager.")
+	}
+
+	if _, err := app.ParseOverlapAction(cfg.OverlappingCycleAction); err != nil {
+		logger.Log.Fatalf("FATAL: Invalid OVERLAPPING_CYCLE_ACTION: %v", err)
+	}
 
 	// Initialize REAL NotificationService
 	notifServiceLogger := logger.Log.WithField("service", "NotificationService")
@@ -96,7 +155,9 @@ func main() {
 		notificationRepo,
 		telegramClientAdapter,
 		notifServiceLogger,
-		cfg.ManagerTelegramID, // Pass ManagerTelegramID
+		managerSettings,
+		managerSettings,
+		notificationSettings(cfg, sandboxRecipientID),
 	)
 	logger.Log.Info("Application services initialized.")
 
@@ -110,18 +171,55 @@ func main() {
 		cfg.CronSpecDailyCheckForLastDay,
 		cfg.CronSpecReminderCheck,
 		cfg.CronSpecNextDayCheck,
-	)
+	).WithFailureAlerts(telegramClientAdapter, cfg.AdminTelegramID, cfg.SchedulerRetryDelay, cfg.SchedulerMaxRetries).
+		WithEndMonthNextDayCheck(cfg.CronSpecNextDayCheckEndMonth)
+	if cfg.PrenotifyEnabled {
+		notifScheduler.WithPrenotify(cfg.CronSpecPrenotify)
+	}
 	logger.Log.Info("Notification scheduler initialized.")
 
 	notifScheduler.Start() // Start the cron jobs
 
 	// Register Handlers
-	telegram.RegisterAdminHandlers(ctx, bot, adminService, cfg.AdminTelegramID, logger.Log.WithField("handler_group", "admin"))
-	telegram.RegisterTeacherResponseHandlers(ctx, bot, notificationService, logger.Log.WithField("handler_group", "teacher_response"))
+	telegram.SetAdminLocation(cfg.AdminLocation)
+	telegram.RegisterAdminHandlers(ctx, bot, adminService, cfg.AdminTelegramID, cfg.ConfirmDestructiveCommands, logger.Log.WithField("handler_group", "admin"))
+	telegram.RegisterCycleAdminHandlers(ctx, bot, notificationService, cfg.AdminTelegramID, logger.Log.WithField("handler_group", "cycle_admin"))
+	telegram.RegisterManagerHandlers(ctx, bot, notificationService, managerSettings, cfg.AdminTelegramID, logger.Log.WithField("handler_group", "manager"))
+	// /reload_config: the cron specs go to the scheduler, the rest is rebuilt into the notification settings.
+	// Handlers that read reloadable settings get liveCfg; cfg itself stays the startup snapshot.
+	liveCfg := config.NewLive(cfg)
+	applyConfig := func(next *config.AppConfig) error {
+		if err := notifScheduler.Reload(next); err != nil {
+			return err
+		}
+		notificationService.UpdateSettings(notificationSettings(liveCfg.ApplyReloadable(next), sandboxRecipientID))
+		return nil
+	}
+	telegram.RegisterSystemAdminHandlers(ctx, bot, liveCfg, managerSettings, telegramClientAdapter, applyConfig, logger.Log.WithField("handler_group", "system_admin"))
+	unknownCallbackAction, err := telegram.ParseUnknownCallbackAction(cfg.UnknownCallbackAction)
+	if err != nil {
+		logger.Log.Fatalf("FATAL: Invalid UNKNOWN_CALLBACK_ACTION: %v", err)
+	}
+	callbackRouter := telegram.NewCallbackRouter(unknownCallbackAction, logger.Log.WithField("handler_group", "callbacks"))
+	telegram.RegisterTeacherResponseHandlers(ctx, callbackRouter, notificationService)
+	telegram.RegisterTeacherListPaging(ctx, callbackRouter, adminService, cfg.AdminTelegramID)
+	telegram.RegisterRemoveTeacherConfirmation(ctx, callbackRouter, adminService, cfg.AdminTelegramID)
+	callbackRouter.Register(bot)
+	if cfg.ReplyKeyboardAnswers {
+		telegram.RegisterTeacherTextAnswerHandler(ctx, bot, notificationService, logger.Log.WithField("handler_group", "teacher_text_answers"))
+	}
+	telegram.RegisterTeacherCommands(ctx, bot, notificationService, teacherRepo, liveCfg, logger.Log.WithField("handler_group", "teacher_commands"))
 	// Register general bot commands
-	telegram.RegisterBotCommands(ctx, bot, cfg, teacherRepo, logger.Log.WithField("handler_group", "general_bot_commands"))
+	telegram.RegisterBotCommands(ctx, bot, cfg, teacherRepo, managerSettings, logger.Log.WithField("handler_group", "general_bot_commands"))
 	logger.Log.Info("Admin, Teacher Response, and General Bot command handlers registered.")
 
+	// Optional read-only HTTP endpoint: /healthz, /readyz and a JSON /stats snapshot
+	var httpServer *httpserver.Server
+	if cfg.HTTPAddr != "" {
+		httpServer = httpserver.NewServer(cfg.HTTPAddr, notificationService, logger.Log.WithField("component", "HTTPServer")).WithHeartbeat(notifScheduler)
+		httpServer.Start()
+	}
+
 	logger.Log.Info("Application setup complete. Bot and Scheduler are starting...")
 
 	// Start bot in a goroutine so it doesn't block graceful shutdown handling
@@ -134,8 +232,36 @@ func main() {
 
 	logger.Log.Info("Shutting down application...")
 	notifScheduler.Stop()
+	if httpServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Log.WithError(err).Warn("Failed to shut down HTTP server cleanly")
+		}
+		cancel()
+	}
 	db.Close() // Explicitly close DB connection
 	// bot.Stop() // If your bot library has a stop method, call it. Telebot poller stops on its own.
 	// db.Close() is handled by defer
 	logger.Log.Info("Application shut down gracefully.")
 }
+
+// notificationSettings builds the notification service settings from the configuration.
+func notificationSettings(cfg *config.AppConfig, sandboxRecipientID int64) app.NotificationSettings {
+	return app.NotificationSettings{
+		NormalizeNameCasing:        cfg.NormalizeNameCasing,
+		AdminTelegramID:            cfg.AdminTelegramID,
+		SandboxRecipientID:         sandboxRecipientID,
+		CoalesceReminders:          cfg.CoalesceReminders,
+		ReminderJitter:             cfg.ReminderJitter,
+		MaxAnswerAge:               cfg.MaxAnswerAge,
+		ReplyKeyboardAnswers:       cfg.ReplyKeyboardAnswers,
+		AnnounceReportCount:        cfg.AnnounceReportCount,
+		ReminderAlreadyDoneNote:    cfg.ReminderAlreadyDoneNote,
+		ManagerKickoffAnnouncement: cfg.ManagerKickoffAnnouncement,
+		OverlapAction:              app.OverlapAction(cfg.OverlappingCycleAction), // Validated at startup
+		ReminderDelays: map[notification.CycleType]app.ReminderDelays{
+			notification.CycleTypeMidMonth: {AfterNo: cfg.Reminder1HDelayMidMonth, AfterFirstReminder: cfg.Reminder4HDelayMidMonth},
+			notification.CycleTypeEndMonth: {AfterNo: cfg.Reminder1HDelayEndMonth, AfterFirstReminder: cfg.Reminder4HDelayEndMonth},
+		},
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"teacher_notification_bot/internal/domain/teacher"
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
 	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
@@ -15,19 +19,39 @@ var (
 	ErrAdminNotAuthorized     = fmt.Errorf("admin not authorized")
 	ErrTeacherAlreadyExists   = fmt.Errorf("teacher with this telegram ID already exists")
 	ErrTeacherAlreadyInactive = fmt.Errorf("teacher is already inactive")
+	// ErrWelcomeMessageNotDelivered is returned together with the created teacher when only the welcome message failed.
+	ErrWelcomeMessageNotDelivered = fmt.Errorf("teacher added, but the welcome message could not be delivered")
+	// ErrDeactivationMessageNotDelivered is returned together with the deactivated teacher when only the notice failed.
+	ErrDeactivationMessageNotDelivered = fmt.Errorf("teacher deactivated, but the deactivation message could not be delivered")
+	ErrFirstNameEmpty                  = fmt.Errorf("teacher first name is empty")
+	ErrNameTooLong                     = fmt.Errorf("teacher name is too long")
+	ErrInvalidReminderDelay            = fmt.Errorf("reminder delay must be at least a minute and at most %s", maxReminderDelayOverride)
 )
 
+// AdminSettings holds the tunable behaviour of the admin service.
+type AdminSettings struct {
+	WelcomeMessageEnabled       bool   // Send a welcome message to newly added teachers
+	WelcomeMessageTemplate      string // "{name}" is replaced with the teacher's first name
+	DeactivationMessageEnabled  bool   // Tell deactivated teachers that reminders stop
+	DeactivationMessageTemplate string // "{name}" is replaced with the teacher's first name
+	MaxNameLength               int    // Maximum length of a first or last name in characters; 0 means no limit
+}
+
 type AdminService struct {
 	teacherRepo     teacher.Repository
+	telegramClient  domainTelegram.Client
 	adminTelegramID int64
 	log             *logrus.Entry
+	settings        AdminSettings
 }
 
-func NewAdminService(tr teacher.Repository, adminID int64, baseLogger *logrus.Entry) *AdminService {
+func NewAdminService(tr teacher.Repository, tc domainTelegram.Client, adminID int64, baseLogger *logrus.Entry, settings AdminSettings) *AdminService {
 	return &AdminService{
 		teacherRepo:     tr,
+		telegramClient:  tc,
 		adminTelegramID: adminID,
 		log:             baseLogger,
+		settings:        settings,
 	}
 }
 
@@ -47,8 +71,14 @@ func (s *AdminService) AddTeacher(ctx context.Context, performingAdminID int64,
 		return nil, ErrAdminNotAuthorized
 	}
 
+	firstName, lastNameValue, err := s.normalizeTeacherName(firstName, lastNameValue)
+	if err != nil {
+		logCtx.WithError(err).Warn("Invalid teacher name")
+		return nil, err
+	}
+
 	// Check if teacher already exists by Telegram ID
-	_, err := s.teacherRepo.GetByTelegramID(ctx, newTeacherTelegramID)
+	_, err = s.teacherRepo.GetByTelegramID(ctx, newTeacherTelegramID)
 	if err == nil { // Teacher found, so already exists
 		logCtx.Warn("Teacher with this Telegram ID already exists")
 		return nil, ErrTeacherAlreadyExists
@@ -66,7 +96,8 @@ func (s *AdminService) AddTeacher(ctx context.Context, performingAdminID int64,
 			String: lastNameValue,
 			Valid:  lastNameValue != "",
 		},
-		IsActive: true, // New teachers are active by default
+		IsActive:                true, // New teachers are active by default
+		NotifyManagerOnComplete: true, // Manager is pinged on completion unless opted out later
 	}
 
 	// Persist to database
@@ -85,6 +116,16 @@ func (s *AdminService) AddTeacher(ctx context.Context, performingAdminID int64,
 		"teacher_tg_id":     newTeacher.TelegramID,
 		"teacher_is_active": newTeacher.IsActive,
 	}).Info("Teacher added successfully")
+
+	if s.settings.WelcomeMessageEnabled {
+		welcomeText := strings.ReplaceAll(s.settings.WelcomeMessageTemplate, "{name}", newTeacher.FirstName)
+		if err := s.telegramClient.SendMessage(newTeacher.TelegramID, welcomeText, nil); err != nil {
+			// Most likely the teacher has not pressed /start yet; the teacher record itself is fine.
+			logCtx.WithError(err).WithField("teacher_id", newTeacher.ID).Warn("Failed to send welcome message to new teacher")
+			return newTeacher, ErrWelcomeMessageNotDelivered
+		}
+		logCtx.WithField("teacher_id", newTeacher.ID).Info("Welcome message sent to new teacher")
+	}
 	return newTeacher, nil
 }
 
@@ -130,6 +171,16 @@ func (s *AdminService) RemoveTeacher(ctx context.Context, performingAdminID int6
 		"teacher_id":    targetTeacher.ID,
 		"teacher_tg_id": targetTeacher.TelegramID,
 	}).Info("Teacher removed (deactivated) successfully")
+
+	if s.settings.DeactivationMessageEnabled {
+		noticeText := strings.ReplaceAll(s.settings.DeactivationMessageTemplate, "{name}", targetTeacher.GreetingName())
+		if err := s.telegramClient.SendMessage(targetTeacher.TelegramID, noticeText, nil); err != nil {
+			// The teacher may have blocked the bot; the deactivation itself is done.
+			logCtx.WithError(err).WithField("teacher_id", targetTeacher.ID).Warn("Failed to send deactivation message to teacher")
+			return targetTeacher, ErrDeactivationMessageNotDelivered
+		}
+		logCtx.WithField("teacher_id", targetTeacher.ID).Info("Deactivation message sent to teacher")
+	}
 	return targetTeacher, nil
 }
 
@@ -174,3 +225,224 @@ func (s *AdminService) ListActiveTeachers(ctx context.Context, performingAdminID
 	logCtx.WithField("count", len(activeTeachers)).Info("Successfully listed active teachers")
 	return activeTeachers, nil
 }
+
+// SetManagerNotify toggles whether the manager is notified when the teacher confirms all reports.
+func (s *AdminService) SetManagerNotify(ctx context.Context, performingAdminID int64, teacherTelegramID int64, enabled bool) (*teacher.Teacher, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "SetManagerNotify",
+		"performing_admin_id": performingAdminID,
+		"teacher_tg_id":       teacherTelegramID,
+		"notify_manager":      enabled,
+	})
+	logCtx.Info("Attempting to change manager notification flag for teacher")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to change manager notification flag")
+		return nil, ErrAdminNotAuthorized
+	}
+
+	targetTeacher, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, idb.ErrTeacherNotFound
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+
+	targetTeacher.NotifyManagerOnComplete = enabled
+	if err := s.teacherRepo.Update(ctx, targetTeacher); err != nil {
+		logCtx.WithError(err).WithField("teacher_id", targetTeacher.ID).Error("Failed to update manager notification flag in repository")
+		return nil, fmt.Errorf("failed to update teacher in repository: %w", err)
+	}
+
+	logCtx.WithField("teacher_id", targetTeacher.ID).Info("Manager notification flag updated successfully")
+	return targetTeacher, nil
+}
+
+// SetWorkDays changes the weekdays on which the teacher receives reminders.
+func (s *AdminService) SetWorkDays(ctx context.Context, performingAdminID int64, teacherTelegramID int64, workDays teacher.WorkDays) (*teacher.Teacher, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "SetWorkDays",
+		"performing_admin_id": performingAdminID,
+		"teacher_tg_id":       teacherTelegramID,
+		"work_days":           workDays.String(),
+	})
+	logCtx.Info("Attempting to change teacher work days")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to change teacher work days")
+		return nil, ErrAdminNotAuthorized
+	}
+
+	targetTeacher, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, idb.ErrTeacherNotFound
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+
+	targetTeacher.WorkDays = workDays
+	if err := s.teacherRepo.Update(ctx, targetTeacher); err != nil {
+		logCtx.WithError(err).WithField("teacher_id", targetTeacher.ID).Error("Failed to update work days in repository")
+		return nil, fmt.Errorf("failed to update teacher in repository: %w", err)
+	}
+
+	logCtx.WithField("teacher_id", targetTeacher.ID).Info("Teacher work days updated successfully")
+	return targetTeacher, nil
+}
+
+// maxReminderDelayOverride caps a teacher's own reminder delay, like snoozes are capped.
+const maxReminderDelayOverride = 7 * 24 * time.Hour
+
+// SetReminderDelay overrides the delay before the reminder that follows the teacher's "No".
+// A nil delay clears the override so the cycle's delay applies again.
+func (s *AdminService) SetReminderDelay(ctx context.Context, performingAdminID int64, teacherTelegramID int64, delay *time.Duration) (*teacher.Teacher, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "SetReminderDelay",
+		"performing_admin_id": performingAdminID,
+		"teacher_tg_id":       teacherTelegramID,
+	})
+	if delay != nil {
+		logCtx = logCtx.WithField("delay", delay.String())
+	}
+	logCtx.Info("Attempting to change teacher reminder delay")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to change teacher reminder delay")
+		return nil, ErrAdminNotAuthorized
+	}
+	if delay != nil && (*delay < time.Minute || *delay > maxReminderDelayOverride) {
+		logCtx.Warn("Reminder delay out of range")
+		return nil, ErrInvalidReminderDelay
+	}
+
+	targetTeacher, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, idb.ErrTeacherNotFound
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+
+	targetTeacher.ReminderDelayOverride = sql.NullInt64{}
+	if delay != nil {
+		targetTeacher.ReminderDelayOverride = sql.NullInt64{Int64: int64(*delay / time.Second), Valid: true}
+	}
+	if err := s.teacherRepo.Update(ctx, targetTeacher); err != nil {
+		logCtx.WithError(err).WithField("teacher_id", targetTeacher.ID).Error("Failed to update reminder delay in repository")
+		return nil, fmt.Errorf("failed to update teacher in repository: %w", err)
+	}
+
+	logCtx.WithField("teacher_id", targetTeacher.ID).Info("Teacher reminder delay updated successfully")
+	return targetTeacher, nil
+}
+
+// SetDisplayName sets the name the teacher is addressed by in messages. An empty name clears it,
+// so the first name is used again. The stored first and last names are not changed.
+func (s *AdminService) SetDisplayName(ctx context.Context, performingAdminID int64, teacherTelegramID int64, displayName string) (*teacher.Teacher, error) {
+	displayName = strings.TrimSpace(displayName)
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "SetDisplayName",
+		"performing_admin_id": performingAdminID,
+		"teacher_tg_id":       teacherTelegramID,
+		"display_name":        displayName,
+	})
+	logCtx.Info("Attempting to change teacher display name")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to change teacher display name")
+		return nil, ErrAdminNotAuthorized
+	}
+	if s.settings.MaxNameLength > 0 && utf8.RuneCountInString(displayName) > s.settings.MaxNameLength {
+		logCtx.Warn("Display name too long")
+		return nil, ErrNameTooLong
+	}
+
+	targetTeacher, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, idb.ErrTeacherNotFound
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+
+	targetTeacher.DisplayName = sql.NullString{String: displayName, Valid: displayName != ""}
+	if err := s.teacherRepo.Update(ctx, targetTeacher); err != nil {
+		logCtx.WithError(err).WithField("teacher_id", targetTeacher.ID).Error("Failed to update display name in repository")
+		return nil, fmt.Errorf("failed to update teacher in repository: %w", err)
+	}
+
+	logCtx.WithField("teacher_id", targetTeacher.ID).Info("Teacher display name updated successfully")
+	return targetTeacher, nil
+}
+
+// EnsureTeacher creates the teacher or refreshes the name of the existing one with the same Telegram ID.
+// Integrations can call it without checking existence first. A deactivated teacher stays
+// deactivated unless allowReactivate is set. The returned bool reports whether the teacher was created.
+func (s *AdminService) EnsureTeacher(ctx context.Context, performingAdminID int64, teacherTelegramID int64, firstName string, lastNameValue string, allowReactivate bool) (*teacher.Teacher, bool, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "EnsureTeacher",
+		"performing_admin_id": performingAdminID,
+		"teacher_tg_id":       teacherTelegramID,
+		"allow_reactivate":    allowReactivate,
+	})
+	logCtx.Info("Attempting to ensure teacher")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to ensure teacher")
+		return nil, false, ErrAdminNotAuthorized
+	}
+
+	return s.ensureTeacher(ctx, logCtx, s.teacherRepo, teacherTelegramID, firstName, lastNameValue, allowReactivate)
+}
+
+// ensureTeacher is EnsureTeacher without the authorization check, against repo (which may be transaction-bound).
+func (s *AdminService) ensureTeacher(ctx context.Context, logCtx *logrus.Entry, repo teacher.Repository, teacherTelegramID int64, firstName string, lastNameValue string, allowReactivate bool) (*teacher.Teacher, bool, error) {
+	firstName, lastNameValue, err := s.normalizeTeacherName(firstName, lastNameValue)
+	if err != nil {
+		logCtx.WithError(err).Warn("Invalid teacher name")
+		return nil, false, err
+	}
+
+	t := &teacher.Teacher{
+		TelegramID: teacherTelegramID,
+		FirstName:  firstName,
+		LastName: sql.NullString{
+			String: lastNameValue,
+			Valid:  lastNameValue != "",
+		},
+		IsActive:                true,
+		NotifyManagerOnComplete: true,
+	}
+	created, err := repo.UpsertTeacher(ctx, t, allowReactivate)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to upsert teacher in repository")
+		return nil, false, fmt.Errorf("failed to upsert teacher in repository: %w", err)
+	}
+
+	logCtx.WithFields(logrus.Fields{
+		"teacher_id": t.ID,
+		"created":    created,
+		"is_active":  t.IsActive,
+	}).Info("Teacher ensured successfully")
+	return t, created, nil
+}
+
+// normalizeTeacherName trims surrounding whitespace and checks the names against MaxNameLength.
+// The last name is optional and may come back empty.
+func (s *AdminService) normalizeTeacherName(firstName, lastName string) (string, string, error) {
+	firstName = strings.TrimSpace(firstName)
+	lastName = strings.TrimSpace(lastName)
+	if firstName == "" {
+		return "", "", ErrFirstNameEmpty
+	}
+	if s.settings.MaxNameLength > 0 &&
+		(utf8.RuneCountInString(firstName) > s.settings.MaxNameLength || utf8.RuneCountInString(lastName) > s.settings.MaxNameLength) {
+		return "", "", ErrNameTooLong
+	}
+	return firstName, lastName, nil
+}
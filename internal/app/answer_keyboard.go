@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// Texts of the reply-keyboard answer buttons. Pressing one sends the text as a regular message.
+const (
+	AnswerTextYes = "Да"
+	AnswerTextNo  = "Нет"
+)
+
+// ErrNoPendingQuestion is returned when a text answer arrives but the teacher has no open question to apply it to.
+var ErrNoPendingQuestion = fmt.Errorf("no pending question to answer")
+
+// answerMarkup builds the "Да"/"Нет" buttons for a question. Inline buttons carry the status ID;
+// with ReplyKeyboardAnswers the buttons send plain text, resolved by ProcessTeacherTextAnswer.
+// Inline reminders also get a "Всё готово" button confirming every outstanding report of the cycle.
+func (s *NotificationServiceImpl) answerMarkup(reportStatus *notification.ReportStatus, mode questionMode) *telebot.ReplyMarkup {
+	replyMarkup := &telebot.ReplyMarkup{ResizeKeyboard: true}
+	if s.currentSettings().ReplyKeyboardAnswers {
+		replyMarkup.Reply(replyMarkup.Row(replyMarkup.Text(AnswerTextYes), replyMarkup.Text(AnswerTextNo)))
+		return replyMarkup
+	}
+	btnYes := replyMarkup.Data("Да", fmt.Sprintf("ans_yes_%d", reportStatus.ID))
+	btnNo := replyMarkup.Data("Нет", fmt.Sprintf("ans_no_%d", reportStatus.ID))
+	rows := []telebot.Row{replyMarkup.Row(btnYes, btnNo)}
+	if mode != questionModeInitial {
+		rows = append(rows, confirmAllRow(replyMarkup, reportStatus.CycleID))
+	}
+	replyMarkup.Inline(rows...)
+	return replyMarkup
+}
+
+// confirmAllRow is the "Всё готово" button handled by ProcessTeacherConfirmAllResponse.
+func confirmAllRow(replyMarkup *telebot.ReplyMarkup, cycleID int32) telebot.Row {
+	return replyMarkup.Row(replyMarkup.Data("Всё готово", fmt.Sprintf("ans_all_%d", cycleID)))
+}
+
+// resolvePendingQuestion finds the status a text answer from this Telegram user refers to:
+// the latest question they were asked that is still pending.
+func (s *NotificationServiceImpl) resolvePendingQuestion(ctx context.Context, senderTelegramID int64) (int64, error) {
+	teacherInfo, err := s.teacherRepo.GetByTelegramID(ctx, senderTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			return 0, ErrNoPendingQuestion
+		}
+		return 0, fmt.Errorf("failed to get teacher by telegram ID %d: %w", senderTelegramID, err)
+	}
+	rs, err := s.notifRepo.GetLatestPendingQuestion(ctx, teacherInfo.ID)
+	if err != nil {
+		if err == idb.ErrReportStatusNotFound {
+			return 0, ErrNoPendingQuestion
+		}
+		return 0, fmt.Errorf("failed to get pending question for teacher %d: %w", teacherInfo.ID, err)
+	}
+	return rs.ID, nil
+}
+
+// ProcessTeacherTextAnswer applies a reply-keyboard answer ("Да"/"Нет") to the teacher's latest pending question.
+func (s *NotificationServiceImpl) ProcessTeacherTextAnswer(ctx context.Context, senderTelegramID int64, answeredYes bool) error {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":    "ProcessTeacherTextAnswer",
+		"sender_tg_id": senderTelegramID,
+		"answered_yes": answeredYes,
+	})
+	reportStatusID, err := s.resolvePendingQuestion(ctx, senderTelegramID)
+	if err != nil {
+		if err == ErrNoPendingQuestion {
+			logCtx.Info("Text answer received, but there is no pending question")
+		} else {
+			logCtx.WithError(err).Error("Failed to resolve pending question for text answer")
+		}
+		return err
+	}
+	logCtx.WithField("report_status_id", reportStatusID).Info("Text answer mapped to pending question")
+
+	if answeredYes {
+		return s.ProcessTeacherYesResponse(ctx, reportStatusID, senderTelegramID)
+	}
+	return s.ProcessTeacherNoResponse(ctx, reportStatusID, senderTelegramID)
+}
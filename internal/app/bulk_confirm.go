@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BulkConfirmResult is the outcome of a proxy confirmation for one teacher.
+type BulkConfirmResult struct {
+	TelegramID     int64
+	Teacher        *teacher.Teacher // nil when the teacher was not found
+	ConfirmedCount int
+	Err            error // nil, idb.ErrTeacherNotFound, ErrNothingToConfirm or an unexpected error
+}
+
+// BulkConfirmForTeachers confirms every outstanding report of the given teachers in the cycle on their behalf,
+// e.g. after a manager collected the answers verbally. Each teacher goes through the same flow as the
+// "Всё готово" button, so completion messages are sent once per teacher, and the statuses record
+// proxyTelegramID as the one who confirmed. A failure for one teacher does not stop the others.
+func (s *NotificationServiceImpl) BulkConfirmForTeachers(ctx context.Context, proxyTelegramID int64, cycleID int32, teacherTelegramIDs []int64) ([]*BulkConfirmResult, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":      "BulkConfirmForTeachers",
+		"proxy_tg_id":    proxyTelegramID,
+		"cycle_id":       cycleID,
+		"teachers_count": len(teacherTelegramIDs),
+	})
+	logCtx.Info("Processing proxy confirmation")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err != idb.ErrCycleNotFound {
+			logCtx.WithError(err).Error("Failed to get cycle")
+		}
+		return nil, err
+	}
+
+	results := make([]*BulkConfirmResult, 0, len(teacherTelegramIDs))
+	for _, tgID := range teacherTelegramIDs {
+		result := &BulkConfirmResult{TelegramID: tgID}
+		results = append(results, result)
+		teacherLog := logCtx.WithField("teacher_tg_id", tgID)
+
+		teacherInfo, err := s.teacherRepo.GetByTelegramID(ctx, tgID)
+		if err != nil {
+			if err != idb.ErrTeacherNotFound {
+				teacherLog.WithError(err).Error("Failed to get teacher by Telegram ID")
+				err = fmt.Errorf("failed to get teacher by telegram ID %d: %w", tgID, err)
+			}
+			result.Err = err
+			continue
+		}
+		result.Teacher = teacherInfo
+
+		result.ConfirmedCount, result.Err = s.confirmAllForTeacher(ctx, teacherLog.WithField("teacher_id", teacherInfo.ID), teacherInfo, cycle, proxyTelegramID)
+	}
+	logCtx.Info("Proxy confirmation finished")
+	return results, nil
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+	"testing"
+)
+
+func TestBulkConfirmIsolatesTeacherFailures(t *testing.T) {
+	anna, boris, vera := testTeacher(1, "Анна"), testTeacher(2, "Борис"), testTeacher(3, "Вера")
+	svc, repo, _ := newTestService([]*teacher.Teacher{anna, boris, vera})
+	ctx := context.Background()
+	cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+	for _, key := range determineReportsForCycle(notification.CycleTypeMidMonth) {
+		answerYes(t, svc, repo, boris, cycleID, key)
+	}
+	const proxyID, unknownID = 777, 555
+
+	results, err := svc.BulkConfirmForTeachers(ctx, proxyID, cycleID, []int64{anna.TelegramID, unknownID, boris.TelegramID, vera.TelegramID})
+	if err != nil {
+		t.Fatalf("BulkConfirmForTeachers: %v", err)
+	}
+
+	want := []struct {
+		telegramID int64
+		confirmed  int
+		err        error
+	}{
+		{telegramID: anna.TelegramID, confirmed: 2},
+		{telegramID: unknownID, err: idb.ErrTeacherNotFound},
+		{telegramID: boris.TelegramID, err: ErrNothingToConfirm},
+		{telegramID: vera.TelegramID, confirmed: 2},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("%d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.TelegramID != w.telegramID || got.ConfirmedCount != w.confirmed || got.Err != w.err {
+			t.Errorf("result %d = {%d, %d confirmed, %v}, want {%d, %d confirmed, %v}", i, got.TelegramID, got.ConfirmedCount, got.Err, w.telegramID, w.confirmed, w.err)
+		}
+	}
+	for _, tc := range []*teacher.Teacher{anna, vera} {
+		for _, rs := range repo.statusesOf(tc.ID, cycleID) {
+			if rs.Status != notification.StatusAnsweredYes {
+				t.Errorf("%s %s: status = %s, want ANSWERED_YES", tc.FirstName, rs.ReportKey, rs.Status)
+			}
+		}
+	}
+}
+
+func TestBulkConfirmRecordsProxy(t *testing.T) {
+	anna, boris := testTeacher(1, "Анна"), testTeacher(2, "Борис")
+	svc, repo, _ := newTestService([]*teacher.Teacher{anna, boris})
+	ctx := context.Background()
+	cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+	answerYes(t, svc, repo, anna, cycleID, notification.ReportKeyTable1Lessons) // Confirmed by the teacher
+	const proxyID = 777
+
+	if _, err := svc.BulkConfirmForTeachers(ctx, proxyID, cycleID, []int64{anna.TelegramID}); err != nil {
+		t.Fatalf("BulkConfirmForTeachers: %v", err)
+	}
+
+	for _, rs := range repo.statusesOf(anna.ID, cycleID) {
+		want := int64(proxyID)
+		if rs.ReportKey == notification.ReportKeyTable1Lessons {
+			want = 0
+		}
+		if got := repo.proxies[rs.ID]; got != want {
+			t.Errorf("%s: proxy = %d, want %d", rs.ReportKey, got, want)
+		}
+	}
+	for _, rs := range repo.statusesOf(boris.ID, cycleID) {
+		if _, ok := repo.proxies[rs.ID]; ok {
+			t.Errorf("Борис %s: recorded as proxy-confirmed without being in the request", rs.ReportKey)
+		}
+	}
+}
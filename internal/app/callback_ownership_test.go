@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+)
+
+func TestAnswersFromAnotherUserAreRejected(t *testing.T) {
+	anna, boris := testTeacher(1, "Анна"), testTeacher(2, "Борис")
+	const strangerTelegramID = 555
+
+	tests := []struct {
+		name   string
+		answer func(svc *NotificationServiceImpl, statusID int64, cycleID int32) error
+	}{
+		{name: "yes from another teacher", answer: func(svc *NotificationServiceImpl, statusID int64, _ int32) error {
+			return svc.ProcessTeacherYesResponse(context.Background(), statusID, boris.TelegramID)
+		}},
+		{name: "no from another teacher", answer: func(svc *NotificationServiceImpl, statusID int64, _ int32) error {
+			return svc.ProcessTeacherNoResponse(context.Background(), statusID, boris.TelegramID)
+		}},
+		{name: "confirm all from a non-teacher", answer: func(svc *NotificationServiceImpl, _ int64, cycleID int32) error {
+			return svc.ProcessTeacherConfirmAllResponse(context.Background(), strangerTelegramID, cycleID)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := newTestService([]*teacher.Teacher{anna, boris})
+			cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+			rs, err := repo.GetReportStatus(context.Background(), anna.ID, cycleID, notification.ReportKeyTable1Lessons)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.answer(svc, rs.ID, cycleID); err != ErrCallbackOwnershipMismatch {
+				t.Fatalf("err = %v, want ErrCallbackOwnershipMismatch", err)
+			}
+			for _, status := range repo.statusesOf(anna.ID, cycleID) {
+				if status.Status != notification.StatusPendingQuestion {
+					t.Errorf("%s: status = %s, want it untouched as PENDING_QUESTION", status.ReportKey, status.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+	"testing"
+)
+
+func TestCircuitOpenIsNotRecordedAsSendFailure(t *testing.T) {
+	anna := testTeacher(1, "Анна")
+	svc, repo, client := newTestService([]*teacher.Teacher{anna})
+	ctx := context.Background()
+
+	client.sendErr = domainTelegram.ErrSendCircuitOpen
+	cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+	first, err := repo.GetReportStatus(ctx, anna.ID, cycleID, notification.ReportKeyTable1Lessons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.sendFailures) != 0 {
+		t.Errorf("initial question: send failures recorded %v, want none", repo.sendFailures)
+	}
+	if first.LastNotifiedAt.Valid {
+		t.Error("initial question: LastNotifiedAt set although nothing was sent")
+	}
+
+	// A 1-hour reminder blocked by the breaker leaves the status due for the next sweep.
+	client.sendErr = nil
+	if err := svc.ProcessTeacherNoResponse(ctx, first.ID, anna.TelegramID); err != nil {
+		t.Fatalf("ProcessTeacherNoResponse: %v", err)
+	}
+	repo.makeRemindersDue()
+	client.sendErr = domainTelegram.ErrSendCircuitOpen
+	if err := svc.ProcessScheduled1HourReminders(ctx); err != nil {
+		t.Fatalf("ProcessScheduled1HourReminders: %v", err)
+	}
+	if len(repo.sendFailures) != 0 {
+		t.Errorf("reminder: send failures recorded %v, want none", repo.sendFailures)
+	}
+	after, err := repo.GetReportStatusByID(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Status != notification.StatusAwaitingReminder1H || !after.RemindAt.Valid {
+		t.Errorf("reminder: status %s (remind_at valid %v), want it still awaiting the 1-hour reminder", after.Status, after.RemindAt.Valid)
+	}
+}
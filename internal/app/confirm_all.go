@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNothingToConfirm is returned by ProcessTeacherConfirmAllResponse when every report is already confirmed.
+var ErrNothingToConfirm = fmt.Errorf("all reports of the cycle are already confirmed")
+
+// ProcessTeacherConfirmAllResponse handles the "Всё готово" button: every outstanding report of the sender
+// in the cycle is marked ANSWERED_YES at once and the completion flow runs a single time.
+// Only the sender's own statuses are touched, since the teacher is resolved from the sender.
+func (s *NotificationServiceImpl) ProcessTeacherConfirmAllResponse(ctx context.Context, senderTelegramID int64, cycleID int32) error {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":    "ProcessTeacherConfirmAllResponse",
+		"sender_tg_id": senderTelegramID,
+		"cycle_id":     cycleID,
+	})
+	logCtx.Info("Processing 'confirm all' response")
+
+	teacherInfo, err := s.teacherRepo.GetByTelegramID(ctx, senderTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("'Confirm all' from a user who is not a teacher. Rejecting.")
+			return ErrCallbackOwnershipMismatch
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return fmt.Errorf("failed to get teacher by telegram ID %d: %w", senderTelegramID, err)
+	}
+	logCtx = logCtx.WithField("teacher_id", teacherInfo.ID)
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found. Possibly a stale callback.")
+			return nil // Acknowledge callback, but nothing to process
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+
+	_, err = s.confirmAllForTeacher(ctx, logCtx, teacherInfo, cycle, 0)
+	return err
+}
+
+// confirmAllForTeacher marks every outstanding report of the teacher in the cycle as ANSWERED_YES and runs the
+// completion flow once. A non-zero proxyTelegramID records who confirmed on the teacher's behalf.
+// It returns the number of confirmed reports, or ErrNothingToConfirm when there was nothing left.
+func (s *NotificationServiceImpl) confirmAllForTeacher(ctx context.Context, logCtx *logrus.Entry, teacherInfo *teacher.Teacher, cycle *notification.Cycle, proxyTelegramID int64) (int, error) {
+	cycleID := cycle.ID
+
+	statuses, err := s.notifRepo.ListReportStatusesByCycleAndTeacher(ctx, cycleID, teacherInfo.ID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list report statuses")
+		return 0, fmt.Errorf("failed to list report statuses for teacher %d, cycle %d: %w", teacherInfo.ID, cycleID, err)
+	}
+	now := time.Now()
+	var confirmed []*notification.ReportStatus
+	var confirmedKeys []notification.ReportKey
+	expired := 0
+	for _, rs := range statuses {
+		if rs.Status == notification.StatusAnsweredYes {
+			continue
+		}
+		// The answer window only limits teachers; a proxy confirmation is recorded as such instead.
+		if proxyTelegramID == 0 && s.answerWindowExpired(rs, now) {
+			expired++
+			continue
+		}
+		rs.Status = notification.StatusAnsweredYes
+		rs.RemindAt.Valid = false
+		rs.UpdatedAt = now
+		confirmed = append(confirmed, rs)
+		confirmedKeys = append(confirmedKeys, rs.ReportKey)
+	}
+	if len(confirmed) == 0 {
+		if expired > 0 {
+			logCtx.WithField("expired_count", expired).Warn("Every outstanding report is past the answer window. Rejecting.")
+			return 0, ErrAnswerWindowExpired
+		}
+		logCtx.Info("Nothing to confirm; every report is already ANSWERED_YES")
+		return 0, ErrNothingToConfirm
+	}
+	if expired > 0 {
+		logCtx.WithField("expired_count", expired).Warn("Some outstanding reports are past the answer window and stay unconfirmed")
+	}
+	if err := s.notifRepo.BulkUpdateReportStatuses(ctx, confirmed); err != nil {
+		var bulkErr *idb.BulkUpdateError
+		if !errors.As(err, &bulkErr) {
+			logCtx.WithError(err).Error("Failed to mark outstanding reports as ANSWERED_YES")
+			return 0, fmt.Errorf("failed to confirm reports for teacher %d, cycle %d: %w", teacherInfo.ID, cycleID, err)
+		}
+		// The other rows were committed; the completion check below sees what actually got confirmed.
+		logCtx.WithError(err).Warn("Some reports could not be marked as ANSWERED_YES")
+	}
+	logCtx.WithField("confirmed_count", len(confirmed)).Info("Outstanding reports marked as ANSWERED_YES")
+	if proxyTelegramID != 0 {
+		ids := make([]int64, 0, len(confirmed))
+		for _, rs := range confirmed {
+			ids = append(ids, rs.ID)
+		}
+		if err := s.notifRepo.MarkProxyConfirmed(ctx, ids, proxyTelegramID); err != nil {
+			// The confirmations themselves are saved; only the audit trail is missing.
+			logCtx.WithError(err).Error("Failed to record who confirmed on the teacher's behalf")
+		}
+	}
+
+	cycleKeys := determineReportsForCycle(cycle.Type)
+	allConfirmed, err := s.notifRepo.AreAllReportsConfirmedForTeacher(ctx, teacherInfo.ID, cycleID, cycleKeys)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to check if all reports confirmed for teacher")
+		return 0, fmt.Errorf("failed to check all reports confirmed for teacher %d, cycle %d: %w", teacherInfo.ID, cycleID, err)
+	}
+	if !allConfirmed {
+		// Some statuses failed to update or were never created; the regular flow takes over from here.
+		logCtx.Warn("Not every report is confirmed after 'confirm all'")
+		return len(confirmed), nil
+	}
+
+	// The manager was already told if their critical reports were confirmed before this click.
+	criticalKeys := s.managerReportKeys(ctx, logCtx, cycleKeys)
+	for _, k := range confirmedKeys {
+		if containsReportKey(criticalKeys, k) {
+			s.sendManagerConfirmation(ctx, teacherInfo, cycle, criticalKeys, cycleKeys)
+			break
+		}
+	}
+	return len(confirmed), s.finalizeTeacher(ctx, logCtx, teacherInfo, cycle)
+}
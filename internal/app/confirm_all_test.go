@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+	"time"
+)
+
+// fixedCriticalReports makes the listed reports the manager-critical ones.
+type fixedCriticalReports []notification.ReportKey
+
+func (k fixedCriticalReports) CriticalReportKeys(context.Context) ([]notification.ReportKey, error) {
+	return k, nil
+}
+
+func TestConfirmAllTellsManagerOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		critical CriticalReportKeySource
+		before   []notification.ReportKey // Confirmed one by one before "Всё готово"
+	}{
+		{name: "nothing confirmed before", critical: allReportsCritical{}},
+		{name: "critical report confirmed before", critical: fixedCriticalReports{notification.ReportKeyTable1Lessons},
+			before: []notification.ReportKey{notification.ReportKeyTable1Lessons}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anna := testTeacher(1, "Анна")
+			svc, repo, client := newTestService([]*teacher.Teacher{anna})
+			svc.criticalReports = tt.critical
+			cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+			for _, key := range tt.before {
+				answerYes(t, svc, repo, anna, cycleID, key)
+			}
+			if len(tt.before) > 0 && len(client.messagesTo(testManagerID)) != 1 {
+				t.Fatal("the manager was not told when the critical report was confirmed")
+			}
+
+			if err := svc.ProcessTeacherConfirmAllResponse(context.Background(), anna.TelegramID, cycleID); err != nil {
+				t.Fatalf("ProcessTeacherConfirmAllResponse: %v", err)
+			}
+			if got := len(client.messagesTo(testManagerID)); got != 1 {
+				t.Errorf("manager got %d confirmations, want 1", got)
+			}
+
+			// A second click has nothing left to confirm and tells nobody.
+			if err := svc.ProcessTeacherConfirmAllResponse(context.Background(), anna.TelegramID, cycleID); err != ErrNothingToConfirm {
+				t.Errorf("second click: err = %v, want ErrNothingToConfirm", err)
+			}
+			if got := len(client.messagesTo(testManagerID)); got != 1 {
+				t.Errorf("after a second click the manager got %d confirmations, want 1", got)
+			}
+		})
+	}
+}
+
+func TestConfirmAllRespectsAnswerWindow(t *testing.T) {
+	const maxAge = time.Hour
+	stale := sql.NullTime{Time: time.Now().Add(-2 * maxAge), Valid: true}
+	fresh := sql.NullTime{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name          string
+		notifiedAt    map[notification.ReportKey]sql.NullTime
+		proxy         bool
+		wantErr       error
+		wantConfirmed []notification.ReportKey
+	}{
+		{name: "every report expired",
+			notifiedAt: map[notification.ReportKey]sql.NullTime{notification.ReportKeyTable1Lessons: stale, notification.ReportKeyTable3Schedule: stale},
+			wantErr:    ErrAnswerWindowExpired},
+		{name: "one report expired",
+			notifiedAt:    map[notification.ReportKey]sql.NullTime{notification.ReportKeyTable1Lessons: stale, notification.ReportKeyTable3Schedule: fresh},
+			wantConfirmed: []notification.ReportKey{notification.ReportKeyTable3Schedule}},
+		{name: "proxy confirms expired reports", proxy: true,
+			notifiedAt:    map[notification.ReportKey]sql.NullTime{notification.ReportKeyTable1Lessons: stale, notification.ReportKeyTable3Schedule: stale},
+			wantConfirmed: []notification.ReportKey{notification.ReportKeyTable1Lessons, notification.ReportKeyTable3Schedule}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anna := testTeacher(1, "Анна")
+			svc, repo, _ := newTestService([]*teacher.Teacher{anna})
+			svc.UpdateSettings(NotificationSettings{MaxAnswerAge: maxAge})
+			ctx := context.Background()
+			cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+			for _, rs := range repo.statusesOf(anna.ID, cycleID) {
+				rs.LastNotifiedAt = tt.notifiedAt[rs.ReportKey]
+				if err := repo.UpdateReportStatus(ctx, rs); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var err error
+			if tt.proxy {
+				var results []*BulkConfirmResult
+				results, err = svc.BulkConfirmForTeachers(ctx, 777, cycleID, []int64{anna.TelegramID})
+				if err == nil {
+					err = results[0].Err
+				}
+			} else {
+				err = svc.ProcessTeacherConfirmAllResponse(ctx, anna.TelegramID, cycleID)
+			}
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			for _, rs := range repo.statusesOf(anna.ID, cycleID) {
+				if got, want := rs.Status == notification.StatusAnsweredYes, containsReportKey(tt.wantConfirmed, rs.ReportKey); got != want {
+					t.Errorf("%s: status = %s, want confirmed = %v", rs.ReportKey, rs.Status, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/settings"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// allReportsSetting is how "every report counts" is written in config and system settings.
+const allReportsSetting = "all"
+
+// CriticalReportKeySource supplies the reports whose confirmation is reported to the manager.
+// An empty result means every report of the cycle counts. Read it on every use: the value can change at runtime.
+type CriticalReportKeySource interface {
+	CriticalReportKeys(ctx context.Context) ([]notification.ReportKey, error)
+}
+
+// ParseCriticalReportKeys parses a comma-separated list of report keys (e.g. "TABLE_2_OTV,TABLE_1_LESSONS").
+// An empty value or "all" yields nil, meaning every report counts.
+func ParseCriticalReportKeys(raw string) ([]notification.ReportKey, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, allReportsSetting) {
+		return nil, nil
+	}
+	var keys []notification.ReportKey
+	for _, part := range strings.Split(raw, ",") {
+		key, err := notification.ParseReportKey(part)
+		if err != nil {
+			return nil, err
+		}
+		if !containsReportKey(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// FormatCriticalReportKeys is the inverse of ParseCriticalReportKeys.
+func FormatCriticalReportKeys(keys []notification.ReportKey) string {
+	if len(keys) == 0 {
+		return allReportsSetting
+	}
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = string(k)
+	}
+	return strings.Join(parts, ",")
+}
+
+func containsReportKey(keys []notification.ReportKey, key notification.ReportKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// CriticalReportKeys returns the persisted manager-critical reports, falling back to the configured ones.
+func (s *ManagerSettingsService) CriticalReportKeys(ctx context.Context) ([]notification.ReportKey, error) {
+	raw, err := s.settingsRepo.Get(ctx, settings.KeyManagerCriticalReportKeys)
+	if err != nil {
+		if err == idb.ErrSettingNotFound {
+			return s.fallbackCriticalKeys, nil
+		}
+		return nil, fmt.Errorf("failed to read critical reports setting: %w", err)
+	}
+	keys, err := ParseCriticalReportKeys(raw)
+	if err != nil {
+		return nil, fmt.Errorf("stored critical reports setting %q is invalid: %w", raw, err)
+	}
+	return keys, nil
+}
+
+// SetCriticalReportKeys changes which reports must be confirmed before the manager is told a teacher is done.
+// nil (or empty) keys make every report count again.
+func (s *ManagerSettingsService) SetCriticalReportKeys(ctx context.Context, performingAdminID int64, keys []notification.ReportKey) error {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "SetCriticalReportKeys",
+		"performing_admin_id": performingAdminID,
+		"report_keys":         FormatCriticalReportKeys(keys),
+	})
+	logCtx.Info("Attempting to change manager-critical reports")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to change manager-critical reports")
+		return ErrAdminNotAuthorized
+	}
+	if err := s.settingsRepo.Set(ctx, settings.KeyManagerCriticalReportKeys, FormatCriticalReportKeys(keys)); err != nil {
+		logCtx.WithError(err).Error("Failed to persist critical reports setting")
+		return fmt.Errorf("failed to persist critical reports setting: %w", err)
+	}
+	logCtx.Info("Manager-critical reports changed successfully")
+	return nil
+}
+
+// managerReportKeys returns the reports of a cycle whose confirmation the manager is told about.
+// When none of the critical reports belong to the cycle (or none are configured), every report counts.
+func (s *NotificationServiceImpl) managerReportKeys(ctx context.Context, logCtx *logrus.Entry, cycleKeys []notification.ReportKey) []notification.ReportKey {
+	critical, err := s.criticalReports.CriticalReportKeys(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to resolve manager-critical reports; counting every report")
+		return cycleKeys
+	}
+	var keys []notification.ReportKey
+	for _, k := range cycleKeys {
+		if containsReportKey(critical, k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return cycleKeys
+	}
+	return keys
+}
+
+// areCriticalReportsConfirmed reports whether answering answeredKey completed the teacher's manager-critical
+// reports for the cycle. It is true only for the answer that completes the set, so the manager is told once.
+func (s *NotificationServiceImpl) areCriticalReportsConfirmed(ctx context.Context, logCtx *logrus.Entry, teacherID int64, cycleID int32, cycleKeys []notification.ReportKey, answeredKey notification.ReportKey) (bool, error) {
+	criticalKeys := s.managerReportKeys(ctx, logCtx, cycleKeys)
+	if !containsReportKey(criticalKeys, answeredKey) {
+		return false, nil
+	}
+	return s.notifRepo.AreAllReportsConfirmedForTeacher(ctx, teacherID, cycleID, criticalKeys)
+}
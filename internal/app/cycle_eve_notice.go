@@ -0,0 +1,47 @@
+// internal/app/cycle_eve_notice.go
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// SendCycleEveNotice tells active teachers that a cycle of the given type starts tomorrow, so they can
+// get their tables in order. Teachers who don't work today are skipped, like reminders. It returns the
+// number of teachers notified; individual send failures are only logged.
+func (s *NotificationServiceImpl) SendCycleEveNotice(ctx context.Context, cycleType notification.CycleType) (int, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":  "SendCycleEveNotice",
+		"cycle_type": cycleType,
+	})
+	logCtx.Info("Sending cycle eve notice")
+
+	activeTeachers, err := s.teacherRepo.ListActive(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list active teachers")
+		return 0, fmt.Errorf("failed to list active teachers: %w", err)
+	}
+
+	today := time.Now().Weekday()
+	notified := 0
+	for _, t := range activeTeachers {
+		teacherLogCtx := logCtx.WithFields(logrus.Fields{"teacher_id": t.ID, "teacher_tg_id": t.TelegramID})
+		if !t.WorkDays.Includes(today) {
+			teacherLogCtx.Info("Today is not a work day for the teacher. Skipping eve notice.")
+			continue
+		}
+		text := fmt.Sprintf("Привет, %s! Завтра (%s) нужно будет подтвердить таблицы. Проверьте, пожалуйста, что всё заполнено.", s.teacherGreetingName(t), cycleType.DisplayName())
+		if err := s.telegramClient.SendMessage(t.TelegramID, text, &telebot.SendOptions{}); err != nil {
+			teacherLogCtx.WithError(err).Warn("Failed to send cycle eve notice")
+			continue
+		}
+		notified++
+	}
+	logCtx.WithFields(logrus.Fields{"notified_count": notified, "active_teachers_count": len(activeTeachers)}).Info("Cycle eve notice sent")
+	return notified, nil
+}
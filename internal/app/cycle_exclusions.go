@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExcludeTeacherFromCycle stops the cycle's questions and reminders for the teacher (e.g. while on leave).
+func (s *NotificationServiceImpl) ExcludeTeacherFromCycle(ctx context.Context, teacherTelegramID int64, cycleID int32) (*teacher.Teacher, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "ExcludeTeacherFromCycle",
+		"teacher_tg_id": teacherTelegramID,
+		"cycle_id":      cycleID,
+	})
+	logCtx.Info("Excluding teacher from cycle")
+
+	t, err := s.getTeacherAndCheckCycle(ctx, logCtx, teacherTelegramID, cycleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifRepo.AddCycleExclusion(ctx, cycleID, t.ID); err != nil {
+		logCtx.WithError(err).Error("Failed to add cycle exclusion")
+		return nil, fmt.Errorf("failed to exclude teacher %d from cycle %d: %w", t.ID, cycleID, err)
+	}
+	logCtx.WithField("teacher_id", t.ID).Info("Teacher excluded from cycle")
+	return t, nil
+}
+
+// IncludeTeacherInCycle undoes ExcludeTeacherFromCycle. Reminders resume with the next sweep.
+func (s *NotificationServiceImpl) IncludeTeacherInCycle(ctx context.Context, teacherTelegramID int64, cycleID int32) (*teacher.Teacher, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "IncludeTeacherInCycle",
+		"teacher_tg_id": teacherTelegramID,
+		"cycle_id":      cycleID,
+	})
+	logCtx.Info("Including teacher back into cycle")
+
+	t, err := s.getTeacherAndCheckCycle(ctx, logCtx, teacherTelegramID, cycleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifRepo.RemoveCycleExclusion(ctx, cycleID, t.ID); err != nil {
+		if err == idb.ErrCycleExclusionNotFound {
+			logCtx.Warn("Teacher was not excluded from cycle")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to remove cycle exclusion")
+		return nil, fmt.Errorf("failed to include teacher %d in cycle %d: %w", t.ID, cycleID, err)
+	}
+	logCtx.WithField("teacher_id", t.ID).Info("Teacher included back into cycle")
+	return t, nil
+}
+
+func (s *NotificationServiceImpl) getTeacherAndCheckCycle(ctx context.Context, logCtx *logrus.Entry, teacherTelegramID int64, cycleID int32) (*teacher.Teacher, error) {
+	t, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get teacher")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID %d: %w", teacherTelegramID, err)
+	}
+	if _, err := s.notifRepo.GetCycleByID(ctx, cycleID); err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+	return t, nil
+}
+
+// isExcludedFromCycle reports whether the teacher is excluded from the cycle.
+// Lookup failures are logged and treated as "not excluded" so a database hiccup doesn't silently drop reminders.
+func (s *NotificationServiceImpl) isExcludedFromCycle(ctx context.Context, logCtx *logrus.Entry, cycleID int32, teacherID int64) bool {
+	excluded, err := s.notifRepo.IsTeacherExcludedFromCycle(ctx, cycleID, teacherID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to check cycle exclusion. Assuming teacher is not excluded.")
+		return false
+	}
+	return excluded
+}
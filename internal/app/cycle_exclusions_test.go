@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+)
+
+func TestExcludedTeacherIsSkippedAtInitiation(t *testing.T) {
+	anna, boris := testTeacher(1, "Анна"), testTeacher(2, "Борис")
+	svc, repo, client := newTestService([]*teacher.Teacher{anna, boris})
+	ctx := context.Background()
+	cycle := &notification.Cycle{CycleDate: testCycleDate, Type: notification.CycleTypeMidMonth, Round: notification.FirstRound}
+	if err := repo.CreateCycle(ctx, cycle); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ExcludeTeacherFromCycle(ctx, boris.TelegramID, cycle.ID); err != nil {
+		t.Fatalf("ExcludeTeacherFromCycle: %v", err)
+	}
+
+	result := initiateTestCycle(t, svc, notification.CycleTypeMidMonth)
+
+	if result.CycleID != cycle.ID {
+		t.Fatalf("initiation used cycle %d, want %d", result.CycleID, cycle.ID)
+	}
+	if got := len(repo.statusesOf(boris.ID, cycle.ID)); got != 0 {
+		t.Errorf("excluded teacher has %d statuses, want none", got)
+	}
+	if got := len(client.messagesTo(boris.TelegramID)); got != 0 {
+		t.Errorf("excluded teacher got %d messages, want none", got)
+	}
+	if got := len(repo.statusesOf(anna.ID, cycle.ID)); got != 2 {
+		t.Errorf("other teacher has %d statuses, want 2", got)
+	}
+	if got := len(client.messagesTo(anna.TelegramID)); got != 1 {
+		t.Errorf("other teacher got %d messages, want the first question", got)
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CycleGaps is the outcome of FindCycleGaps.
+type CycleGaps struct {
+	CycleID    int32
+	Missing    []*teacher.Teacher // Active, non-excluded teachers without any status in the cycle
+	Backfilled int                // Statuses created; only with backfill
+	Sent       []int64            // Teachers asked their first question; only with backfill
+	Failed     []SendFailure
+}
+
+// FindCycleGaps lists active teachers who were never initiated into the cycle, typically because they were added
+// after it started. CheckCycleStatuses only sees teachers with at least one status, so it misses them. With
+// backfill, they get every status of the cycle and, if it is still open, its first question.
+// Simulation cycles only ever target one teacher and are reported without gaps.
+func (s *NotificationServiceImpl) FindCycleGaps(ctx context.Context, cycleID int32, backfill bool) (*CycleGaps, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "FindCycleGaps",
+		"cycle_id":  cycleID,
+		"backfill":  backfill,
+	})
+	logCtx.Info("Looking for active teachers missing from the cycle")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+	result := &CycleGaps{CycleID: cycleID}
+	if cycle.Source == notification.CycleSourceSimulation {
+		return result, nil
+	}
+
+	statuses, err := s.notifRepo.ListReportStatusesByCycle(ctx, cycleID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list report statuses of the cycle")
+		return nil, fmt.Errorf("failed to list statuses of cycle %d: %w", cycleID, err)
+	}
+	initiated := make(map[int64]bool)
+	for _, rs := range statuses {
+		initiated[rs.TeacherID] = true
+	}
+	excludedIDs, err := s.notifRepo.ListExcludedTeacherIDs(ctx, cycleID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list cycle exclusions")
+		return nil, fmt.Errorf("failed to list exclusions of cycle %d: %w", cycleID, err)
+	}
+	for _, id := range excludedIDs {
+		initiated[id] = true // Left out on purpose, not a gap
+	}
+
+	activeTeachers, err := s.teacherRepo.ListActive(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list active teachers")
+		return nil, fmt.Errorf("failed to list active teachers: %w", err)
+	}
+	for _, t := range activeTeachers {
+		if !initiated[t.ID] {
+			result.Missing = append(result.Missing, t)
+		}
+	}
+	logCtx.WithField("missing_teachers", len(result.Missing)).Info("Cycle gaps found")
+	if !backfill || len(result.Missing) == 0 {
+		return result, nil
+	}
+
+	cycleKeys := determineReportsForCycle(cycle.Type)
+	if len(cycleKeys) == 0 {
+		logCtx.Warn("No reports defined for cycle type")
+		return result, nil
+	}
+	var statusesToCreate []*notification.ReportStatus
+	for _, t := range result.Missing {
+		for _, key := range cycleKeys {
+			statusesToCreate = append(statusesToCreate, &notification.ReportStatus{
+				TeacherID:      t.ID,
+				CycleID:        cycleID,
+				ReportKey:      key,
+				Status:         notification.StatusPendingQuestion,
+				LastNotifiedAt: sql.NullTime{},
+			})
+		}
+	}
+	if err := s.notifRepo.BulkCreateReportStatuses(ctx, statusesToCreate); err != nil {
+		logCtx.WithError(err).Error("Failed to create statuses for missing teachers")
+		return nil, fmt.Errorf("failed to backfill teachers into cycle %d: %w", cycleID, err)
+	}
+	result.Backfilled = len(statusesToCreate)
+	logCtx.WithField("count", result.Backfilled).Info("Statuses created for missing teachers")
+
+	if cycle.Status != notification.CycleStatusOpen {
+		logCtx.Info("Cycle is not open. Backfilled teachers are not asked.")
+		return result, nil
+	}
+	for _, t := range result.Missing {
+		if err := s.sendSpecificReportQuestion(ctx, t, cycleID, cycleKeys[0], questionModeInitial); err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: t.ID, Err: err})
+			continue
+		}
+		result.Sent = append(result.Sent, t.ID)
+	}
+	logCtx.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Backfilled teachers asked")
+	return result, nil
+}
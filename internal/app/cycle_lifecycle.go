@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrCycleStatusUnchanged is returned when the cycle already has the requested status.
+	ErrCycleStatusUnchanged = fmt.Errorf("cycle already has this status")
+	ErrCycleAlreadyExists   = fmt.Errorf("a cycle with this date and type already exists")
+	ErrCycleDateInFuture    = fmt.Errorf("cycle date is too far in the future")
+)
+
+// cycleBackfillFutureWindow is how far ahead CreateCycle accepts dates without force, to tolerate timezone skew.
+const cycleBackfillFutureWindow = 24 * time.Hour
+
+// CreateCycle creates an empty first-round cycle for the given date without sending anything,
+// e.g. to backfill history or import data. Dates more than a day ahead are refused unless force is set.
+func (s *NotificationServiceImpl) CreateCycle(ctx context.Context, cycleDate time.Time, cycleType notification.CycleType, force bool) (*notification.Cycle, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":  "CreateCycle",
+		"cycle_type": cycleType,
+		"cycle_date": cycleDate.Format("2006-01-02"),
+		"force":      force,
+	})
+	logCtx.Info("Creating cycle without notifications")
+
+	if !force && cycleDate.After(time.Now().Add(cycleBackfillFutureWindow)) {
+		logCtx.Warn("Refusing to create a cycle dated in the future")
+		return nil, ErrCycleDateInFuture
+	}
+
+	existing, err := s.notifRepo.GetCycleByDateAndType(ctx, cycleDate, cycleType, notification.FirstRound)
+	if err == nil {
+		logCtx.WithField("cycle_id", existing.ID).Warn("Cycle already exists")
+		return existing, ErrCycleAlreadyExists
+	}
+	if err != idb.ErrCycleNotFound {
+		logCtx.WithError(err).Error("Failed to get notification cycle")
+		return nil, fmt.Errorf("failed to get notification cycle: %w", err)
+	}
+
+	cycle := &notification.Cycle{
+		CycleDate: cycleDate,
+		Type:      cycleType,
+		Round:     notification.FirstRound,
+	}
+	if err := s.notifRepo.CreateCycle(ctx, cycle); err != nil {
+		if err == idb.ErrDuplicateCycle {
+			logCtx.WithField("cycle_id", cycle.ID).Warn("Cycle was created concurrently")
+			return cycle, ErrCycleAlreadyExists
+		}
+		logCtx.WithError(err).Error("Failed to create notification cycle")
+		return nil, fmt.Errorf("failed to create notification cycle: %w", err)
+	}
+	logCtx.WithField("cycle_id", cycle.ID).Info("Cycle created; no notifications were sent")
+	return cycle, nil
+}
+
+// SetCycleStatus is the admin override for closing a cycle early or reopening it.
+// Closed cycles are skipped by reminder sweeps; answers to questions already sent are still accepted.
+func (s *NotificationServiceImpl) SetCycleStatus(ctx context.Context, cycleID int32, status notification.CycleStatus) (*notification.Cycle, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "SetCycleStatus",
+		"cycle_id":  cycleID,
+		"status":    status,
+	})
+	logCtx.Info("Changing cycle status")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+	if cycle.Status == status {
+		logCtx.Info("Cycle already has the requested status")
+		return cycle, ErrCycleStatusUnchanged
+	}
+
+	if err := s.notifRepo.UpdateCycleStatus(ctx, cycleID, status); err != nil {
+		logCtx.WithError(err).Error("Failed to update cycle status")
+		return nil, fmt.Errorf("failed to update status of cycle %d: %w", cycleID, err)
+	}
+	cycle.Status = status
+	logCtx.Info("Cycle status changed")
+	if status == notification.CycleStatusClosed {
+		s.recordCycleMetrics(ctx, logCtx, cycle)
+	}
+	return cycle, nil
+}
+
+// closeCycleIfComplete closes an open cycle once none of its reports need work anymore.
+// Failures are only logged: the daily sweep will try again.
+func (s *NotificationServiceImpl) closeCycleIfComplete(ctx context.Context, logCtx *logrus.Entry, cycle *notification.Cycle) {
+	if cycle.Status != notification.CycleStatusOpen {
+		return
+	}
+	cycleLogCtx := logCtx.WithField("cycle_id", cycle.ID)
+
+	outstanding, err := s.notifRepo.CountOutstandingReportStatuses(ctx, cycle.ID)
+	if err != nil {
+		cycleLogCtx.WithError(err).Error("Failed to count outstanding report statuses")
+		return
+	}
+	if outstanding > 0 {
+		cycleLogCtx.WithField("outstanding_count", outstanding).Debug("Cycle still has outstanding reports")
+		return
+	}
+
+	if err := s.notifRepo.UpdateCycleStatus(ctx, cycle.ID, notification.CycleStatusClosed); err != nil {
+		cycleLogCtx.WithError(err).Error("Failed to close completed cycle")
+		return
+	}
+	cycle.Status = notification.CycleStatusClosed
+	cycleLogCtx.Info("All reports of the cycle are done. Cycle closed.")
+	s.recordCycleMetrics(ctx, logCtx, cycle)
+}
+
+// closeCompletedCycles checks every open cycle and closes the ones without remaining work.
+func (s *NotificationServiceImpl) closeCompletedCycles(ctx context.Context, logCtx *logrus.Entry) {
+	openCycles, err := s.notifRepo.ListCyclesByStatus(ctx, notification.CycleStatusOpen)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list open cycles")
+		return
+	}
+	for _, cycle := range openCycles {
+		s.closeCycleIfComplete(ctx, logCtx, cycle)
+	}
+}
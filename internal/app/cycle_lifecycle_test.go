@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+	"time"
+)
+
+// testCycleDate is the date test cycles are initiated for.
+var testCycleDate = time.Date(2024, 5, 15, 10, 0, 0, 0, time.Local)
+
+// initiateTestCycle starts a first-round cycle and fails the test on error.
+func initiateTestCycle(t *testing.T, svc *NotificationServiceImpl, cycleType notification.CycleType) *InitiationResult {
+	t.Helper()
+	result, err := svc.InitiateNotificationProcess(context.Background(), cycleType, testCycleDate, notification.FirstRound)
+	if err != nil {
+		t.Fatalf("InitiateNotificationProcess: %v", err)
+	}
+	return result
+}
+
+// answerYes answers "Yes" to the teacher's status for key, as the teacher's own button press.
+func answerYes(t *testing.T, svc *NotificationServiceImpl, repo *fakeNotifRepo, tc *teacher.Teacher, cycleID int32, key notification.ReportKey) {
+	t.Helper()
+	rs, err := repo.GetReportStatus(context.Background(), tc.ID, cycleID, key)
+	if err != nil {
+		t.Fatalf("GetReportStatus(%s): %v", key, err)
+	}
+	if err := svc.ProcessTeacherYesResponse(context.Background(), rs.ID, tc.TelegramID); err != nil {
+		t.Fatalf("ProcessTeacherYesResponse(%s): %v", key, err)
+	}
+}
+
+func cycleStatus(t *testing.T, repo *fakeNotifRepo, cycleID int32) notification.CycleStatus {
+	t.Helper()
+	c, err := repo.GetCycleByID(context.Background(), cycleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.Status
+}
+
+func TestCycleClosesWhenLastReportIsConfirmed(t *testing.T) {
+	anna, boris := testTeacher(1, "Анна"), testTeacher(2, "Борис")
+	svc, repo, _ := newTestService([]*teacher.Teacher{anna, boris})
+	cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+
+	steps := []struct {
+		teacher *teacher.Teacher
+		key     notification.ReportKey
+		want    notification.CycleStatus
+	}{
+		{teacher: anna, key: notification.ReportKeyTable1Lessons, want: notification.CycleStatusOpen},
+		{teacher: anna, key: notification.ReportKeyTable3Schedule, want: notification.CycleStatusOpen},
+		{teacher: boris, key: notification.ReportKeyTable1Lessons, want: notification.CycleStatusOpen},
+		{teacher: boris, key: notification.ReportKeyTable3Schedule, want: notification.CycleStatusClosed},
+	}
+	for _, step := range steps {
+		answerYes(t, svc, repo, step.teacher, cycleID, step.key)
+		if got := cycleStatus(t, repo, cycleID); got != step.want {
+			t.Fatalf("after %s confirmed %s: cycle status = %s, want %s", step.teacher.FirstName, step.key, got, step.want)
+		}
+	}
+}
+
+func TestCloseCompletedCyclesSkipsCyclesWithWork(t *testing.T) {
+	anna := testTeacher(1, "Анна")
+	svc, repo, _ := newTestService([]*teacher.Teacher{anna})
+	ctx := context.Background()
+
+	busyID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+	done := &notification.Cycle{CycleDate: testCycleDate, Type: notification.CycleTypeEndMonth}
+	if err := repo.CreateCycle(ctx, done); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.closeCompletedCycles(ctx, svc.log)
+
+	if got := cycleStatus(t, repo, busyID); got != notification.CycleStatusOpen {
+		t.Errorf("cycle with unconfirmed reports: status = %s, want OPEN", got)
+	}
+	if got := cycleStatus(t, repo, done.ID); got != notification.CycleStatusClosed {
+		t.Errorf("cycle without outstanding reports: status = %s, want CLOSED", got)
+	}
+}
+
+func TestCloseCycleIfCompleteIgnoresClosedCycles(t *testing.T) {
+	svc, repo, _ := newTestService(nil)
+	ctx := context.Background()
+	cycle := &notification.Cycle{CycleDate: testCycleDate, Type: notification.CycleTypeMidMonth}
+	if err := repo.CreateCycle(ctx, cycle); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateCycleStatus(ctx, cycle.ID, notification.CycleStatusClosed); err != nil {
+		t.Fatal(err)
+	}
+	cycle.Status = notification.CycleStatusClosed
+
+	svc.closeCycleIfComplete(ctx, svc.log, cycle)
+
+	if len(repo.metrics) != 0 {
+		t.Errorf("an already closed cycle was closed again: %d metrics rows recorded", len(repo.metrics))
+	}
+}
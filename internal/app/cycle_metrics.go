@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxTrendCycles caps how many cycles ListCycleTrends returns.
+const maxTrendCycles = 30
+
+// recordCycleMetrics stores the metrics snapshot of a cycle that just closed. Simulation cycles are skipped,
+// like in the other stats. Failures are only logged: closing the cycle must not depend on them.
+func (s *NotificationServiceImpl) recordCycleMetrics(ctx context.Context, logCtx *logrus.Entry, cycle *notification.Cycle) {
+	if cycle.Source == notification.CycleSourceSimulation {
+		return
+	}
+	cycleLogCtx := logCtx.WithField("cycle_id", cycle.ID)
+
+	metrics, err := s.notifRepo.ComputeCycleMetrics(ctx, cycle.ID)
+	if err != nil {
+		cycleLogCtx.WithError(err).Error("Failed to compute cycle metrics")
+		return
+	}
+	metrics.CompletionTime = time.Since(cycle.CreatedAt)
+	if err := s.notifRepo.RecordCycleMetrics(ctx, *metrics); err != nil {
+		cycleLogCtx.WithError(err).Error("Failed to record cycle metrics")
+		return
+	}
+	cycleLogCtx.WithFields(logrus.Fields{
+		"teachers_notified": metrics.TeachersNotified,
+		"confirmations":     metrics.Confirmations,
+		"reminders_sent":    metrics.RemindersSent,
+	}).Info("Cycle metrics recorded")
+}
+
+// ListCycleTrends returns the metrics snapshots of the last limit closed cycles, newest first.
+func (s *NotificationServiceImpl) ListCycleTrends(ctx context.Context, limit int) ([]*notification.CycleMetrics, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "ListCycleTrends",
+		"limit":     limit,
+	})
+	if limit <= 0 || limit > maxTrendCycles {
+		limit = maxTrendCycles
+	}
+	metrics, err := s.notifRepo.ListCycleMetrics(ctx, limit)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list cycle metrics")
+		return nil, fmt.Errorf("failed to list cycle metrics: %w", err)
+	}
+	logCtx.WithField("count", len(metrics)).Info("Listed cycle metrics")
+	return metrics, nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+)
+
+func TestMetricsRecordedWhenCycleCloses(t *testing.T) {
+	anna := testTeacher(1, "Анна")
+	svc, repo, _ := newTestService([]*teacher.Teacher{anna})
+	ctx := context.Background()
+	cycleID := initiateTestCycle(t, svc, notification.CycleTypeMidMonth).CycleID
+
+	// A "No" followed by the 1-hour reminder counts as one reminder sent.
+	first, err := repo.GetReportStatus(ctx, anna.ID, cycleID, notification.ReportKeyTable1Lessons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ProcessTeacherNoResponse(ctx, first.ID, anna.TelegramID); err != nil {
+		t.Fatalf("ProcessTeacherNoResponse: %v", err)
+	}
+	repo.makeRemindersDue()
+	if err := svc.ProcessScheduled1HourReminders(ctx); err != nil {
+		t.Fatalf("ProcessScheduled1HourReminders: %v", err)
+	}
+	if len(repo.metrics) != 0 {
+		t.Fatal("metrics recorded before the cycle closed")
+	}
+	reminded, err := repo.GetReportStatusByID(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reminded.ResponseAttempts != 0 {
+		t.Errorf("after the 1-hour reminder: ResponseAttempts = %d, want 0 so the first-try statistics are unchanged", reminded.ResponseAttempts)
+	}
+
+	answerYes(t, svc, repo, anna, cycleID, notification.ReportKeyTable1Lessons)
+	answerYes(t, svc, repo, anna, cycleID, notification.ReportKeyTable3Schedule)
+
+	if len(repo.metrics) != 1 {
+		t.Fatalf("%d metrics rows recorded on close, want 1", len(repo.metrics))
+	}
+	m := repo.metrics[0]
+	if m.CycleID != cycleID || m.TotalStatuses != 2 || m.Confirmations != 2 || m.TeachersNotified != 1 || m.RemindersSent != 1 {
+		t.Errorf("metrics = %+v, want cycle %d with 2 of 2 confirmed, 1 teacher notified and 1 reminder", m, cycleID)
+	}
+	if m.CompletionTime <= 0 {
+		t.Errorf("CompletionTime = %v, want positive", m.CompletionTime)
+	}
+}
+
+func TestMetricsRecordedWhenAdminClosesCycle(t *testing.T) {
+	tests := []struct {
+		name   string
+		source notification.CycleSource
+		want   int
+	}{
+		{name: "scheduled", source: notification.CycleSourceScheduled, want: 1},
+		{name: "simulation", source: notification.CycleSourceSimulation, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := newTestService(nil)
+			ctx := context.Background()
+			cycle := &notification.Cycle{CycleDate: testCycleDate, Type: notification.CycleTypeMidMonth, Source: tt.source}
+			if err := repo.CreateCycle(ctx, cycle); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.SetCycleStatus(ctx, cycle.ID, notification.CycleStatusClosed); err != nil {
+				t.Fatalf("SetCycleStatus: %v", err)
+			}
+			if got := len(repo.metrics); got != tt.want {
+				t.Errorf("%d metrics rows recorded, want %d", got, tt.want)
+			}
+		})
+	}
+}
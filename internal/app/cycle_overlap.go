@@ -0,0 +1,99 @@
+// internal/app/cycle_overlap.go
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OverlapAction decides what cycle initiation does with teachers who still have unconfirmed reports
+// in another open cycle. The admin is alerted in either case.
+type OverlapAction string
+
+const (
+	OverlapWarn OverlapAction = "warn" // Ask them anyway; they get two parallel question threads
+	OverlapSkip OverlapAction = "skip" // Hold back the new cycle's first question; /retry_failed sends it later
+)
+
+// ParseOverlapAction validates a configured OverlapAction.
+func ParseOverlapAction(raw string) (OverlapAction, error) {
+	switch action := OverlapAction(strings.ToLower(strings.TrimSpace(raw))); action {
+	case OverlapWarn, OverlapSkip:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown overlap action %q (expected warn or skip)", raw)
+	}
+}
+
+// handleCycleOverlap finds teachers with unconfirmed reports in other open cycles, alerts the admin about them
+// and returns the teachers the new cycle's first question should go to. Lookup failures are logged and
+// everyone is asked, as before overlaps were detected.
+func (s *NotificationServiceImpl) handleCycleOverlap(ctx context.Context, logCtx *logrus.Entry, cycle *notification.Cycle, teachers []*teacher.Teacher) []*teacher.Teacher {
+	busyTeacherIDs, err := s.teachersWithOpenWorkElsewhere(ctx, cycle.ID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to check for overlapping cycles. Asking every teacher.")
+		return teachers
+	}
+	if len(busyTeacherIDs) == 0 {
+		return teachers
+	}
+
+	action := s.currentSettings().OverlapAction
+	var toAsk []*teacher.Teacher
+	var names []string
+	for _, t := range teachers {
+		if !busyTeacherIDs[t.ID] {
+			toAsk = append(toAsk, t)
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s (Telegram ID: %d)", s.teacherFullName(t), t.TelegramID))
+		if action != OverlapSkip {
+			toAsk = append(toAsk, t)
+		}
+	}
+	if len(names) == 0 {
+		return teachers
+	}
+	logCtx.WithFields(logrus.Fields{"overlapping_count": len(names), "overlap_action": action}).Warn("Some teachers still have unconfirmed reports in another open cycle")
+
+	if adminID := s.currentSettings().AdminTelegramID; adminID != 0 {
+		msg := fmt.Sprintf("Внимание: у этих преподавателей остались неподтверждённые таблицы в другом открытом цикле, поэтому они получат вопросы по двум циклам одновременно:\n%s", strings.Join(names, "\n"))
+		if action == OverlapSkip {
+			msg = fmt.Sprintf("Внимание: у этих преподавателей остались неподтверждённые таблицы в другом открытом цикле. Первый вопрос нового цикла им не отправлен; отправить его можно командой /retry_failed %d:\n%s", cycle.ID, strings.Join(names, "\n"))
+		}
+		if err := s.telegramClient.SendLongMessage(adminID, msg, nil); err != nil {
+			logCtx.WithError(err).Error("Failed to warn admin about overlapping cycles")
+		}
+	}
+	return toAsk
+}
+
+// teachersWithOpenWorkElsewhere returns the IDs of teachers with unconfirmed reports in open scheduled cycles
+// other than cycleID.
+func (s *NotificationServiceImpl) teachersWithOpenWorkElsewhere(ctx context.Context, cycleID int32) (map[int64]bool, error) {
+	openCycles, err := s.notifRepo.ListCyclesByStatus(ctx, notification.CycleStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open cycles: %w", err)
+	}
+	busy := make(map[int64]bool)
+	for _, c := range openCycles {
+		if c.ID == cycleID || c.Source == notification.CycleSourceSimulation {
+			continue
+		}
+		statuses, err := s.notifRepo.ListReportStatusesByCycle(ctx, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statuses of cycle %d: %w", c.ID, err)
+		}
+		for _, rs := range statuses {
+			if rs.Status != notification.StatusAnsweredYes {
+				busy[rs.TeacherID] = true
+			}
+		}
+	}
+	return busy, nil
+}
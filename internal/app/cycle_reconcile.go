@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReconcileResult summarizes a ReconcileCycle run.
+type ReconcileResult struct {
+	CycleID         int32
+	TeachersChecked int
+	Reconciled      []int64 // Teacher IDs whose missing final messages were sent
+	Failed          []SendFailure
+}
+
+// ReconcileCycle repairs cycles where a teacher confirmed every report but the final manager/teacher
+// messages never fired (e.g. the process died in between). Teachers with a completion marker are skipped,
+// so running it repeatedly never duplicates messages.
+func (s *NotificationServiceImpl) ReconcileCycle(ctx context.Context, cycleID int32) (*ReconcileResult, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "ReconcileCycle",
+		"cycle_id":  cycleID,
+	})
+	logCtx.Info("Reconciling cycle completion")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+
+	statuses, err := s.notifRepo.ListReportStatusesByCycle(ctx, cycleID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list report statuses")
+		return nil, fmt.Errorf("failed to list report statuses for cycle %d: %w", cycleID, err)
+	}
+	var teacherIDs []int64
+	seen := make(map[int64]bool)
+	for _, rs := range statuses {
+		if !seen[rs.TeacherID] {
+			seen[rs.TeacherID] = true
+			teacherIDs = append(teacherIDs, rs.TeacherID)
+		}
+	}
+
+	cycleKeys := determineReportsForCycle(cycle.Type)
+	result := &ReconcileResult{CycleID: cycleID, TeachersChecked: len(teacherIDs)}
+	for _, teacherID := range teacherIDs {
+		teacherLogCtx := logCtx.WithField("teacher_id", teacherID)
+
+		notified, err := s.notifRepo.IsCompletionNotified(ctx, cycleID, teacherID)
+		if err != nil {
+			teacherLogCtx.WithError(err).Error("Failed to check completion marker")
+			result.Failed = append(result.Failed, SendFailure{TeacherID: teacherID, Err: err})
+			continue
+		}
+		if notified {
+			continue
+		}
+		teacherInfo, teacherStatuses, err := s.notifRepo.GetTeacherWithStatuses(ctx, teacherID, cycleID)
+		if err != nil {
+			teacherLogCtx.WithError(err).Error("Failed to get teacher with report statuses")
+			result.Failed = append(result.Failed, SendFailure{TeacherID: teacherID, Err: err})
+			continue
+		}
+		if !allStatusesConfirmed(teacherStatuses, cycleKeys) {
+			continue
+		}
+		teacherLogCtx.Info("Teacher confirmed every report but was never finalized; re-sending final messages")
+		s.sendManagerConfirmation(ctx, teacherInfo, cycle, cycleKeys, cycleKeys)
+		if err := s.sendTeacherFinalReply(teacherInfo, cycle); err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: teacherID, Err: err})
+			continue
+		}
+		s.markCompletionNotified(ctx, teacherLogCtx, cycleID, teacherID)
+		result.Reconciled = append(result.Reconciled, teacherID)
+	}
+
+	s.closeCycleIfComplete(ctx, logCtx, cycle)
+	logCtx.WithFields(logrus.Fields{
+		"teachers_checked": result.TeachersChecked,
+		"reconciled_count": len(result.Reconciled),
+		"failed_count":     len(result.Failed),
+	}).Info("Cycle reconciliation finished")
+	return result, nil
+}
+
+// allStatusesConfirmed reports whether none of the statuses for keys is unconfirmed.
+// Like AreAllReportsConfirmedForTeacher, keys without a status do not count as unconfirmed.
+func allStatusesConfirmed(statuses []*notification.ReportStatus, keys []notification.ReportKey) bool {
+	expected := make(map[notification.ReportKey]bool, len(keys))
+	for _, key := range keys {
+		expected[key] = true
+	}
+	for _, rs := range statuses {
+		if expected[rs.ReportKey] && rs.Status != notification.StatusAnsweredYes {
+			return false
+		}
+	}
+	return true
+}
+
+// finalizeTeacher sends the final reply to a teacher who confirmed every report,
+// records that they were told and closes the cycle if they were the last one outstanding.
+func (s *NotificationServiceImpl) finalizeTeacher(ctx context.Context, logCtx *logrus.Entry, teacherInfo *teacher.Teacher, cycle *notification.Cycle) error {
+	err := s.sendTeacherFinalReply(teacherInfo, cycle)
+	if err == nil {
+		s.markCompletionNotified(ctx, logCtx, cycle.ID, teacherInfo.ID)
+	}
+	s.closeCycleIfComplete(ctx, logCtx, cycle)
+	return err
+}
+
+// markCompletionNotified records that the teacher got the final messages for the cycle.
+// A failure is only logged: at worst a later ReconcileCycle sends the final messages again.
+func (s *NotificationServiceImpl) markCompletionNotified(ctx context.Context, logCtx *logrus.Entry, cycleID int32, teacherID int64) {
+	if err := s.notifRepo.MarkCompletionNotified(ctx, cycleID, teacherID); err != nil {
+		logCtx.WithError(err).WithField("teacher_id", teacherID).Error("Failed to record completion marker")
+	}
+}
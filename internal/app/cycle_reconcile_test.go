@@ -0,0 +1,65 @@
+package app
+
+import (
+	"teacher_notification_bot/internal/domain/notification"
+	"testing"
+)
+
+func TestAllStatusesConfirmed(t *testing.T) {
+	status := func(key notification.ReportKey, s notification.InteractionStatus) *notification.ReportStatus {
+		return &notification.ReportStatus{ReportKey: key, Status: s}
+	}
+	keys := []notification.ReportKey{notification.ReportKeyTable1Lessons, notification.ReportKeyTable3Schedule}
+	tests := []struct {
+		name     string
+		statuses []*notification.ReportStatus
+		want     bool
+	}{
+		{
+			name: "all confirmed",
+			statuses: []*notification.ReportStatus{
+				status(notification.ReportKeyTable1Lessons, notification.StatusAnsweredYes),
+				status(notification.ReportKeyTable3Schedule, notification.StatusAnsweredYes),
+			},
+			want: true,
+		},
+		{
+			name: "one pending",
+			statuses: []*notification.ReportStatus{
+				status(notification.ReportKeyTable1Lessons, notification.StatusAnsweredYes),
+				status(notification.ReportKeyTable3Schedule, notification.StatusPendingQuestion),
+			},
+			want: false,
+		},
+		{
+			name: "awaiting reminder",
+			statuses: []*notification.ReportStatus{
+				status(notification.ReportKeyTable1Lessons, notification.StatusAwaitingReminder1H),
+			},
+			want: false,
+		},
+		{
+			name: "missing status does not count",
+			statuses: []*notification.ReportStatus{
+				status(notification.ReportKeyTable1Lessons, notification.StatusAnsweredYes),
+			},
+			want: true,
+		},
+		{
+			name: "keys outside the cycle are ignored",
+			statuses: []*notification.ReportStatus{
+				status(notification.ReportKeyTable1Lessons, notification.StatusAnsweredYes),
+				status(notification.ReportKeyTable2OTV, notification.StatusPendingQuestion),
+			},
+			want: true,
+		},
+		{name: "no statuses", statuses: nil, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allStatusesConfirmed(tt.statuses, keys); got != tt.want {
+				t.Errorf("allStatusesConfirmed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrReportKeyNotInCycle is returned when a report is not part of the cycle's type.
+var ErrReportKeyNotInCycle = fmt.Errorf("report is not asked in this cycle")
+
+// RenotifyReportKey is for data lost after teachers confirmed it: their ANSWERED_YES statuses of the key are reset
+// and the question is sent again. A closed cycle is reopened so the usual reminders follow up.
+// Inactive and excluded teachers are reset but not messaged.
+func (s *NotificationServiceImpl) RenotifyReportKey(ctx context.Context, cycleID int32, reportKey notification.ReportKey) (*InitiationResult, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":  "RenotifyReportKey",
+		"cycle_id":   cycleID,
+		"report_key": reportKey,
+	})
+	logCtx.Info("Re-notifying report key")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+	inCycle := false
+	for _, key := range determineReportsForCycle(cycle.Type) {
+		if key == reportKey {
+			inCycle = true
+		}
+	}
+	if !inCycle {
+		logCtx.WithField("cycle_type", cycle.Type).Warn("Report key is not part of the cycle type")
+		return nil, ErrReportKeyNotInCycle
+	}
+
+	resetStatuses, err := s.notifRepo.ResetReportKeyStatuses(ctx, cycleID, reportKey)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to reset report statuses")
+		return nil, fmt.Errorf("failed to reset %s statuses in cycle %d: %w", reportKey, cycleID, err)
+	}
+	logCtx.WithField("reset_count", len(resetStatuses)).Info("Report statuses reset")
+
+	result := &InitiationResult{CycleID: cycleID}
+	if len(resetStatuses) == 0 {
+		return result, nil
+	}
+	if cycle.Status != notification.CycleStatusOpen {
+		if err := s.notifRepo.UpdateCycleStatus(ctx, cycleID, notification.CycleStatusOpen); err != nil {
+			logCtx.WithError(err).Error("Failed to reopen cycle. Reminders for the reset statuses will not run.")
+		} else {
+			logCtx.Info("Cycle reopened for the re-notified report")
+		}
+	}
+
+	teacherIDs := make([]int64, len(resetStatuses))
+	for i, rs := range resetStatuses {
+		teacherIDs[i] = rs.TeacherID
+	}
+	teachers, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to get teachers of reset statuses")
+		return nil, fmt.Errorf("failed to get teachers: %w", err)
+	}
+	for _, rs := range resetStatuses {
+		teacherLogCtx := logCtx.WithFields(logrus.Fields{"teacher_id": rs.TeacherID, "report_status_id": rs.ID})
+		t, ok := teachers[rs.TeacherID]
+		if !ok || !t.IsActive {
+			teacherLogCtx.Info("Teacher is missing or inactive. Skipping re-notification.")
+			continue
+		}
+		if s.isExcludedFromCycle(ctx, teacherLogCtx, cycleID, t.ID) {
+			teacherLogCtx.Info("Teacher is excluded from this cycle. Skipping re-notification.")
+			continue
+		}
+		if err := s.sendSpecificReportQuestion(ctx, t, cycleID, reportKey, questionModeInitial); err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: t.ID, Err: err})
+			continue
+		}
+		result.Sent = append(result.Sent, t.ID)
+	}
+
+	logCtx.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Re-notification finished")
+	return result, nil
+}
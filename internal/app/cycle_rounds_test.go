@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+)
+
+func TestTwoRoundsOfTheSameDay(t *testing.T) {
+	anna := testTeacher(1, "Анна")
+	svc, repo, client := newTestService([]*teacher.Teacher{anna})
+	ctx := context.Background()
+
+	first, err := svc.InitiateNotificationProcess(ctx, notification.CycleTypeEndMonth, testCycleDate, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.InitiateNotificationProcess(ctx, notification.CycleTypeEndMonth, testCycleDate, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.CycleID == second.CycleID {
+		t.Fatalf("both rounds resolved to cycle %d", first.CycleID)
+	}
+	for _, cycleID := range []int32{first.CycleID, second.CycleID} {
+		if got := len(repo.statusesOf(anna.ID, cycleID)); got != 3 {
+			t.Errorf("cycle %d: %d statuses, want 3", cycleID, got)
+		}
+	}
+	if got := len(client.messagesTo(anna.TelegramID)); got != 2 {
+		t.Errorf("teacher got %d messages, want one first question per round", got)
+	}
+
+	// Re-initiating a round resolves to its existing cycle.
+	again, err := svc.InitiateNotificationProcess(ctx, notification.CycleTypeEndMonth, testCycleDate, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.CycleID != second.CycleID || again.StatusesCreated != 0 {
+		t.Errorf("re-run of round 2: cycle %d with %d new statuses, want cycle %d with none", again.CycleID, again.StatusesCreated, second.CycleID)
+	}
+
+	// Finishing one round leaves the other open.
+	for _, key := range determineReportsForCycle(notification.CycleTypeEndMonth) {
+		answerYes(t, svc, repo, anna, first.CycleID, key)
+	}
+	if got := cycleStatus(t, repo, first.CycleID); got != notification.CycleStatusClosed {
+		t.Errorf("round 1: status = %s, want CLOSED", got)
+	}
+	if got := cycleStatus(t, repo, second.CycleID); got != notification.CycleStatusOpen {
+		t.Errorf("round 2: status = %s, want OPEN", got)
+	}
+}
+
+func TestInitiationUsesCycleCreatedConcurrently(t *testing.T) {
+	anna := testTeacher(1, "Анна")
+	svc, repo, _ := newTestService([]*teacher.Teacher{anna})
+	ctx := context.Background()
+
+	existing := &notification.Cycle{CycleDate: testCycleDate, Type: notification.CycleTypeMidMonth, Round: notification.FirstRound}
+	if err := repo.CreateCycle(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+	repo.cycleLookupMisses = 1 // The lookup misses the cycle, so the insert hits the unique index
+
+	result := initiateTestCycle(t, svc, notification.CycleTypeMidMonth)
+	if result.CycleID != existing.ID {
+		t.Errorf("initiation used cycle %d, want the existing cycle %d", result.CycleID, existing.ID)
+	}
+	if len(repo.cycles) != 1 {
+		t.Errorf("%d cycles stored, want 1", len(repo.cycles))
+	}
+}
+
+func TestInitiationSendsInListActiveOrder(t *testing.T) {
+	// Namesakes come from ListActive ordered by ID; the first questions must go out in that order.
+	first, second, third := testTeacher(1, "Анна"), testTeacher(2, "Анна"), testTeacher(3, "Анна")
+	svc, _, client := newTestService([]*teacher.Teacher{first, second, third})
+
+	initiateTestCycle(t, svc, notification.CycleTypeMidMonth)
+
+	var order []int64
+	for _, m := range client.sent {
+		order = append(order, m.ChatID)
+	}
+	want := []int64{first.TelegramID, second.TelegramID, third.TelegramID}
+	if !slices.Equal(order, want) {
+		t.Errorf("sent to %v, want %v", order, want)
+	}
+}
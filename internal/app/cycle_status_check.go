@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CycleStatusCheck is the outcome of CheckCycleStatuses.
+type CycleStatusCheck struct {
+	CycleID    int32
+	Missing    map[int64][]notification.ReportKey // Teacher ID -> reports without a status, in question order
+	Backfilled int                                // Statuses created; only with backfill
+	Sent       []int64                            // Teachers asked a backfilled question; only with backfill
+	Failed     []SendFailure
+}
+
+// CheckCycleStatuses repairs cycles whose statuses are incomplete, e.g. after a partially failed bulk insert or a
+// change of the report definitions mid-cycle. Such teachers are never asked the missing reports: the question
+// flow treats a missing status as the next report, but cannot send a question without one.
+func (s *NotificationServiceImpl) CheckCycleStatuses(ctx context.Context, cycleID int32, backfill bool) (*CycleStatusCheck, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "CheckCycleStatuses",
+		"cycle_id":  cycleID,
+		"backfill":  backfill,
+	})
+	logCtx.Info("Checking cycle for missing report statuses")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+	cycleKeys := determineReportsForCycle(cycle.Type)
+
+	missing, err := s.notifRepo.FindMissingStatuses(ctx, cycleID, cycleKeys)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to find missing report statuses")
+		return nil, fmt.Errorf("failed to find missing statuses in cycle %d: %w", cycleID, err)
+	}
+	result := &CycleStatusCheck{CycleID: cycleID, Missing: missing}
+	logCtx.WithField("teachers_with_gaps", len(missing)).Info("Missing report statuses found")
+	if !backfill || len(missing) == 0 {
+		return result, nil
+	}
+
+	var statusesToCreate []*notification.ReportStatus
+	teacherIDs := make([]int64, 0, len(missing))
+	for teacherID, keys := range missing {
+		teacherIDs = append(teacherIDs, teacherID)
+		for _, key := range keys {
+			statusesToCreate = append(statusesToCreate, &notification.ReportStatus{
+				TeacherID:      teacherID,
+				CycleID:        cycleID,
+				ReportKey:      key,
+				Status:         notification.StatusPendingQuestion,
+				LastNotifiedAt: sql.NullTime{},
+			})
+		}
+	}
+	if err := s.notifRepo.BulkCreateReportStatuses(ctx, statusesToCreate); err != nil {
+		logCtx.WithError(err).Error("Failed to backfill missing report statuses")
+		return nil, fmt.Errorf("failed to backfill missing statuses in cycle %d: %w", cycleID, err)
+	}
+	result.Backfilled = len(statusesToCreate)
+	logCtx.WithField("count", result.Backfilled).Info("Missing report statuses backfilled")
+
+	if cycle.Status != notification.CycleStatusOpen {
+		logCtx.Info("Cycle is not open. Backfilled questions are not sent.")
+		return result, nil
+	}
+	teachers, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to get teachers for backfilled questions")
+		return nil, fmt.Errorf("failed to get teachers: %w", err)
+	}
+	for _, teacherID := range teacherIDs {
+		teacherLogCtx := logCtx.WithField("teacher_id", teacherID)
+		t, ok := teachers[teacherID]
+		if !ok || !t.IsActive || s.isExcludedFromCycle(ctx, teacherLogCtx, cycleID, teacherID) {
+			continue
+		}
+		// Only a teacher who got stuck on a missing report is asked now; everyone else reaches
+		// the backfilled reports through the normal question sequence.
+		nextKey, err := s.determineNextReportKey(ctx, teacherID, cycleID, "", cycleKeys)
+		if err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: teacherID, Err: err})
+			continue
+		}
+		if !containsReportKey(missing[teacherID], nextKey) {
+			continue
+		}
+		if err := s.sendSpecificReportQuestion(ctx, t, cycleID, nextKey, questionModeInitial); err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: teacherID, Err: err})
+			continue
+		}
+		result.Sent = append(result.Sent, teacherID)
+	}
+	logCtx.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Backfilled questions sent")
+	return result, nil
+}
@@ -0,0 +1,43 @@
+// internal/app/display.go
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/teacher"
+
+	"github.com/sirupsen/logrus"
+)
+
+// teacherFullName renders the teacher's full name for user-facing messages.
+func (s *NotificationServiceImpl) teacherFullName(t *teacher.Teacher) string {
+	if s.currentSettings().NormalizeNameCasing {
+		return teacher.FormatName(t.FullName())
+	}
+	return t.FullName()
+}
+
+// teacherGreetingName renders the name used to address the teacher directly.
+// A display name set by the admin is shown as entered, without case normalization.
+func (s *NotificationServiceImpl) teacherGreetingName(t *teacher.Teacher) string {
+	name := t.GreetingName()
+	if s.currentSettings().NormalizeNameCasing && name == t.FirstName {
+		return teacher.FormatName(name)
+	}
+	return name
+}
+
+// managerTeacherName renders the teacher's full name for manager messages. When another active teacher has
+// the same name, the Telegram ID is appended so the manager can tell the two apart.
+func (s *NotificationServiceImpl) managerTeacherName(ctx context.Context, logCtx *logrus.Entry, t *teacher.Teacher) string {
+	name := s.teacherFullName(t)
+	namesakes, err := s.teacherRepo.CountActiveWithNameKey(ctx, teacher.NameKey(t.FullName()), t.ID)
+	if err != nil {
+		logCtx.WithError(err).Warn("Failed to count active namesakes for name disambiguation. Using the plain name.")
+		return name
+	}
+	if namesakes > 0 {
+		return fmt.Sprintf("%s (Telegram ID: %d)", name, t.TelegramID)
+	}
+	return name
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+)
+
+func TestManagerTeacherName(t *testing.T) {
+	withLastName := func(id int64, first, last string, active bool) *teacher.Teacher {
+		tc := testTeacher(id, first)
+		tc.LastName = sql.NullString{String: last, Valid: true}
+		tc.IsActive = active
+		return tc
+	}
+	anna := withLastName(1, "Анна", "Иванова", true)
+	annaNamesake := withLastName(2, "анна", " Иванова", true)
+	boris := withLastName(3, "Борис", "Петров", true)
+	formerBoris := withLastName(4, "Борис", "Петров", false)
+	vera := withLastName(5, "Вера", "Смирнова", true)
+	svc, _, _ := newTestService([]*teacher.Teacher{anna, annaNamesake, boris, formerBoris, vera})
+
+	tests := []struct {
+		name    string
+		teacher *teacher.Teacher
+		want    string
+	}{
+		{name: "namesake differs in case and spaces", teacher: anna, want: "Анна Иванова (Telegram ID: 101)"},
+		{name: "other side of the collision", teacher: annaNamesake, want: "анна  Иванова (Telegram ID: 102)"},
+		{name: "inactive namesake does not count", teacher: boris, want: "Борис Петров"},
+		{name: "unique name", teacher: vera, want: "Вера Смирнова"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.managerTeacherName(context.Background(), svc.log, tt.teacher); got != tt.want {
+				t.Errorf("managerTeacherName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
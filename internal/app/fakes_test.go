@@ -0,0 +1,550 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// fakeNotifRepo is an in-memory notification.Repository holding what the tested flows touch.
+// Methods it does not implement panic through the nil embedded interface.
+type fakeNotifRepo struct {
+	notification.Repository
+
+	mu           sync.Mutex
+	nextCycleID  int32
+	nextStatusID int64
+	cycles       map[int32]*notification.Cycle
+	statuses     map[int64]*notification.ReportStatus
+	runLocks     map[string]string // Owner token per held run key
+	lockOwners   int
+	completions  map[int64]bool // Teacher IDs marked completion-notified, across cycles
+	metrics      []notification.CycleMetrics
+	sendFailures map[int64]string // Last recorded send error per status ID
+	proxies      map[int64]int64  // Proxy Telegram ID per status ID confirmed on the teacher's behalf
+	exclusions   map[int32]map[int64]bool
+	reminders    map[int64]int // Same-day reminders sent per status ID
+	// cycleLookupMisses makes that many GetCycleByDateAndType calls miss, as if another initiation
+	// created the cycle between the lookup and the insert.
+	cycleLookupMisses int
+}
+
+func newFakeNotifRepo() *fakeNotifRepo {
+	return &fakeNotifRepo{
+		cycles:       make(map[int32]*notification.Cycle),
+		statuses:     make(map[int64]*notification.ReportStatus),
+		runLocks:     make(map[string]string),
+		completions:  make(map[int64]bool),
+		sendFailures: make(map[int64]string),
+		proxies:      make(map[int64]int64),
+		exclusions:   make(map[int32]map[int64]bool),
+		reminders:    make(map[int64]int),
+	}
+}
+
+func (r *fakeNotifRepo) findCycle(day time.Time, cycleType notification.CycleType, round int, source notification.CycleSource) *notification.Cycle {
+	for _, c := range r.cycles {
+		if c.CycleDate.Equal(notification.CycleDay(day)) && c.Type == cycleType && c.Round == round && c.Source == source {
+			return c
+		}
+	}
+	return nil
+}
+
+func (r *fakeNotifRepo) CreateCycle(_ context.Context, cycle *notification.Cycle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cycle.Source == "" {
+		cycle.Source = notification.CycleSourceScheduled
+	}
+	if cycle.Round == 0 {
+		cycle.Round = notification.FirstRound
+	}
+	cycle.CycleDate = notification.CycleDay(cycle.CycleDate)
+	if cycle.Source == notification.CycleSourceScheduled {
+		if existing := r.findCycle(cycle.CycleDate, cycle.Type, cycle.Round, cycle.Source); existing != nil {
+			*cycle = *existing
+			return idb.ErrDuplicateCycle
+		}
+	}
+	r.nextCycleID++
+	cycle.ID = r.nextCycleID
+	cycle.Status = notification.CycleStatusOpen
+	cycle.CreatedAt = time.Now()
+	stored := *cycle
+	r.cycles[cycle.ID] = &stored
+	return nil
+}
+
+func (r *fakeNotifRepo) GetCycleByID(_ context.Context, id int32) (*notification.Cycle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.cycles[id]
+	if !ok {
+		return nil, idb.ErrCycleNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *fakeNotifRepo) GetCycleByDateAndType(_ context.Context, cycleDate time.Time, cycleType notification.CycleType, round int) (*notification.Cycle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cycleLookupMisses > 0 {
+		r.cycleLookupMisses--
+		return nil, idb.ErrCycleNotFound
+	}
+	c := r.findCycle(cycleDate, cycleType, round, notification.CycleSourceScheduled)
+	if c == nil {
+		return nil, idb.ErrCycleNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *fakeNotifRepo) UpdateCycleStatus(_ context.Context, cycleID int32, status notification.CycleStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.cycles[cycleID]
+	if !ok {
+		return idb.ErrCycleNotFound
+	}
+	c.Status = status
+	return nil
+}
+
+func (r *fakeNotifRepo) ListCyclesByStatus(_ context.Context, status notification.CycleStatus) ([]*notification.Cycle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var cycles []*notification.Cycle
+	for id := int32(1); id <= r.nextCycleID; id++ {
+		if c, ok := r.cycles[id]; ok && c.Status == status {
+			cp := *c
+			cycles = append(cycles, &cp)
+		}
+	}
+	return cycles, nil
+}
+
+func (r *fakeNotifRepo) CountOutstandingReportStatuses(_ context.Context, cycleID int32) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, rs := range r.statuses {
+		if rs.CycleID == cycleID && rs.Status != notification.StatusAnsweredYes {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeNotifRepo) AddCycleExclusion(_ context.Context, cycleID int32, teacherID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.exclusions[cycleID] == nil {
+		r.exclusions[cycleID] = make(map[int64]bool)
+	}
+	r.exclusions[cycleID][teacherID] = true
+	return nil
+}
+
+func (r *fakeNotifRepo) ListExcludedTeacherIDs(_ context.Context, cycleID int32) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []int64
+	for id := range r.exclusions[cycleID] {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *fakeNotifRepo) IsTeacherExcludedFromCycle(_ context.Context, cycleID int32, teacherID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.exclusions[cycleID][teacherID], nil
+}
+
+func (r *fakeNotifRepo) MarkCompletionNotified(_ context.Context, _ int32, teacherID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completions[teacherID] = true
+	return nil
+}
+
+func (r *fakeNotifRepo) RecordSendFailure(_ context.Context, reportStatusID int64, errText string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sendFailures[reportStatusID] = errText
+	return nil
+}
+
+func (r *fakeNotifRepo) RecordRun(context.Context, *notification.RunSummary) error {
+	return nil
+}
+
+func (r *fakeNotifRepo) AcquireRunLock(_ context.Context, runKey string, _ time.Duration) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.runLocks[runKey] != "" {
+		return "", nil
+	}
+	r.lockOwners++
+	owner := fmt.Sprintf("owner-%d", r.lockOwners)
+	r.runLocks[runKey] = owner
+	return owner, nil
+}
+
+func (r *fakeNotifRepo) ReleaseRunLock(_ context.Context, runKey string, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.runLocks[runKey] == owner {
+		delete(r.runLocks, runKey)
+	}
+	return nil
+}
+
+func (r *fakeNotifRepo) ComputeCycleMetrics(_ context.Context, cycleID int32) (*notification.CycleMetrics, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := notification.CycleMetrics{CycleID: cycleID}
+	notified := make(map[int64]bool)
+	for _, rs := range r.statuses {
+		if rs.CycleID != cycleID {
+			continue
+		}
+		m.TotalStatuses++
+		m.RemindersSent += rs.ResponseAttempts + r.reminders[rs.ID]
+		if rs.Status == notification.StatusAnsweredYes {
+			m.Confirmations++
+		}
+		if rs.LastNotifiedAt.Valid {
+			notified[rs.TeacherID] = true
+		}
+	}
+	m.TeachersNotified = len(notified)
+	return &m, nil
+}
+
+func (r *fakeNotifRepo) RecordCycleMetrics(_ context.Context, m notification.CycleMetrics) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+	return nil
+}
+
+func (r *fakeNotifRepo) BulkCreateReportStatuses(_ context.Context, statuses []*notification.ReportStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range statuses {
+		r.nextStatusID++
+		rs.ID = r.nextStatusID
+		rs.CreatedAt = time.Now()
+		rs.UpdatedAt = rs.CreatedAt
+		stored := *rs
+		r.statuses[rs.ID] = &stored
+	}
+	return nil
+}
+
+func (r *fakeNotifRepo) UpdateReportStatus(_ context.Context, rs *notification.ReportStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.statuses[rs.ID]; !ok {
+		return idb.ErrReportStatusNotFound
+	}
+	rs.UpdatedAt = time.Now()
+	stored := *rs
+	r.statuses[rs.ID] = &stored
+	return nil
+}
+
+func (r *fakeNotifRepo) GetReportStatus(_ context.Context, teacherID int64, cycleID int32, reportKey notification.ReportKey) (*notification.ReportStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range r.statuses {
+		if rs.TeacherID == teacherID && rs.CycleID == cycleID && rs.ReportKey == reportKey {
+			cp := *rs
+			return &cp, nil
+		}
+	}
+	return nil, idb.ErrReportStatusNotFound
+}
+
+func (r *fakeNotifRepo) GetReportStatusByID(_ context.Context, id int64) (*notification.ReportStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rs, ok := r.statuses[id]
+	if !ok {
+		return nil, idb.ErrReportStatusNotFound
+	}
+	cp := *rs
+	return &cp, nil
+}
+
+func (r *fakeNotifRepo) ListReportStatusesByCycle(_ context.Context, cycleID int32) ([]*notification.ReportStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var statuses []*notification.ReportStatus
+	for _, rs := range r.statuses {
+		if rs.CycleID == cycleID {
+			cp := *rs
+			statuses = append(statuses, &cp)
+		}
+	}
+	return statuses, nil
+}
+
+func (r *fakeNotifRepo) ListDueReminders(_ context.Context, targetStatus notification.InteractionStatus, remindAtOrBefore time.Time) ([]*notification.ReportStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*notification.ReportStatus
+	for _, rs := range r.statuses {
+		if rs.Status == targetStatus && rs.RemindAt.Valid && !rs.RemindAt.Time.After(remindAtOrBefore) &&
+			r.cycles[rs.CycleID].Status == notification.CycleStatusOpen {
+			cp := *rs
+			due = append(due, &cp)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeNotifRepo) ListReportStatusesByCycleAndTeacher(_ context.Context, cycleID int32, teacherID int64) ([]*notification.ReportStatus, error) {
+	return r.statusesOf(teacherID, cycleID), nil
+}
+
+func (r *fakeNotifRepo) BulkUpdateReportStatuses(ctx context.Context, statuses []*notification.ReportStatus) error {
+	failures := make(map[int64]error)
+	for _, rs := range statuses {
+		if err := r.UpdateReportStatus(ctx, rs); err != nil {
+			failures[rs.ID] = err
+		}
+	}
+	if len(failures) > 0 {
+		return &idb.BulkUpdateError{Failures: failures}
+	}
+	return nil
+}
+
+func (r *fakeNotifRepo) RecordRemindersSent(_ context.Context, reportStatusIDs []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range reportStatusIDs {
+		r.reminders[id]++
+	}
+	return nil
+}
+
+func (r *fakeNotifRepo) MarkProxyConfirmed(_ context.Context, reportStatusIDs []int64, proxyTelegramID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range reportStatusIDs {
+		r.proxies[id] = proxyTelegramID
+	}
+	return nil
+}
+
+// makeRemindersDue moves every scheduled reminder into the past.
+func (r *fakeNotifRepo) makeRemindersDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range r.statuses {
+		if rs.RemindAt.Valid {
+			rs.RemindAt.Time = time.Now().Add(-time.Minute)
+		}
+	}
+}
+
+func (r *fakeNotifRepo) AreAllReportsConfirmedForTeacher(_ context.Context, teacherID int64, cycleID int32, keys []notification.ReportKey) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range r.statuses {
+		if rs.TeacherID == teacherID && rs.CycleID == cycleID && containsReportKey(keys, rs.ReportKey) && rs.Status != notification.StatusAnsweredYes {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// statusesOf returns copies of the statuses of a teacher in a cycle.
+func (r *fakeNotifRepo) statusesOf(teacherID int64, cycleID int32) []*notification.ReportStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var statuses []*notification.ReportStatus
+	for _, rs := range r.statuses {
+		if rs.TeacherID == teacherID && rs.CycleID == cycleID {
+			cp := *rs
+			statuses = append(statuses, &cp)
+		}
+	}
+	return statuses
+}
+
+// fakeTeacherRepo is an in-memory teacher.Repository over a fixed list of active teachers.
+type fakeTeacherRepo struct {
+	teacher.Repository
+
+	mu       sync.Mutex
+	teachers []*teacher.Teacher
+}
+
+func (r *fakeTeacherRepo) ListActive(context.Context) ([]*teacher.Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	active := make([]*teacher.Teacher, 0, len(r.teachers))
+	for _, t := range r.teachers {
+		if t.IsActive {
+			cp := *t
+			active = append(active, &cp)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeTeacherRepo) GetByID(_ context.Context, id int64) (*teacher.Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.teachers {
+		if t.ID == id {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, idb.ErrTeacherNotFound
+}
+
+func (r *fakeTeacherRepo) GetByTelegramID(_ context.Context, telegramID int64) (*teacher.Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.teachers {
+		if t.TelegramID == telegramID {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, idb.ErrTeacherNotFound
+}
+
+func (r *fakeTeacherRepo) Update(_ context.Context, updated *teacher.Teacher) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.teachers {
+		if t.ID == updated.ID {
+			cp := *updated
+			r.teachers[i] = &cp
+			return nil
+		}
+	}
+	return idb.ErrTeacherNotFound
+}
+
+func (r *fakeTeacherRepo) CountActiveWithNameKey(_ context.Context, nameKey string, excludeID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, t := range r.teachers {
+		if t.IsActive && t.ID != excludeID && teacher.NameKey(t.FullName()) == nameKey {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// sentMessage is a message the fake Telegram client was asked to send.
+type sentMessage struct {
+	ChatID int64
+	Text   string
+}
+
+// fakeTelegramClient records sent messages. When blockFirstSend is set, the first SendMessage signals
+// sendStarted and waits until releaseSend is closed. While sendErr is set, every send fails with it.
+type fakeTelegramClient struct {
+	mu             sync.Mutex
+	sent           []sentMessage
+	sendErr        error
+	blockFirstSend bool
+	sendStarted    chan struct{}
+	releaseSend    chan struct{}
+	blocked        bool
+}
+
+func (c *fakeTelegramClient) SendMessage(chatID int64, text string, _ *telebot.SendOptions) error {
+	c.mu.Lock()
+	if c.sendErr != nil {
+		defer c.mu.Unlock()
+		return c.sendErr
+	}
+	block := c.blockFirstSend && !c.blocked
+	c.blocked = c.blocked || block
+	c.sent = append(c.sent, sentMessage{ChatID: chatID, Text: text})
+	c.mu.Unlock()
+	if block {
+		close(c.sendStarted)
+		<-c.releaseSend
+	}
+	return nil
+}
+
+func (c *fakeTelegramClient) SendLongMessage(chatID int64, text string, options *telebot.SendOptions) error {
+	return c.SendMessage(chatID, text, options)
+}
+
+func (c *fakeTelegramClient) SendDocument(int64, string, string, string) error {
+	return nil
+}
+
+// messagesTo returns the texts sent to chatID, in order.
+func (c *fakeTelegramClient) messagesTo(chatID int64) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var texts []string
+	for _, m := range c.sent {
+		if m.ChatID == chatID {
+			texts = append(texts, m.Text)
+		}
+	}
+	return texts
+}
+
+type fixedManager int64
+
+func (m fixedManager) CurrentManagerID(context.Context) (int64, error) { return int64(m), nil }
+
+type allReportsCritical struct{}
+
+func (allReportsCritical) CriticalReportKeys(context.Context) ([]notification.ReportKey, error) {
+	return nil, nil
+}
+
+// testManagerID is the manager's chat in service tests.
+const testManagerID = 900
+
+// newTestService wires a NotificationServiceImpl to the fakes, with logging discarded.
+func newTestService(teachers []*teacher.Teacher) (*NotificationServiceImpl, *fakeNotifRepo, *fakeTelegramClient) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	notifRepo := newFakeNotifRepo()
+	client := &fakeTelegramClient{}
+	svc := NewNotificationServiceImpl(&fakeTeacherRepo{teachers: teachers}, notifRepo, client, logrus.NewEntry(logger),
+		fixedManager(testManagerID), allReportsCritical{}, NotificationSettings{})
+	return svc, notifRepo, client
+}
+
+// testTeacher returns an active teacher who started the bot and wants manager confirmations.
+func testTeacher(id int64, firstName string) *teacher.Teacher {
+	return &teacher.Teacher{
+		ID:                      id,
+		TelegramID:              100 + id,
+		FirstName:               firstName,
+		IsActive:                true,
+		HasStartedBot:           true,
+		NotifyManagerOnComplete: true,
+	}
+}
@@ -0,0 +1,58 @@
+// internal/app/manager_notifications.go
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// ErrManagerNotConfigured is returned by notifyManager when no manager Telegram ID is set.
+// Manager messages are optional, so callers should treat it as "skipped", not as a failure.
+var ErrManagerNotConfigured = fmt.Errorf("manager telegram ID is not configured")
+
+// notifyManager sends a message to the manager. Every manager-facing feature must go through it
+// so the "manager not configured" case is handled in one place.
+// The manager ID is resolved on every call because admins can change it at runtime.
+func (s *NotificationServiceImpl) notifyManager(ctx context.Context, text string) error {
+	managerID, err := s.managers.CurrentManagerID(ctx)
+	if err != nil {
+		s.log.WithField("operation", "notifyManager").WithError(err).Error("Failed to resolve manager Telegram ID")
+		return fmt.Errorf("failed to resolve manager: %w", err)
+	}
+	if managerID == 0 {
+		s.log.WithField("operation", "notifyManager").Warn("Manager Telegram ID not configured. Skipping manager message.")
+		return ErrManagerNotConfigured
+	}
+
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "notifyManager",
+		"manager_tg_id": managerID,
+	})
+	if err := s.telegramClient.SendMessage(managerID, text, &telebot.SendOptions{}); err != nil {
+		logCtx.WithError(err).Error("Failed to send message to manager")
+		return fmt.Errorf("failed to send message to manager: %w", err)
+	}
+	logCtx.Debug("Message sent to manager")
+	return nil
+}
+
+// announceCycleKickoff tells the manager that an initiation run finished, once per run. Failures are only logged.
+func (s *NotificationServiceImpl) announceCycleKickoff(ctx context.Context, logCtx *logrus.Entry, cycle *notification.Cycle, result *InitiationResult, reportCount int) {
+	text := fmt.Sprintf("Запущен цикл %s (%s): уведомлено преподавателей: %d, таблиц в цикле: %d.",
+		cycle.Type.DisplayName(), notification.FormatDate(cycle.CycleDate), len(result.Sent), reportCount)
+	if len(result.Failed) > 0 {
+		text += fmt.Sprintf(" Не доставлено: %d (повторить: /retry_failed %d).", len(result.Failed), cycle.ID)
+	}
+	switch err := s.notifyManager(ctx, text); err {
+	case nil:
+		logCtx.Info("Cycle kickoff announced to manager")
+	case ErrManagerNotConfigured:
+		// Already logged by notifyManager
+	default:
+		logCtx.WithError(err).Error("Failed to announce cycle kickoff to manager")
+	}
+}
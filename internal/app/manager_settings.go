@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/settings"
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+var (
+	ErrInvalidManagerID   = fmt.Errorf("manager telegram ID must be a positive integer")
+	ErrManagerUnreachable = fmt.Errorf("the bot cannot message this user; they must start the bot first")
+)
+
+// ManagerIDSource supplies the current manager Telegram ID (0 when no manager is configured).
+// Read it on every use: the value can change at runtime.
+type ManagerIDSource interface {
+	CurrentManagerID(ctx context.Context) (int64, error)
+}
+
+// ManagerSettingsService stores the manager Telegram ID and manager-critical reports in system settings.
+// Until an admin sets them, the values from the environment are used.
+type ManagerSettingsService struct {
+	settingsRepo      settings.Repository
+	telegramClient    domainTelegram.Client
+	adminTelegramID   int64
+	fallbackManagerID int64
+	// fallbackCriticalKeys are the configured manager-critical reports; nil means every report counts
+	fallbackCriticalKeys []notification.ReportKey
+	log                  *logrus.Entry
+}
+
+func NewManagerSettingsService(sr settings.Repository, tc domainTelegram.Client, adminID int64, fallbackManagerID int64, fallbackCriticalKeys []notification.ReportKey, baseLogger *logrus.Entry) *ManagerSettingsService {
+	return &ManagerSettingsService{
+		settingsRepo:         sr,
+		telegramClient:       tc,
+		adminTelegramID:      adminID,
+		fallbackManagerID:    fallbackManagerID,
+		fallbackCriticalKeys: fallbackCriticalKeys,
+		log:                  baseLogger,
+	}
+}
+
+// CurrentManagerID returns the persisted manager ID, falling back to the configured one.
+func (s *ManagerSettingsService) CurrentManagerID(ctx context.Context) (int64, error) {
+	raw, err := s.settingsRepo.Get(ctx, settings.KeyManagerTelegramID)
+	if err != nil {
+		if err == idb.ErrSettingNotFound {
+			return s.fallbackManagerID, nil
+		}
+		return 0, fmt.Errorf("failed to read manager setting: %w", err)
+	}
+	managerID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("stored manager setting %q is not a number: %w", raw, err)
+	}
+	return managerID, nil
+}
+
+// SetManager makes managerTelegramID the recipient of manager messages. A confirmation is sent
+// to the new manager first, so an ID that never started the bot is rejected instead of saved.
+func (s *ManagerSettingsService) SetManager(ctx context.Context, performingAdminID int64, managerTelegramID int64) error {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "SetManager",
+		"performing_admin_id": performingAdminID,
+		"manager_tg_id":       managerTelegramID,
+	})
+	logCtx.Info("Attempting to change manager")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to change manager")
+		return ErrAdminNotAuthorized
+	}
+	if managerTelegramID <= 0 {
+		logCtx.Warn("Invalid manager Telegram ID")
+		return ErrInvalidManagerID
+	}
+
+	msg := "Вы назначены менеджером: сюда будут приходить уведомления о заполнении таблиц преподавателями."
+	if err := s.telegramClient.SendMessage(managerTelegramID, msg, &telebot.SendOptions{}); err != nil {
+		logCtx.WithError(err).Warn("Failed to reach the new manager")
+		return ErrManagerUnreachable
+	}
+
+	if err := s.settingsRepo.Set(ctx, settings.KeyManagerTelegramID, strconv.FormatInt(managerTelegramID, 10)); err != nil {
+		logCtx.WithError(err).Error("Failed to persist manager setting")
+		return fmt.Errorf("failed to persist manager setting: %w", err)
+	}
+	logCtx.Info("Manager changed successfully")
+	return nil
+}
@@ -4,7 +4,10 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"teacher_notification_bot/internal/domain/notification" // Adjust import path
 	"teacher_notification_bot/internal/domain/teacher"
 	domainTelegram "teacher_notification_bot/internal/domain/telegram" // Import from domain
@@ -15,26 +18,191 @@ import (
 	"gopkg.in/telebot.v3" // For telebot.ReplyMarkup and telebot.SendOptions
 )
 
+// Custom application-level errors for notification service
+var (
+	ErrNoOutstandingReports   = fmt.Errorf("teacher has no outstanding reports in the latest cycle")
+	ErrInvalidSnoozeDuration  = fmt.Errorf("snooze duration must be positive and at most %s", maxSnoozeDuration)
+	ErrReportAlreadyConfirmed = fmt.Errorf("report is already confirmed")
+	// ErrCallbackOwnershipMismatch is returned when a user answers a question that was sent to another teacher.
+	ErrCallbackOwnershipMismatch = fmt.Errorf("report status does not belong to the callback sender")
+	// ErrAnswerWindowExpired is returned when a teacher answers longer than MaxAnswerAge after being asked.
+	ErrAnswerWindowExpired = fmt.Errorf("answer window has expired")
+)
+
+// maxSnoozeDuration caps how far an admin can push a single reminder.
+const maxSnoozeDuration = 7 * 24 * time.Hour
+
 // NotificationService defines the operations for managing the notification process.
 // This is a placeholder for now; its full implementation will come in later tasks.
 type NotificationService interface {
 	// InitiateNotificationProcess starts the notification workflow for a given cycle type.
 	// It will find/create a NotificationCycle, identify target teachers,
 	// create initial TeacherReportStatus entries, and send the first notifications.
-	InitiateNotificationProcess(ctx context.Context, cycleType notification.CycleType, cycleDate time.Time) error
-	ProcessTeacherYesResponse(ctx context.Context, reportStatusID int64) error
-	ProcessTeacherNoResponse(ctx context.Context, reportStatusID int64) error
+	// The returned result lists which teachers were (not) reached; the error is reserved for fatal failures.
+	// round separates several initiations of the same date and type; the scheduler always uses notification.FirstRound.
+	InitiateNotificationProcess(ctx context.Context, cycleType notification.CycleType, cycleDate time.Time, round int) (*InitiationResult, error)
+	// RetryFailedSends re-sends the first question to teachers of the cycle who never received it.
+	RetryFailedSends(ctx context.Context, cycleID int32) (*InitiationResult, error)
+	// RunCycleManually backs /run_cycle. A cycle that was already initiated is not started again: only its
+	// never-delivered first questions are re-sent, so teachers who were already notified get no duplicates.
+	RunCycleManually(ctx context.Context, cycleType notification.CycleType, cycleDate time.Time, round int) (*ManualRunResult, error)
+	// ProcessTeacherYesResponse and ProcessTeacherNoResponse handle answer buttons. senderTelegramID is the
+	// user who pressed the button; ErrCallbackOwnershipMismatch is returned if the status belongs to someone else,
+	// ErrAnswerWindowExpired if the question was asked longer than MaxAnswerAge ago.
+	ProcessTeacherYesResponse(ctx context.Context, reportStatusID int64, senderTelegramID int64) error
+	ProcessTeacherNoResponse(ctx context.Context, reportStatusID int64, senderTelegramID int64) error
 	ProcessScheduled1HourReminders(ctx context.Context) error
-	ProcessNextDayReminders(ctx context.Context) error
+	// ProcessNextDayReminders reminds teachers who left a question unanswered since yesterday. With cycleTypes,
+	// only statuses of those cycle types are swept (e.g. an earlier sweep for time-critical end-of-month cycles).
+	ProcessNextDayReminders(ctx context.Context, cycleTypes ...notification.CycleType) error
+	// ReplayLastQuestion re-sends the teacher's current outstanding question from the latest cycle.
+	ReplayLastQuestion(ctx context.Context, teacherTelegramID int64) (notification.ReportKey, error)
+	// RemindTeacherNow sends a reminder for the teacher's current outstanding report immediately.
+	RemindTeacherNow(ctx context.Context, teacherTelegramID int64) (notification.ReportKey, error)
+	// ExcludeTeacherFromCycle and IncludeTeacherInCycle manage per-cycle exclusions (e.g. a teacher on leave).
+	ExcludeTeacherFromCycle(ctx context.Context, teacherTelegramID int64, cycleID int32) (*teacher.Teacher, error)
+	IncludeTeacherInCycle(ctx context.Context, teacherTelegramID int64, cycleID int32) (*teacher.Teacher, error)
+	// CreateCycle creates an empty cycle (no statuses, no messages), e.g. for backfilling past dates.
+	// It returns ErrCycleDateInFuture for dates more than a day ahead unless force is set.
+	CreateCycle(ctx context.Context, cycleDate time.Time, cycleType notification.CycleType, force bool) (*notification.Cycle, error)
+	// RenotifyReportKey asks every teacher who confirmed the report in the cycle to confirm it again.
+	// It returns ErrReportKeyNotInCycle when the cycle type never asks about the report.
+	RenotifyReportKey(ctx context.Context, cycleID int32, reportKey notification.ReportKey) (*InitiationResult, error)
+	// SetCycleStatus lets an admin close a cycle early or reopen it for reminder sweeps.
+	SetCycleStatus(ctx context.Context, cycleID int32, status notification.CycleStatus) (*notification.Cycle, error)
+	// ListRuns returns the most recent initiation runs, optionally limited to one cycle (cycleID 0 means all).
+	ListRuns(ctx context.Context, cycleID int32) ([]*notification.RunSummary, error)
+	// ListCycleTrends returns the metrics snapshots recorded when cycles closed, for the last limit cycles, newest first.
+	ListCycleTrends(ctx context.Context, limit int) ([]*notification.CycleMetrics, error)
+	// SimulateCycle runs a throwaway cycle for one teacher only, for QA of the whole question/reminder flow.
+	SimulateCycle(ctx context.Context, teacherTelegramID int64) (*notification.Cycle, error)
+	// GetTeacherSummary returns the teacher's unconfirmed reports in the latest open cycle.
+	GetTeacherSummary(ctx context.Context, teacherTelegramID int64) (*TeacherSummary, error)
+	// GetOperationalStats returns a cheap snapshot of current counts for operators.
+	GetOperationalStats(ctx context.Context) (*OperationalStats, error)
+	// ListUpcomingReminders returns reminders scheduled to fire within the given window, soonest first.
+	ListUpcomingReminders(ctx context.Context, within time.Duration) ([]*UpcomingReminder, error)
+	// CheckCycleStatuses finds teachers of the cycle missing statuses for some of its reports. With backfill, the
+	// statuses are created and teachers whose next question is one of them are asked it.
+	CheckCycleStatuses(ctx context.Context, cycleID int32, backfill bool) (*CycleStatusCheck, error)
+	// FindCycleGaps finds active teachers without any status in the cycle (e.g. added after it started). With
+	// backfill, they are initiated into it.
+	FindCycleGaps(ctx context.Context, cycleID int32, backfill bool) (*CycleGaps, error)
+	// CheckIntegrity runs read-only diagnostics over teachers and report statuses.
+	CheckIntegrity(ctx context.Context) (*IntegrityReport, error)
+	// GetResponseRateStats returns aggregate response KPIs for cycles dated within [from, to).
+	GetResponseRateStats(ctx context.Context, from, to time.Time) (*notification.ResponseRateStats, error)
+	// GetTeacherReliability returns how often the teacher confirmed reports without reminders in cycles dated
+	// within [from, to). idb.ErrTeacherNotFound is returned for unknown Telegram IDs.
+	GetTeacherReliability(ctx context.Context, teacherTelegramID int64, from, to time.Time) (*TeacherReliability, error)
+	// ProcessTeacherTextAnswer applies a reply-keyboard "Да"/"Нет" to the sender's latest pending question.
+	// It returns ErrNoPendingQuestion when the sender has nothing to answer.
+	ProcessTeacherTextAnswer(ctx context.Context, senderTelegramID int64, answeredYes bool) error
+	// ProcessTeacherConfirmAllResponse handles the "Всё готово" button: it confirms every outstanding report of
+	// the sender in the cycle. ErrNothingToConfirm is returned when there is nothing left to confirm, and
+	// ErrAnswerWindowExpired when every outstanding report was asked too long ago (see MaxAnswerAge).
+	ProcessTeacherConfirmAllResponse(ctx context.Context, senderTelegramID int64, cycleID int32) error
+	// BulkConfirmForTeachers confirms all outstanding reports of each listed teacher on their behalf and records
+	// proxyTelegramID as the confirmer. Per-teacher failures are reported in the results.
+	BulkConfirmForTeachers(ctx context.Context, proxyTelegramID int64, cycleID int32, teacherTelegramIDs []int64) ([]*BulkConfirmResult, error)
+	// ReconcileCycle re-sends the final "all confirmed" messages to teachers of the cycle who confirmed
+	// everything but never got them.
+	ReconcileCycle(ctx context.Context, cycleID int32) (*ReconcileResult, error)
+	// SnoozeReport schedules a reminder for one status after the given delay, regardless of its current state.
+	SnoozeReport(ctx context.Context, reportStatusID int64, delay time.Duration) (*notification.ReportStatus, error)
+	// ListRecentSendFailures returns statuses whose latest delivery failed at or after since, newest first.
+	ListRecentSendFailures(ctx context.Context, since time.Time) ([]*RecentSendFailure, error)
+	// SendCycleEveNotice tells active teachers that a cycle of the given type starts tomorrow.
+	SendCycleEveNotice(ctx context.Context, cycleType notification.CycleType) (int, error)
+}
+
+// UpcomingReminder is a scheduled reminder together with the name of the teacher it goes to.
+type UpcomingReminder struct {
+	Status      *notification.ReportStatus
+	TeacherName string
+}
+
+// RecentSendFailure is a failed delivery together with the name of the teacher it was meant for.
+type RecentSendFailure struct {
+	Failure     *notification.SendFailure
+	TeacherName string
+}
+
+// TeacherReliability is a teacher's share of reports confirmed on the first ask.
+type TeacherReliability struct {
+	Teacher      *teacher.Teacher
+	FirstTryRate float64 // Percent of TotalReports confirmed without any reminder
+	TotalReports int     // 0 means there is nothing to rate
+}
+
+// IntegrityReport summarizes data inconsistencies found by CheckIntegrity.
+type IntegrityReport struct {
+	DuplicateTelegramIDs []int64                      // Telegram IDs shared by more than one teacher row
+	OrphanedStatuses     []*notification.ReportStatus // Statuses whose teacher or cycle no longer exists
+}
+
+// firstReportKey is the report every cycle starts with.
+const firstReportKey = notification.ReportKeyTable1Lessons
+
+// SendFailure records a teacher whose message could not be delivered.
+type SendFailure struct {
+	TeacherID int64
+	Err       error
+}
+
+// InitiationResult summarizes who was reached by an initiation (or retry) run.
+type InitiationResult struct {
+	CycleID          int32
+	TeachersTargeted int     // Only set by initiation runs
+	StatusesCreated  int     // Only set by initiation runs
+	Sent             []int64 // Teacher IDs that received the first question
+	Failed           []SendFailure
+}
+
+// ManualRunResult is the outcome of RunCycleManually.
+type ManualRunResult struct {
+	*InitiationResult
+	ExistingCycle *notification.Cycle // Set when the cycle already existed and only unsent questions were retried
+}
+
+// NotificationSettings holds the tunable behaviour of the notification service.
+type NotificationSettings struct {
+	NormalizeNameCasing bool  // Title-case teacher names in user-facing messages (stored data is untouched)
+	AdminTelegramID     int64 // Receives operational warnings (e.g. teachers who never started the bot); 0 disables them
+	SandboxRecipientID  int64 // In sandbox mode this user receives every question, so may also answer them
+	CoalesceReminders   bool  // Send one combined reminder per teacher when several of their reports are due in a sweep
+	// ReplyKeyboardAnswers sends "Да"/"Нет" as a reply keyboard instead of inline buttons (for clients that
+	// render inline keyboards poorly). Text answers apply to the teacher's latest pending question.
+	ReplyKeyboardAnswers bool
+	// AnnounceReportCount tells teachers in the first question how many reports the cycle asks about.
+	AnnounceReportCount bool
+	// ReminderAlreadyDoneNote tells teachers in reminders to just press "Да" if the table is already filled in.
+	ReminderAlreadyDoneNote bool
+	// ManagerKickoffAnnouncement sends the manager one summary per cycle initiation (teachers notified, failed sends).
+	ManagerKickoffAnnouncement bool
+	// OverlapAction decides what happens to teachers with unconfirmed reports in another open cycle.
+	OverlapAction OverlapAction
+	// ReminderDelays configures the timed reminder tiers per cycle type; missing types use defaultReminderDelays.
+	ReminderDelays map[notification.CycleType]ReminderDelays
+	// ReminderJitter adds a random delay of up to this much to every scheduled reminder, so answers given at
+	// the same moment (e.g. right after a cycle starts) do not all come due in one sweep. It only ever delays:
+	// a reminder may arrive up to ReminderJitter later than "Напомню через ..." says, never earlier. 0 disables it.
+	ReminderJitter time.Duration
+	// MaxAnswerAge rejects teacher answers given longer than this after the latest question or reminder, leaving
+	// the status unchanged, so confirmations are not accepted arbitrarily late. 0 accepts answers at any time.
+	MaxAnswerAge time.Duration
 }
 
 // NotificationServiceImpl implements the NotificationService interface.
 type NotificationServiceImpl struct {
-	teacherRepo       teacher.Repository
-	notifRepo         notification.Repository
-	telegramClient    domainTelegram.Client // Use the interface from the domain package
-	log               *logrus.Entry
-	managerTelegramID int64 // Added
+	teacherRepo     teacher.Repository
+	notifRepo       notification.Repository
+	telegramClient  domainTelegram.Client // Use the interface from the domain package
+	log             *logrus.Entry
+	managers        ManagerIDSource
+	criticalReports CriticalReportKeySource
+	settingsMu      sync.RWMutex // Guards settings, which UpdateSettings can replace at runtime
+	settings        NotificationSettings
 }
 
 func NewNotificationServiceImpl(
@@ -42,44 +210,104 @@ func NewNotificationServiceImpl(
 	nr notification.Repository,
 	tc domainTelegram.Client, // Use the interface from the domain package
 	baseLogger *logrus.Entry,
-	managerID int64, // Added
+	managers ManagerIDSource,
+	criticalReports CriticalReportKeySource, // Reports that must be confirmed before the manager is told; can change at runtime
+	settings NotificationSettings,
 ) *NotificationServiceImpl {
 	return &NotificationServiceImpl{
-		teacherRepo:       tr,
-		notifRepo:         nr,
-		telegramClient:    tc,
-		log:               baseLogger,
-		managerTelegramID: managerID, // Added
+		teacherRepo:     tr,
+		notifRepo:       nr,
+		telegramClient:  tc,
+		log:             baseLogger,
+		managers:        managers,
+		criticalReports: criticalReports,
+		settings:        settings,
 	}
 }
 
+// UpdateSettings replaces the service settings at runtime (see /reload_config). Work already in progress
+// may still use the previous settings.
+func (s *NotificationServiceImpl) UpdateSettings(settings NotificationSettings) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.settings = settings
+}
+
+// currentSettings returns the settings in effect.
+func (s *NotificationServiceImpl) currentSettings() NotificationSettings {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.settings
+}
+
 // InitiateNotificationProcess starts the notification workflow.
-func (s *NotificationServiceImpl) InitiateNotificationProcess(ctx context.Context, cycleType notification.CycleType, cycleDate time.Time) error {
+// Every run that got as far as resolving its cycle is recorded in the run history.
+// It returns ErrInitiationInProgress while another initiation of the same cycle is running.
+func (s *NotificationServiceImpl) InitiateNotificationProcess(ctx context.Context, cycleType notification.CycleType, cycleDate time.Time, round int) (*InitiationResult, error) {
 	logCtx := s.log.WithFields(logrus.Fields{
-		"operation":  "InitiateNotificationProcess",
-		"cycle_type": cycleType,
-		"cycle_date": cycleDate.Format("2006-01-02"),
+		"operation":   "InitiateNotificationProcess",
+		"cycle_type":  cycleType,
+		"cycle_date":  cycleDate.Format("2006-01-02"),
+		"cycle_round": round,
 	})
 	logCtx.Info("Initiating notification process")
 
+	var result *InitiationResult
+	err := s.withRunLock(ctx, logCtx, runKey(cycleType, cycleDate, round), func() error {
+		startedAt := time.Now()
+		var err error
+		result, err = s.initiateNotificationProcess(ctx, logCtx, cycleType, cycleDate, round)
+		if result != nil {
+			s.recordRun(ctx, logCtx, result, startedAt)
+		}
+		return err
+	})
+	return result, err
+}
+
+// recordRun persists the run summary. Failures are only logged: the history is diagnostic.
+func (s *NotificationServiceImpl) recordRun(ctx context.Context, logCtx *logrus.Entry, result *InitiationResult, startedAt time.Time) {
+	summary := &notification.RunSummary{
+		CycleID:          result.CycleID,
+		TeachersTargeted: result.TeachersTargeted,
+		StatusesCreated:  result.StatusesCreated,
+		MessagesSent:     len(result.Sent),
+		MessagesFailed:   len(result.Failed),
+		StartedAt:        startedAt,
+		FinishedAt:       time.Now(),
+	}
+	if err := s.notifRepo.RecordRun(ctx, summary); err != nil {
+		logCtx.WithError(err).Error("Failed to record notification run")
+		return
+	}
+	logCtx.WithField("run_id", summary.ID).Info("Notification run recorded")
+}
+
+func (s *NotificationServiceImpl) initiateNotificationProcess(ctx context.Context, logCtx *logrus.Entry, cycleType notification.CycleType, cycleDate time.Time, round int) (*InitiationResult, error) {
 	// 1. Find or Create NotificationCycle
-	currentCycle, err := s.notifRepo.GetCycleByDateAndType(ctx, cycleDate, cycleType)
+	currentCycle, err := s.notifRepo.GetCycleByDateAndType(ctx, cycleDate, cycleType, round)
 	if err != nil {
 		if err == idb.ErrCycleNotFound {
 			logCtx.Info("No existing cycle found. Creating new cycle.")
 			newCycle := &notification.Cycle{ // Create as a pointer
 				CycleDate: cycleDate,
 				Type:      cycleType,
+				Round:     round,
 			}
-			if err := s.notifRepo.CreateCycle(ctx, newCycle); err != nil {
+			err := s.notifRepo.CreateCycle(ctx, newCycle)
+			switch err {
+			case nil:
+				logCtx.WithField("cycle_id", newCycle.ID).Info("New notification cycle created")
+			case idb.ErrDuplicateCycle:
+				logCtx.WithField("cycle_id", newCycle.ID).Info("Cycle was created concurrently. Using the existing one.")
+			default:
 				logCtx.WithError(err).Error("Failed to create notification cycle")
-				return fmt.Errorf("failed to create notification cycle: %w", err)
+				return nil, fmt.Errorf("failed to create notification cycle: %w", err)
 			}
 			currentCycle = newCycle // Assign the pointer
-			logCtx.WithField("cycle_id", currentCycle.ID).Info("New notification cycle created")
 		} else {
 			logCtx.WithError(err).Error("Failed to get notification cycle")
-			return fmt.Errorf("failed to get notification cycle: %w", err)
+			return nil, fmt.Errorf("failed to get notification cycle: %w", err)
 		}
 	} else {
 		logCtx.WithField("cycle_id", currentCycle.ID).Info("Existing cycle found.")
@@ -89,19 +317,43 @@ func (s *NotificationServiceImpl) InitiateNotificationProcess(ctx context.Contex
 	activeTeachers, err := s.teacherRepo.ListActive(ctx)
 	if err != nil {
 		logCtx.WithError(err).Error("Failed to list active teachers")
-		return fmt.Errorf("failed to list active teachers: %w", err)
+		return nil, fmt.Errorf("failed to list active teachers: %w", err)
 	}
 	if len(activeTeachers) == 0 {
 		logCtx.Info("No active teachers found. Notification process will not send any messages.")
-		return nil
+		return &InitiationResult{CycleID: currentCycle.ID}, nil
 	}
 	logCtx.WithField("active_teachers_count", len(activeTeachers)).Info("Found active teachers.")
 
+	// 2a. Drop teachers excluded from this cycle; they get neither statuses nor messages.
+	excludedIDs, err := s.notifRepo.ListExcludedTeacherIDs(ctx, currentCycle.ID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list cycle exclusions. Proceeding without exclusions.")
+	} else if len(excludedIDs) > 0 {
+		excluded := make(map[int64]bool, len(excludedIDs))
+		for _, id := range excludedIDs {
+			excluded[id] = true
+		}
+		includedTeachers := activeTeachers[:0]
+		for _, t := range activeTeachers {
+			if excluded[t.ID] {
+				logCtx.WithField("teacher_id", t.ID).Info("Teacher is excluded from this cycle. Skipping.")
+				continue
+			}
+			includedTeachers = append(includedTeachers, t)
+		}
+		activeTeachers = includedTeachers
+		if len(activeTeachers) == 0 {
+			logCtx.Info("All active teachers are excluded from this cycle. No messages will be sent.")
+			return &InitiationResult{CycleID: currentCycle.ID}, nil
+		}
+	}
+
 	// 3. Determine Reports for the Cycle
 	reportsForCycle := determineReportsForCycle(cycleType)
 	if len(reportsForCycle) == 0 {
 		logCtx.Warn("No reports defined for cycle type")
-		return nil
+		return &InitiationResult{CycleID: currentCycle.ID, TeachersTargeted: len(activeTeachers)}, nil
 	}
 
 	// 4. Create Initial TeacherReportStatus Records (Bulk Preferred)
@@ -132,6 +384,7 @@ func (s *NotificationServiceImpl) InitiateNotificationProcess(ctx context.Contex
 		}
 	}
 
+	result := &InitiationResult{CycleID: currentCycle.ID, TeachersTargeted: len(activeTeachers)}
 	if len(statusesToCreate) > 0 {
 		if err := s.notifRepo.BulkCreateReportStatuses(ctx, statusesToCreate); err != nil {
 			logCtx.WithError(err).Error("Failed to bulk create teacher report statuses")
@@ -139,45 +392,150 @@ func (s *NotificationServiceImpl) InitiateNotificationProcess(ctx context.Contex
 			// For now, we log and proceed to send for successfully created/existing statuses.
 		} else {
 			logCtx.WithField("count", len(statusesToCreate)).Info("Successfully created/verified teacher report statuses.")
+			result.StatusesCreated = len(statusesToCreate)
 		}
 	}
 
-	// 5. Send First Notification (Table 1)
-	firstReportKey := notification.ReportKeyTable1Lessons // Always start with Table 1
-	for _, t := range activeTeachers {
-		teacherLogCtx := logCtx.WithFields(logrus.Fields{"teacher_id": t.ID, "teacher_tg_id": t.TelegramID, "report_key": firstReportKey})
-		reportStatus, err := s.notifRepo.GetReportStatus(ctx, t.ID, currentCycle.ID, firstReportKey)
+	// 4a. Telegram rejects messages to users who never started the bot; tell the admin up front.
+	s.warnAdminAboutNotStartedTeachers(logCtx, activeTeachers)
+
+	// 4b. Teachers still busy with another open cycle would get two parallel question threads.
+	teachersToAsk := s.handleCycleOverlap(ctx, logCtx, currentCycle, activeTeachers)
+
+	// 5. Send First Notification. Send order follows ListActive's stable ordering.
+	// On a re-run some teachers are already mid-sequence or done, so each one resumes at their
+	// next unconfirmed report instead of Table 1.
+	for _, t := range teachersToAsk {
+		teacherLogCtx := logCtx.WithFields(logrus.Fields{"teacher_id": t.ID, "teacher_tg_id": t.TelegramID})
+		nextReportKey, err := s.determineNextReportKey(ctx, t.ID, currentCycle.ID, "", reportsForCycle)
+		if err != nil {
+			teacherLogCtx.WithError(err).Error("Could not determine the next report for sending initial notification")
+			continue
+		}
+		if nextReportKey == "" {
+			teacherLogCtx.Info("Initial notification skipped, teacher already confirmed every report.")
+			continue
+		}
+		teacherLogCtx = teacherLogCtx.WithField("report_key", nextReportKey)
+
+		reportStatus, err := s.notifRepo.GetReportStatus(ctx, t.ID, currentCycle.ID, nextReportKey)
 		if err != nil {
 			teacherLogCtx.WithError(err).Error("Could not fetch report status for sending initial notification")
-			continue // Skip this teacher if their initial status record is missing
+			continue // Skip this teacher if their status record is missing
 		}
 		if reportStatus.Status != notification.StatusPendingQuestion {
 			teacherLogCtx.WithField("status", reportStatus.Status).Info("Initial notification skipped, status is not PENDING_QUESTION.")
 			continue
 		}
 
-		teacherName := t.FirstName
-		messageText := fmt.Sprintf("Привет, %s! Заполнена ли Таблица 1: Проведенные уроки (отчёт за текущий период)?", teacherName)
+		if nextReportKey == firstReportKey {
+			err = s.sendInitialQuestion(ctx, teacherLogCtx, t, reportStatus, len(reportsForCycle), now)
+		} else {
+			err = s.resumeReportQuestion(ctx, teacherLogCtx, t, reportStatus)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: t.ID, Err: err})
+			continue
+		}
+		result.Sent = append(result.Sent, t.ID)
+	}
+	logCtx.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Initial notifications dispatched.")
 
-		replyMarkup := &telebot.ReplyMarkup{ResizeKeyboard: true} // Inline keyboard
-		btnYes := replyMarkup.Data("Да", fmt.Sprintf("ans_yes_%d", reportStatus.ID))
-		btnNo := replyMarkup.Data("Нет", fmt.Sprintf("ans_no_%d", reportStatus.ID))
-		replyMarkup.Inline(replyMarkup.Row(btnYes, btnNo))
+	if s.currentSettings().ManagerKickoffAnnouncement {
+		s.announceCycleKickoff(ctx, logCtx, currentCycle, result, len(reportsForCycle))
+	}
+	return result, nil
+}
 
-		err = s.telegramClient.SendMessage(t.TelegramID, messageText, &telebot.SendOptions{ReplyMarkup: replyMarkup, ParseMode: telebot.ModeDefault})
-		if err != nil {
-			teacherLogCtx.WithError(err).Errorf("Failed to send initial notification for Table 1 to Teacher %s", teacherName)
-		} else {
-			teacherLogCtx.Infof("Successfully sent initial notification for Table 1 to Teacher %s", teacherName)
-			reportStatus.LastNotifiedAt = sql.NullTime{Time: now, Valid: true} // Use the 'now' from the beginning of status processing for this batch
-			if errUpdate := s.notifRepo.UpdateReportStatus(ctx, reportStatus); errUpdate != nil {
-				teacherLogCtx.WithError(errUpdate).WithField("report_status_id", reportStatus.ID).Error("Failed to update LastNotifiedAt")
-			}
+// sendInitialQuestion sends the first question of the cycle and records LastNotifiedAt on success.
+// reportCount is the number of reports in the cycle, announced up front when AnnounceReportCount is on.
+func (s *NotificationServiceImpl) sendInitialQuestion(ctx context.Context, teacherLogCtx *logrus.Entry, t *teacher.Teacher, reportStatus *notification.ReportStatus, reportCount int, now time.Time) error {
+	teacherName := t.FirstName
+	messageText := fmt.Sprintf("Привет, %s! Заполнена ли Таблица 1: Проведенные уроки (отчёт за текущий период)?", s.teacherGreetingName(t))
+	if s.currentSettings().AnnounceReportCount && reportCount > 0 {
+		messageText = fmt.Sprintf("Привет, %s! %s\nЗаполнена ли Таблица 1: Проведенные уроки (отчёт за текущий период)?", s.teacherGreetingName(t), reportCountAnnouncement(reportCount))
+	}
+
+	replyMarkup := s.answerMarkup(reportStatus, questionModeInitial)
+
+	err := s.telegramClient.SendMessage(t.TelegramID, messageText, &telebot.SendOptions{ReplyMarkup: replyMarkup, ParseMode: telebot.ModeDefault})
+	if err != nil {
+		if errors.Is(err, domainTelegram.ErrSendCircuitOpen) {
+			// Nothing reached Telegram, so there is no delivery failure to record; the status stays due.
+			teacherLogCtx.WithError(err).Warnf("Initial notification to Teacher %s not sent, Telegram is unreachable", teacherName)
+			return err
+		}
+		if errors.Is(err, telebot.ErrChatNotFound) {
+			teacherLogCtx.WithError(err).Warnf("Teacher %s has not started the bot; Telegram refused the initial notification", teacherName)
+			s.setHasStartedBot(ctx, teacherLogCtx, t, false)
+			s.recordSendFailure(ctx, teacherLogCtx, reportStatus, err)
+			return err
 		}
+		teacherLogCtx.WithError(err).Errorf("Failed to send initial notification for Table 1 to Teacher %s", teacherName)
+		s.recordSendFailure(ctx, teacherLogCtx, reportStatus, err)
+		return err
+	}
+	s.setHasStartedBot(ctx, teacherLogCtx, t, true) // A delivered message proves the chat exists
+	teacherLogCtx.Infof("Successfully sent initial notification for Table 1 to Teacher %s", teacherName)
+	reportStatus.LastNotifiedAt = sql.NullTime{Time: now, Valid: true} // Use the 'now' from the beginning of status processing for this batch
+	if errUpdate := s.notifRepo.UpdateReportStatus(ctx, reportStatus); errUpdate != nil {
+		teacherLogCtx.WithError(errUpdate).WithField("report_status_id", reportStatus.ID).Error("Failed to update LastNotifiedAt")
 	}
 	return nil
 }
 
+// resumeReportQuestion re-asks a teacher who was already past Table 1 when the cycle was initiated again.
+func (s *NotificationServiceImpl) resumeReportQuestion(ctx context.Context, teacherLogCtx *logrus.Entry, t *teacher.Teacher, reportStatus *notification.ReportStatus) error {
+	if err := s.sendReportQuestion(ctx, teacherLogCtx, t, reportStatus, questionModeInitial); err != nil {
+		return err
+	}
+	if errUpdate := s.notifRepo.UpdateReportStatus(ctx, reportStatus); errUpdate != nil {
+		teacherLogCtx.WithError(errUpdate).WithField("report_status_id", reportStatus.ID).Error("Failed to update LastNotifiedAt")
+	}
+	return nil
+}
+
+// recordSendFailure stores a failed delivery on the status for /failures. Failures to record are only logged.
+func (s *NotificationServiceImpl) recordSendFailure(ctx context.Context, logCtx *logrus.Entry, reportStatus *notification.ReportStatus, sendErr error) {
+	if err := s.notifRepo.RecordSendFailure(ctx, reportStatus.ID, sendErr.Error()); err != nil {
+		logCtx.WithError(err).WithField("report_status_id", reportStatus.ID).Error("Failed to record send failure")
+	}
+}
+
+// setHasStartedBot persists the teacher's HasStartedBot flag when it changes. Failures are only logged.
+func (s *NotificationServiceImpl) setHasStartedBot(ctx context.Context, logCtx *logrus.Entry, t *teacher.Teacher, started bool) {
+	if t.HasStartedBot == started {
+		return
+	}
+	t.HasStartedBot = started
+	if err := s.teacherRepo.Update(ctx, t); err != nil {
+		logCtx.WithError(err).WithField("has_started_bot", started).Error("Failed to update teacher HasStartedBot flag")
+	}
+}
+
+// warnAdminAboutNotStartedTeachers lists teachers who have never started the bot, so the admin can
+// ask them to press /start before their first question bounces.
+func (s *NotificationServiceImpl) warnAdminAboutNotStartedTeachers(logCtx *logrus.Entry, teachers []*teacher.Teacher) {
+	var names []string
+	for _, t := range teachers {
+		if !t.HasStartedBot {
+			names = append(names, fmt.Sprintf("%s (Telegram ID: %d)", s.teacherFullName(t), t.TelegramID))
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	logCtx.WithField("not_started_count", len(names)).Warn("Some teachers have not started the bot yet")
+	if s.currentSettings().AdminTelegramID == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("Внимание: эти преподаватели ещё не запускали бота (/start), поэтому Telegram может не доставить им вопросы:\n%s", strings.Join(names, "\n"))
+	if err := s.telegramClient.SendLongMessage(s.currentSettings().AdminTelegramID, msg, nil); err != nil {
+		logCtx.WithError(err).Error("Failed to warn admin about teachers who have not started the bot")
+	}
+}
+
 func determineReportsForCycle(cycleType notification.CycleType) []notification.ReportKey {
 	switch cycleType {
 	case notification.CycleTypeMidMonth:
@@ -196,10 +554,11 @@ func determineReportsForCycle(cycleType notification.CycleType) []notification.R
 	}
 }
 
-func (s *NotificationServiceImpl) ProcessTeacherYesResponse(ctx context.Context, reportStatusID int64) error {
+func (s *NotificationServiceImpl) ProcessTeacherYesResponse(ctx context.Context, reportStatusID int64, senderTelegramID int64) error {
 	logCtx := s.log.WithFields(logrus.Fields{
 		"operation":        "ProcessTeacherYesResponse",
 		"report_status_id": reportStatusID,
+		"sender_tg_id":     senderTelegramID,
 	})
 	logCtx.Info("Processing 'Yes' response")
 
@@ -215,13 +574,35 @@ func (s *NotificationServiceImpl) ProcessTeacherYesResponse(ctx context.Context,
 	}
 	logCtx = logCtx.WithFields(logrus.Fields{"teacher_id": currentReportStatus.TeacherID, "cycle_id": currentReportStatus.CycleID, "report_key": currentReportStatus.ReportKey})
 
+	// Only the teacher the question was sent to may answer it.
+	teacherInfo, err := s.verifyCallbackOwner(ctx, logCtx, currentReportStatus, senderTelegramID)
+	if err != nil {
+		return err
+	}
+
 	// If already answered 'Yes', to prevent reprocessing (e.g. double clicks)
 	if currentReportStatus.Status == notification.StatusAnsweredYes {
 		logCtx.Info("ReportStatusID already marked as ANSWERED_YES. No action needed.")
 		return nil
 	}
+	if s.answerWindowExpired(currentReportStatus, time.Now()) {
+		logCtx.WithField("last_notified_at", currentReportStatus.LastNotifiedAt.Time).Warn("Answer came after the answer window. Rejecting.")
+		return ErrAnswerWindowExpired
+	}
+
+	// 1b. Fetch Cycle details (the teacher was loaded by the ownership check)
+	currentCycle, err := s.notifRepo.GetCycleByID(ctx, currentReportStatus.CycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			s.cancelOrphanedStatus(ctx, logCtx, currentReportStatus)
+			return nil // Acknowledge callback, but nothing to process
+		}
+		logCtx.WithError(err).Error("Failed to get cycle details")
+		return fmt.Errorf("failed to get cycle %d: %w", currentReportStatus.CycleID, err)
+	}
+	logCtx = logCtx.WithField("cycle_type", currentCycle.Type)
 
-	// 1b. Update Status
+	// 1c. Update Status
 	currentReportStatus.Status = notification.StatusAnsweredYes
 	currentReportStatus.UpdatedAt = time.Now() // Service layer can set this before repo call
 	if err := s.notifRepo.UpdateReportStatus(ctx, currentReportStatus); err != nil {
@@ -230,22 +611,18 @@ func (s *NotificationServiceImpl) ProcessTeacherYesResponse(ctx context.Context,
 	}
 	logCtx.Info("ReportStatusID updated to ANSWERED_YES.")
 
-	// 1c. Fetch Teacher and Cycle details
-	teacherInfo, err := s.teacherRepo.GetByID(ctx, currentReportStatus.TeacherID)
-	if err != nil {
-		logCtx.WithError(err).Error("Failed to get teacher details")
-		return fmt.Errorf("failed to get teacher %d: %w", currentReportStatus.TeacherID, err)
-	}
+	// 1d. Determine Next Action
+	allExpectedReportsForCycle := determineReportsForCycle(currentCycle.Type)
 
-	currentCycle, err := s.notifRepo.GetCycleByID(ctx, currentReportStatus.CycleID)
+	// The manager may only care about some reports; they are told as soon as those are all confirmed.
+	criticalConfirmed, err := s.areCriticalReportsConfirmed(ctx, logCtx, teacherInfo.ID, currentCycle.ID, allExpectedReportsForCycle, currentReportStatus.ReportKey)
 	if err != nil {
-		logCtx.WithError(err).Error("Failed to get cycle details")
-		return fmt.Errorf("failed to get cycle %d: %w", currentReportStatus.CycleID, err)
+		logCtx.WithError(err).Error("Failed to check if critical reports confirmed for teacher")
+		return fmt.Errorf("failed to check critical reports confirmed for teacher %d, cycle %d: %w", teacherInfo.ID, currentCycle.ID, err)
+	}
+	if criticalConfirmed {
+		s.sendManagerConfirmation(ctx, teacherInfo, currentCycle, s.managerReportKeys(ctx, logCtx, allExpectedReportsForCycle), allExpectedReportsForCycle)
 	}
-	logCtx = logCtx.WithField("cycle_type", currentCycle.Type)
-
-	// 1d. Determine Next Action
-	allExpectedReportsForCycle := determineReportsForCycle(currentCycle.Type)
 
 	allConfirmed, err := s.notifRepo.AreAllReportsConfirmedForTeacher(ctx, teacherInfo.ID, currentCycle.ID, allExpectedReportsForCycle)
 	if err != nil {
@@ -255,7 +632,7 @@ func (s *NotificationServiceImpl) ProcessTeacherYesResponse(ctx context.Context,
 
 	if allConfirmed {
 		logCtx.Info("All reports confirmed for teacher in this cycle.")
-		return s.sendManagerConfirmationAndTeacherFinalReply(ctx, teacherInfo, currentCycle)
+		return s.finalizeTeacher(ctx, logCtx, teacherInfo, currentCycle)
 	} else {
 		// Determine next report to ask
 		nextReportKey, err := s.determineNextReportKey(ctx, teacherInfo.ID, currentCycle.ID, currentReportStatus.ReportKey, allExpectedReportsForCycle)
@@ -267,19 +644,22 @@ func (s *NotificationServiceImpl) ProcessTeacherYesResponse(ctx context.Context,
 		// Should not happen if allConfirmed is false, but as a safeguard
 		if nextReportKey == "" {
 			logCtx.Warn("All reports appeared confirmed, but determineNextReportKey found no next key. Finalizing.")
-			return s.sendManagerConfirmationAndTeacherFinalReply(ctx, teacherInfo, currentCycle)
+			return s.finalizeTeacher(ctx, logCtx, teacherInfo, currentCycle)
 		}
 
 		logCtx.WithField("next_report_key", nextReportKey).Info("Determined next report to ask.")
-		return s.sendSpecificReportQuestion(ctx, teacherInfo, currentCycle.ID, nextReportKey)
+		return s.sendSpecificReportQuestion(ctx, teacherInfo, currentCycle.ID, nextReportKey, questionModeInitial)
 	}
 }
 
 // determineNextReportKey finds the next report in sequence that isn't 'ANSWERED_YES'.
 // currentAnsweredKey is passed for context but the simpler logic iterates all keys.
+// The keys are walked in question order (see notification.ReportKey.Sequence), whatever order they come in.
 func (s *NotificationServiceImpl) determineNextReportKey(ctx context.Context, teacherID int64, cycleID int32, _ notification.ReportKey, allCycleKeys []notification.ReportKey) (notification.ReportKey, error) {
 	logCtx := s.log.WithFields(logrus.Fields{"operation": "determineNextReportKey", "teacher_id": teacherID, "cycle_id": cycleID})
-	for _, key := range allCycleKeys {
+	orderedKeys := append([]notification.ReportKey(nil), allCycleKeys...)
+	notification.SortReportKeys(orderedKeys)
+	for _, key := range orderedKeys {
 		reportStatus, err := s.notifRepo.GetReportStatus(ctx, teacherID, cycleID, key)
 		if err != nil {
 			if err == idb.ErrReportStatusNotFound {
@@ -297,13 +677,15 @@ func (s *NotificationServiceImpl) determineNextReportKey(ctx context.Context, te
 }
 
 // sendSpecificReportQuestion sends a question for a given report key.
-func (s *NotificationServiceImpl) sendSpecificReportQuestion(ctx context.Context, teacherInfo *teacher.Teacher, cycleID int32, reportKey notification.ReportKey) error {
+// The mode selects between the initial question and the reminder templates.
+func (s *NotificationServiceImpl) sendSpecificReportQuestion(ctx context.Context, teacherInfo *teacher.Teacher, cycleID int32, reportKey notification.ReportKey, mode questionMode) error {
 	logCtx := s.log.WithFields(logrus.Fields{
 		"operation":     "sendSpecificReportQuestion",
 		"teacher_id":    teacherInfo.ID,
 		"teacher_tg_id": teacherInfo.TelegramID,
 		"cycle_id":      cycleID,
 		"report_key":    reportKey,
+		"question_mode": mode.String(),
 	})
 	reportStatus, err := s.notifRepo.GetReportStatus(ctx, teacherInfo.ID, cycleID, reportKey)
 	if err != nil {
@@ -311,76 +693,104 @@ func (s *NotificationServiceImpl) sendSpecificReportQuestion(ctx context.Context
 		return fmt.Errorf("failed to fetch status for %s: %w", reportKey, err)
 	}
 
-	// Ensure the status is PendingQuestion before sending
-	if reportStatus.Status != notification.StatusPendingQuestion {
-		logCtx.WithField("status", reportStatus.Status).Warn("Attempted to send question for status not PENDING_QUESTION")
-		// Maybe update status here if it's something unexpected, but for now, just log and return.
+	if err := s.sendReportQuestion(ctx, logCtx, teacherInfo, reportStatus, mode); err != nil {
+		return err
+	}
+
+	if errUpdate := s.notifRepo.UpdateReportStatus(ctx, reportStatus); errUpdate != nil {
+		logCtx.WithError(errUpdate).WithField("report_status_id", reportStatus.ID).Error("Failed to update LastNotifiedAt/Status after sending question")
+	}
+	return nil
+}
+
+// sendReportQuestion sends the question for an already loaded status and updates it in memory only
+// (LastNotifiedAt and Status). Persisting the change is left to the caller.
+func (s *NotificationServiceImpl) sendReportQuestion(ctx context.Context, logCtx *logrus.Entry, teacherInfo *teacher.Teacher, reportStatus *notification.ReportStatus, mode questionMode) error {
+	reportKey := reportStatus.ReportKey
+
+	// Never re-ask a report that is already confirmed. Reminder statuses are re-asked and reset to pending below.
+	if reportStatus.Status == notification.StatusAnsweredYes {
+		logCtx.WithField("status", reportStatus.Status).Warn("Attempted to send question for an already confirmed report")
 		return fmt.Errorf("cannot send question for status %s", reportStatus.Status)
 	}
 
-	var questionText string
-	switch reportKey {
-	case notification.ReportKeyTable1Lessons:
-		questionText = "Заполнена ли Таблица 1: Проведенные уроки (отчёт за текущий период)?"
-	case notification.ReportKeyTable3Schedule:
-		questionText = "Отлично! Заполнена ли Таблица 3: Расписание (проверка актуальности)?"
-	case notification.ReportKeyTable2OTV:
-		questionText = "Супер! Заполнена ли Таблица 2: Таблица ОТВ (все проведенные уроки за всё время)?"
-	default:
+	questionText, err := buildReportQuestionText(reportKey, mode)
+	if err != nil {
 		logCtx.Error("Unknown report key")
-		return fmt.Errorf("unknown report key: %s", reportKey)
+		return err
 	}
 
-	fullMessage := fmt.Sprintf("Привет, %s! %s", teacherInfo.FirstName, questionText)
+	fullMessage := fmt.Sprintf("Привет, %s! %s", s.teacherGreetingName(teacherInfo), questionText)
+	if mode != questionModeInitial && s.currentSettings().ReminderAlreadyDoneNote {
+		fullMessage += "\n" + reminderAlreadyDoneNote
+	}
 
-	replyMarkup := &telebot.ReplyMarkup{ResizeKeyboard: true}
-	btnYes := replyMarkup.Data("Да", fmt.Sprintf("ans_yes_%d", reportStatus.ID))
-	btnNo := replyMarkup.Data("Нет", fmt.Sprintf("ans_no_%d", reportStatus.ID))
-	replyMarkup.Inline(replyMarkup.Row(btnYes, btnNo))
+	replyMarkup := s.answerMarkup(reportStatus, mode)
 
 	err = s.telegramClient.SendMessage(teacherInfo.TelegramID, fullMessage, &telebot.SendOptions{ReplyMarkup: replyMarkup})
 	if err != nil {
 		logCtx.WithError(err).Errorf("Failed to send question for %s to Teacher %s", reportKey, teacherInfo.FirstName)
+		if !errors.Is(err, domainTelegram.ErrSendCircuitOpen) { // Not a delivery failure; the status stays due
+			s.recordSendFailure(ctx, logCtx, reportStatus, err)
+		}
 		return fmt.Errorf("failed to send question for %s: %w", reportKey, err)
 	}
 	logCtx.Infof("Successfully sent question for %s to Teacher %s", reportKey, teacherInfo.FirstName)
 
 	reportStatus.LastNotifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
 	reportStatus.Status = notification.StatusPendingQuestion // Ensure it's marked as pending
-	if errUpdate := s.notifRepo.UpdateReportStatus(ctx, reportStatus); errUpdate != nil {
-		logCtx.WithError(errUpdate).WithField("report_status_id", reportStatus.ID).Error("Failed to update LastNotifiedAt/Status after sending question")
-	}
 	return nil
 }
 
-// sendManagerConfirmationAndTeacherFinalReply handles the final messages.
-func (s *NotificationServiceImpl) sendManagerConfirmationAndTeacherFinalReply(ctx context.Context, teacherInfo *teacher.Teacher, cycleInfo *notification.Cycle) error {
+// sendManagerConfirmation tells the manager that the teacher confirmed the reports they care about.
+// Failures are logged only: the teacher's flow must not depend on the manager message.
+func (s *NotificationServiceImpl) sendManagerConfirmation(ctx context.Context, teacherInfo *teacher.Teacher, cycleInfo *notification.Cycle, criticalKeys []notification.ReportKey, cycleKeys []notification.ReportKey) {
 	logCtx := s.log.WithFields(logrus.Fields{
-		"operation":     "sendManagerConfirmationAndTeacherFinalReply",
+		"operation":     "sendManagerConfirmation",
 		"teacher_id":    teacherInfo.ID,
 		"teacher_tg_id": teacherInfo.TelegramID,
 		"cycle_id":      cycleInfo.ID,
 	})
-	if s.managerTelegramID != 0 {
-		managerLogCtx := logCtx.WithField("manager_tg_id", s.managerTelegramID)
-		teacherFullName := teacherInfo.FirstName
-		if teacherInfo.LastName.Valid {
-			teacherFullName += " " + teacherInfo.LastName.String
-		}
-		managerMessage := fmt.Sprintf("Преподаватель %s подтвердил(а) все таблицы для цикла %s (%s).", teacherFullName, cycleInfo.Type, cycleInfo.CycleDate.Format("2006-01-02"))
+	if !teacherInfo.NotifyManagerOnComplete {
+		logCtx.Info("Teacher is opted out of manager confirmations. Skipping manager message.")
+		return
+	}
 
-		err := s.telegramClient.SendMessage(s.managerTelegramID, managerMessage, &telebot.SendOptions{})
-		if err != nil {
-			managerLogCtx.WithError(err).Errorf("Failed to send confirmation to manager for teacher %s", teacherFullName)
-		} else {
-			managerLogCtx.Infof("Confirmation sent to manager for teacher %s.", teacherFullName)
+	teacherFullName := s.managerTeacherName(ctx, logCtx, teacherInfo)
+	confirmed := "все таблицы"
+	if len(criticalKeys) < len(cycleKeys) {
+		titles := make([]string, len(criticalKeys))
+		for i, k := range criticalKeys {
+			titles[i] = ReportTitle(k)
 		}
-	} else {
-		logCtx.Warn("Manager Telegram ID not configured. Cannot send manager confirmation.")
+		confirmed = "ключевые таблицы (" + strings.Join(titles, ", ") + ")"
+	}
+	managerMessage := fmt.Sprintf("Преподаватель %s подтвердил(а) %s для цикла %s (%s).", teacherFullName, confirmed, cycleInfo.Type.DisplayName(), notification.FormatDate(cycleInfo.CycleDate))
+
+	switch err := s.notifyManager(ctx, managerMessage); err {
+	case nil:
+		logCtx.Infof("Confirmation sent to manager for teacher %s.", teacherFullName)
+	case ErrManagerNotConfigured:
+		// Already logged by notifyManager.
+	default:
+		logCtx.WithError(err).Errorf("Failed to send confirmation to manager for teacher %s", teacherFullName)
 	}
+}
 
+// sendTeacherFinalReply thanks the teacher once every report of the cycle is confirmed.
+func (s *NotificationServiceImpl) sendTeacherFinalReply(teacherInfo *teacher.Teacher, cycleInfo *notification.Cycle) error {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "sendTeacherFinalReply",
+		"teacher_id":    teacherInfo.ID,
+		"teacher_tg_id": teacherInfo.TelegramID,
+		"cycle_id":      cycleInfo.ID,
+	})
 	teacherReplyMessage := "Спасибо! Все таблицы подтверждены."
-	err := s.telegramClient.SendMessage(teacherInfo.TelegramID, teacherReplyMessage, &telebot.SendOptions{})
+	opts := &telebot.SendOptions{}
+	if s.currentSettings().ReplyKeyboardAnswers {
+		opts.ReplyMarkup = &telebot.ReplyMarkup{RemoveKeyboard: true} // Nothing left to answer
+	}
+	err := s.telegramClient.SendMessage(teacherInfo.TelegramID, teacherReplyMessage, opts)
 	if err != nil {
 		logCtx.WithError(err).Errorf("Failed to send final confirmation to teacher %s", teacherInfo.FirstName)
 		return fmt.Errorf("failed to send final reply to teacher: %w", err)
@@ -389,10 +799,33 @@ func (s *NotificationServiceImpl) sendManagerConfirmationAndTeacherFinalReply(ct
 	return nil
 }
 
-func (s *NotificationServiceImpl) ProcessTeacherNoResponse(ctx context.Context, reportStatusID int64) error {
+// answerWindowExpired reports whether an answer to rs at now comes later than MaxAnswerAge after the latest
+// question or reminder. Statuses that were never delivered have no window.
+func (s *NotificationServiceImpl) answerWindowExpired(rs *notification.ReportStatus, now time.Time) bool {
+	maxAge := s.currentSettings().MaxAnswerAge
+	return maxAge > 0 && rs.LastNotifiedAt.Valid && now.Sub(rs.LastNotifiedAt.Time) > maxAge
+}
+
+// verifyCallbackOwner loads the status's teacher and checks that the callback came from them,
+// so a forwarded or crafted callback can't answer another teacher's question.
+func (s *NotificationServiceImpl) verifyCallbackOwner(ctx context.Context, logCtx *logrus.Entry, rs *notification.ReportStatus, senderTelegramID int64) (*teacher.Teacher, error) {
+	teacherInfo, err := s.teacherRepo.GetByID(ctx, rs.TeacherID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to get teacher details")
+		return nil, fmt.Errorf("failed to get teacher %d: %w", rs.TeacherID, err)
+	}
+	if teacherInfo.TelegramID != senderTelegramID && !(s.currentSettings().SandboxRecipientID != 0 && senderTelegramID == s.currentSettings().SandboxRecipientID) {
+		logCtx.WithField("owner_tg_id", teacherInfo.TelegramID).Warn("Callback sender does not own the report status. Rejecting.")
+		return nil, ErrCallbackOwnershipMismatch
+	}
+	return teacherInfo, nil
+}
+
+func (s *NotificationServiceImpl) ProcessTeacherNoResponse(ctx context.Context, reportStatusID int64, senderTelegramID int64) error {
 	logCtx := s.log.WithFields(logrus.Fields{
 		"operation":        "ProcessTeacherNoResponse",
 		"report_status_id": reportStatusID,
+		"sender_tg_id":     senderTelegramID,
 	})
 	logCtx.Info("Processing 'No' response")
 
@@ -408,21 +841,34 @@ func (s *NotificationServiceImpl) ProcessTeacherNoResponse(ctx context.Context,
 	}
 	logCtx = logCtx.WithFields(logrus.Fields{"teacher_id": currentReportStatus.TeacherID, "cycle_id": currentReportStatus.CycleID, "report_key": currentReportStatus.ReportKey})
 
+	// Only the teacher the question was sent to may answer it. The teacher is also needed for the confirmation message.
+	teacherInfo, err := s.verifyCallbackOwner(ctx, logCtx, currentReportStatus, senderTelegramID)
+	if err != nil {
+		return err
+	}
+
 	// If already awaiting reminder (e.g. from a previous 'No' click), prevent reprocessing.
 	if currentReportStatus.Status == notification.StatusAwaitingReminder1H {
 		logCtx.Info("ReportStatusID already in AWAITING_REMINDER_1H. Ignoring duplicate 'No' response.")
 		return nil
 	}
+	if s.answerWindowExpired(currentReportStatus, time.Now()) {
+		logCtx.WithField("last_notified_at", currentReportStatus.LastNotifiedAt.Time).Warn("Answer came after the answer window. Rejecting.")
+		return ErrAnswerWindowExpired
+	}
 
-	// Fetch Teacher details for sending confirmation message
-	teacherInfo, err := s.teacherRepo.GetByID(ctx, currentReportStatus.TeacherID)
-	if err != nil {
-		logCtx.WithError(err).Error("Failed to get teacher details")
-		return fmt.Errorf("failed to get teacher %d: %w", currentReportStatus.TeacherID, err)
+	// A reminder for a status whose cycle is gone would be swept forever; cancel it instead.
+	if s.isCycleMissing(ctx, logCtx, currentReportStatus.CycleID, make(map[int32]bool)) {
+		s.cancelOrphanedStatus(ctx, logCtx, currentReportStatus)
+		return nil
 	}
 
-	// Calculate reminder time (1 hour from now)
-	reminderTime := time.Now().Add(1 * time.Hour)
+	// Calculate reminder time; the delay depends on the cycle type unless the teacher has their own
+	reminderDelay, overridden := teacherInfo.ReminderDelay()
+	if !overridden {
+		reminderDelay = s.reminderDelaysForCycle(ctx, logCtx, currentReportStatus.CycleID).AfterNo
+	}
+	reminderTime := time.Now().Add(reminderDelay + s.reminderJitter())
 
 	// 1b. Update Status and set reminder time
 	currentReportStatus.Status = notification.StatusAwaitingReminder1H
@@ -438,7 +884,7 @@ func (s *NotificationServiceImpl) ProcessTeacherNoResponse(ctx context.Context,
 	logCtx.WithField("remind_at", currentReportStatus.RemindAt.Time.Format(time.RFC3339)).Info("ReportStatusID updated to AWAITING_REMINDER_1H.")
 
 	// Send confirmation message to teacher
-	teacherMessage := "Понял(а). Напомню через час. Если заполните таблицу раньше, это сообщение можно будет проигнорировать."
+	teacherMessage := fmt.Sprintf("Понял(а). Напомню через %s. Если заполните таблицу раньше, это сообщение можно будет проигнорировать.", formatDelay(reminderDelay))
 	err = s.telegramClient.SendMessage(teacherInfo.TelegramID, teacherMessage, nil)
 	if err != nil {
 		logCtx.WithError(err).WithField("teacher_tg_id", teacherInfo.TelegramID).Errorf("Failed to send 'No' response confirmation to teacher %s", teacherInfo.FirstName)
@@ -449,23 +895,48 @@ func (s *NotificationServiceImpl) ProcessTeacherNoResponse(ctx context.Context,
 	return nil
 }
 
+// reminderTier describes one of the timed reminder stages handled by ProcessScheduled1HourReminders.
+type reminderTier struct {
+	name   string // For logs, e.g. "1-hour"
+	status notification.InteractionStatus
+	mode   questionMode
+}
+
+var (
+	reminderTier1H = reminderTier{name: "1-hour", status: notification.StatusAwaitingReminder1H, mode: questionModeReminder1H}
+	reminderTier4H = reminderTier{name: "4-hour", status: notification.StatusAwaitingReminder4H, mode: questionModeReminder4H}
+)
+
+// ProcessScheduled1HourReminders sends all due timed reminders: the 1-hour tier and, when enabled,
+// the intermediate 4-hour tier. 4-hour rows are processed even if the tier was disabled since they were scheduled.
 func (s *NotificationServiceImpl) ProcessScheduled1HourReminders(ctx context.Context) error {
 	logCtx := s.log.WithField("operation", "ProcessScheduled1HourReminders")
-	logCtx.Info("Processing scheduled 1-hour reminders...")
 	now := time.Now()
 
-	dueStatuses, err := s.notifRepo.ListDueReminders(ctx, notification.StatusAwaitingReminder1H, now)
+	if err := s.processDueReminderTier(ctx, logCtx, reminderTier1H, now); err != nil {
+		return err
+	}
+	return s.processDueReminderTier(ctx, logCtx, reminderTier4H, now)
+}
+
+func (s *NotificationServiceImpl) processDueReminderTier(ctx context.Context, logCtx *logrus.Entry, tier reminderTier, now time.Time) error {
+	logCtx = logCtx.WithField("reminder_tier", tier.name)
+	logCtx.Infof("Processing scheduled %s reminders...", tier.name)
+
+	dueStatuses, err := s.notifRepo.ListDueReminders(ctx, tier.status, now)
 	if err != nil {
-		logCtx.WithError(err).Error("Failed to list due 1-hour reminders")
-		return fmt.Errorf("failed to list due 1-hour reminders: %w", err)
+		logCtx.WithError(err).Errorf("Failed to list due %s reminders", tier.name)
+		return fmt.Errorf("failed to list due %s reminders: %w", tier.name, err)
 	}
 
 	if len(dueStatuses) == 0 {
-		logCtx.Info("No 1-hour reminders due at this time.")
+		logCtx.Infof("No %s reminders due at this time.", tier.name)
 		return nil
 	}
-	logCtx.WithField("due_statuses_count", len(dueStatuses)).Info("Found status(es) needing a 1-hour reminder.")
+	logCtx.WithField("due_statuses_count", len(dueStatuses)).Infof("Found status(es) needing a %s reminder.", tier.name)
 
+	var due []*dueReminder
+	missingCycles := make(map[int32]bool)
 	for _, rs := range dueStatuses {
 		reminderLogCtx := logCtx.WithFields(logrus.Fields{
 			"report_status_id": rs.ID,
@@ -473,45 +944,90 @@ func (s *NotificationServiceImpl) ProcessScheduled1HourReminders(ctx context.Con
 			"cycle_id":         rs.CycleID,
 			"report_key":       rs.ReportKey,
 		})
-		reminderLogCtx.Info("Processing 1-hour reminder")
+		reminderLogCtx.Info("Processing reminder")
 
 		teacherInfo, err := s.teacherRepo.GetByID(ctx, rs.TeacherID)
 		if err != nil {
-			reminderLogCtx.WithError(err).Error("Failed to get teacher for 1-hour reminder")
+			reminderLogCtx.WithError(err).Error("Failed to get teacher for reminder")
 			continue // Skip this reminder
 		}
 
-		// Re-send the specific question. This function also updates LastNotifiedAt and sets status to StatusPendingQuestion.
-		err = s.sendSpecificReportQuestion(ctx, teacherInfo, rs.CycleID, rs.ReportKey)
+		if s.isExcludedFromCycle(ctx, reminderLogCtx, rs.CycleID, rs.TeacherID) {
+			reminderLogCtx.Info("Teacher is excluded from this cycle. Skipping reminder.")
+			continue
+		}
+
+		// The teacher may have answered (or an admin snoozed the report) since the sweep selected this row.
+		currentRs, err := s.notifRepo.GetReportStatusByID(ctx, rs.ID)
 		if err != nil {
-			reminderLogCtx.WithError(err).Error("Failed to send 1-hour reminder (re-ask question)")
-			// If sendSpecificReportQuestion fails, the status in DB should still be AWAITING_REMINDER_1H
-			// and RemindAt should still be set, so it will be picked up next time.
+			reminderLogCtx.WithError(err).Error("Failed to re-validate status before reminder")
+			continue
+		}
+		if !isStillDueForReminder(currentRs, tier.status, now) {
+			reminderLogCtx.WithField("current_status", currentRs.Status).Info("Status changed since selection. Skipping reminder.")
+			continue
+		}
+		if s.isCycleMissing(ctx, reminderLogCtx, currentRs.CycleID, missingCycles) {
+			s.cancelOrphanedStatus(ctx, reminderLogCtx, currentRs)
 			continue
 		}
 
-		// After successfully sending the reminder, clear the RemindAt timestamp
-		// Fetch the latest status again as sendSpecificReportQuestion modified it.
-		updatedRs, fetchErr := s.notifRepo.GetReportStatusByID(ctx, rs.ID)
-		if fetchErr != nil {
-			reminderLogCtx.WithError(fetchErr).Error("Failed to re-fetch ReportStatusID after sending 1-hour reminder. RemindAt might not be cleared.")
+		if !teacherInfo.WorkDays.Includes(now.Weekday()) {
+			s.deferReminderToNextWorkDay(ctx, reminderLogCtx, teacherInfo, rs, now)
 			continue
 		}
 
-		updatedRs.RemindAt = sql.NullTime{Valid: false} // Clear the reminder time
-		// The status is already PENDING_QUESTION due to sendSpecificReportQuestion.
-		// We are just ensuring RemindAt is cleared.
-		if errUpdate := s.notifRepo.UpdateReportStatus(ctx, updatedRs); errUpdate != nil {
-			reminderLogCtx.WithError(errUpdate).Error("Failed to update ReportStatusID to clear RemindAt after 1-hour reminder")
-		} else {
-			reminderLogCtx.WithField("new_status", updatedRs.Status).Info("Successfully sent 1-hour reminder and updated status. RemindAt cleared.")
+		due = append(due, &dueReminder{teacher: teacherInfo, status: currentRs, logCtx: reminderLogCtx})
+	}
+
+	for _, batch := range s.batchReminders(due) {
+		// sendReminderBatch sets LastNotifiedAt and PENDING_QUESTION in memory on success.
+		if err := s.sendReminderBatch(ctx, batch, tier.mode); err != nil {
+			// The statuses in DB keep their awaiting status and RemindAt, so they will be picked up next time.
+			continue
+		}
+		// Counted apart from ResponseAttempts, which the first-try statistics read (see RecordRemindersSent).
+		sentIDs := make([]int64, len(batch))
+		for i, r := range batch {
+			sentIDs[i] = r.status.ID
+		}
+		if err := s.notifRepo.RecordRemindersSent(ctx, sentIDs); err != nil {
+			// The reminders went out; only their count in the cycle metrics is missing.
+			logCtx.WithError(err).WithField("report_status_ids", sentIDs).Errorf("Failed to count sent %s reminders", tier.name)
+		}
+		for _, r := range batch {
+			var secondTierDelay time.Duration
+			if tier == reminderTier1H {
+				secondTierDelay = s.reminderDelaysForCycle(ctx, r.logCtx, r.status.CycleID).AfterFirstReminder
+			}
+			if secondTierDelay > 0 {
+				// Escalate to the intermediate tier; the next-day sweep still follows if the teacher stays silent.
+				r.status.Status = notification.StatusAwaitingReminder4H
+				r.status.RemindAt = sql.NullTime{Time: now.Add(secondTierDelay + s.reminderJitter()), Valid: true}
+			} else {
+				// The status is already PENDING_QUESTION after sending; just clear the reminder time.
+				r.status.RemindAt = sql.NullTime{Valid: false}
+			}
+			if errUpdate := s.notifRepo.UpdateReportStatus(ctx, r.status); errUpdate != nil {
+				r.logCtx.WithError(errUpdate).Error("Failed to update ReportStatusID after reminder")
+			} else {
+				r.logCtx.WithFields(logrus.Fields{
+					"new_status": r.status.Status,
+					"remind_at":  r.status.RemindAt.Time,
+				}).Info("Successfully sent reminder and updated status.")
+			}
 		}
 	}
 	return nil
 }
 
-func (s *NotificationServiceImpl) ProcessNextDayReminders(ctx context.Context) error {
-	logCtx := s.log.WithField("operation", "ProcessNextDayReminders")
+// isStillDueForReminder re-checks a freshly loaded status against the conditions ListDueReminders selected it by.
+func isStillDueForReminder(rs *notification.ReportStatus, awaitingStatus notification.InteractionStatus, now time.Time) bool {
+	return rs.Status == awaitingStatus && rs.RemindAt.Valid && !rs.RemindAt.Time.After(now)
+}
+
+func (s *NotificationServiceImpl) ProcessNextDayReminders(ctx context.Context, cycleTypes ...notification.CycleType) error {
+	logCtx := s.log.WithFields(logrus.Fields{"operation": "ProcessNextDayReminders", "cycle_types": cycleTypes})
 	logCtx.Info("Processing scheduled next-day reminders...")
 
 	now := time.Now()
@@ -531,9 +1047,10 @@ func (s *NotificationServiceImpl) ProcessNextDayReminders(ctx context.Context) e
 	statusesToConsider := []notification.InteractionStatus{
 		notification.StatusPendingQuestion,
 		notification.StatusAwaitingReminder1H,
+		notification.StatusAwaitingReminder4H,
 	}
 
-	stalledStatuses, err := s.notifRepo.ListStalledStatusesFromPreviousDay(ctx, statusesToConsider, startOfPreviousDay, endOfPreviousDay)
+	stalledStatuses, err := s.notifRepo.ListStalledStatusesFromPreviousDay(ctx, statusesToConsider, startOfPreviousDay, endOfPreviousDay, cycleTypes)
 	if err != nil {
 		logCtx.WithError(err).Error("Failed to list stalled statuses for next-day reminder")
 		return fmt.Errorf("failed to list stalled statuses: %w", err)
@@ -541,10 +1058,29 @@ func (s *NotificationServiceImpl) ProcessNextDayReminders(ctx context.Context) e
 
 	if len(stalledStatuses) == 0 {
 		logCtx.Info("No statuses found needing a next-day reminder.")
+		s.closeCompletedCycles(ctx, logCtx)
 		return nil
 	}
 	logCtx.WithField("stalled_statuses_count", len(stalledStatuses)).Info("Found status(es) needing a next-day reminder.")
 
+	// Load every teacher involved in one query instead of one lookup per status.
+	teacherIDs := make([]int64, 0, len(stalledStatuses))
+	seenTeachers := make(map[int64]bool)
+	for _, rs := range stalledStatuses {
+		if !seenTeachers[rs.TeacherID] {
+			seenTeachers[rs.TeacherID] = true
+			teacherIDs = append(teacherIDs, rs.TeacherID)
+		}
+	}
+	teachersByID, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to load teachers for next-day reminders")
+		return fmt.Errorf("failed to load teachers: %w", err)
+	}
+
+	statusesToUpdate := make([]*notification.ReportStatus, 0, len(stalledStatuses))
+	var due []*dueReminder
+	missingCycles := make(map[int32]bool)
 	for _, rs := range stalledStatuses {
 		reminderLogCtx := logCtx.WithFields(logrus.Fields{
 			"report_status_id": rs.ID,
@@ -555,47 +1091,493 @@ func (s *NotificationServiceImpl) ProcessNextDayReminders(ctx context.Context) e
 		})
 		reminderLogCtx.Info("Processing next-day reminder")
 
-		teacherInfo, err := s.teacherRepo.GetByID(ctx, rs.TeacherID)
-		if err != nil {
-			reminderLogCtx.WithError(err).Error("Failed to get teacher for next-day reminder")
+		teacherInfo, ok := teachersByID[rs.TeacherID]
+		if !ok {
+			reminderLogCtx.Error("Teacher for next-day reminder not found")
 			continue // Skip this reminder
 		}
 
-		// Update status before sending to prevent re-processing if send fails temporarily
-		rs.Status = notification.StatusNextDayReminderSent
-		rs.ResponseAttempts++                    // Increment response attempts
-		rs.RemindAt = sql.NullTime{Valid: false} // Clear any existing reminder time
-		rs.UpdatedAt = time.Now()
-
-		// Re-send the specific question
-		if err := s.sendSpecificReportQuestion(ctx, teacherInfo, rs.CycleID, rs.ReportKey); err != nil {
-			reminderLogCtx.WithError(err).Error("Failed to send next-day reminder")
-			// If send fails, status is already NEXT_DAY_REMINDER_SENT in memory.
-			// We update the DB status to NEXT_DAY_REMINDER_SENT to record the attempt.
-			// LastNotifiedAt would not be updated by sendSpecificReportQuestion.
-			rs.Status = notification.StatusNextDayReminderSent // Ensure this is the final status for this attempt
-			if errUpdate := s.notifRepo.UpdateReportStatus(ctx, rs); errUpdate != nil {
-				reminderLogCtx.WithError(errUpdate).Error("Failed to update ReportStatusID after FAILED next-day reminder send attempt")
-			}
+		if s.isExcludedFromCycle(ctx, reminderLogCtx, rs.CycleID, rs.TeacherID) {
+			reminderLogCtx.Info("Teacher is excluded from this cycle. Skipping next-day reminder.")
+			continue
+		}
 
-		} else {
-			// sendSpecificReportQuestion on success would have updated rs.LastNotifiedAt (via its own UpdateReportStatus call for that status).
-			// Now, we ensure the status is NEXT_DAY_REMINDER_SENT.
-			// Fetch the latest version of rs as sendSpecificReportQuestion might have updated it (especially LastNotifiedAt).
-			updatedRs, fetchErr := s.notifRepo.GetReportStatusByID(ctx, rs.ID)
-			if fetchErr != nil {
-				reminderLogCtx.WithError(fetchErr).Error("Failed to re-fetch ReportStatusID after successful next-day reminder send")
-				updatedRs = rs // Fallback to original rs, LastNotifiedAt might be stale from this rs instance.
-			}
-			updatedRs.Status = notification.StatusNextDayReminderSent // Final status for this path
-			updatedRs.ResponseAttempts = rs.ResponseAttempts          // Preserve incremented attempts from the in-memory rs
+		if s.isCycleMissing(ctx, reminderLogCtx, rs.CycleID, missingCycles) {
+			s.cancelOrphanedStatus(ctx, reminderLogCtx, rs)
+			continue
+		}
 
-			if errUpdate := s.notifRepo.UpdateReportStatus(ctx, updatedRs); errUpdate != nil {
-				reminderLogCtx.WithError(errUpdate).Error("Failed to update ReportStatusID after successful next-day reminder")
-			} else {
-				reminderLogCtx.Info("Successfully sent and updated status for next-day reminder")
+		if !teacherInfo.WorkDays.Includes(now.Weekday()) {
+			// The next-day query only looks at yesterday, so hand the status over to the reminder sweep instead of skipping it.
+			s.deferReminderToNextWorkDay(ctx, reminderLogCtx, teacherInfo, rs, now)
+			continue
+		}
+
+		due = append(due, &dueReminder{teacher: teacherInfo, status: rs, logCtx: reminderLogCtx})
+	}
+
+	for _, batch := range s.batchReminders(due) {
+		// sendReminderBatch only touches the in-memory statuses (LastNotifiedAt on success);
+		// all updates of this sweep are flushed together below.
+		err := s.sendReminderBatch(ctx, batch, questionModeReminderNextDay)
+		if errors.Is(err, domainTelegram.ErrSendCircuitOpen) {
+			// Telegram is unreachable; leave the statuses due so the next sweep tries again.
+			continue
+		}
+		if err == nil {
+			for _, r := range batch {
+				r.logCtx.Info("Successfully sent next-day reminder")
 			}
 		}
+		// A failed attempt is still recorded as NEXT_DAY_REMINDER_SENT so it isn't retried every sweep.
+		for _, r := range batch {
+			r.status.Status = notification.StatusNextDayReminderSent
+			r.status.ResponseAttempts++                    // Increment response attempts
+			r.status.RemindAt = sql.NullTime{Valid: false} // Clear any existing reminder time
+			r.status.UpdatedAt = time.Now()
+			statusesToUpdate = append(statusesToUpdate, r.status)
+		}
 	}
+
+	if err := s.notifRepo.BulkUpdateReportStatuses(ctx, statusesToUpdate); err != nil {
+		var bulkErr *idb.BulkUpdateError
+		if !errors.As(err, &bulkErr) {
+			logCtx.WithError(err).Error("Failed to flush next-day reminder status updates")
+			return fmt.Errorf("failed to flush next-day reminder status updates: %w", err)
+		}
+		// Per-item failures: the remaining statuses were committed.
+		for reportStatusID, itemErr := range bulkErr.Failures {
+			logCtx.WithError(itemErr).WithField("report_status_id", reportStatusID).Error("Failed to update ReportStatusID after next-day reminder")
+		}
+	}
+	logCtx.WithField("updated_statuses_count", len(statusesToUpdate)).Info("Next-day reminder sweep finished")
+
+	// Close cycles that no longer need sweeping (e.g. the last outstanding teacher was deactivated).
+	s.closeCompletedCycles(ctx, logCtx)
 	return nil
 }
+
+// deferReminderToNextWorkDay schedules the status to be re-asked by the reminder sweep on the teacher's next work day.
+func (s *NotificationServiceImpl) deferReminderToNextWorkDay(ctx context.Context, logCtx *logrus.Entry, teacherInfo *teacher.Teacher, rs *notification.ReportStatus, now time.Time) {
+	nextWorkDay := teacherInfo.WorkDays.NextWorkDay(now)
+	rs.Status = notification.StatusAwaitingReminder1H
+	rs.RemindAt = sql.NullTime{Time: nextWorkDay, Valid: true}
+	rs.UpdatedAt = now
+	if err := s.notifRepo.UpdateReportStatus(ctx, rs); err != nil {
+		logCtx.WithError(err).Error("Failed to defer reminder to next work day")
+		return
+	}
+	logCtx.WithFields(logrus.Fields{
+		"work_days": teacherInfo.WorkDays.String(),
+		"remind_at": nextWorkDay.Format(time.RFC3339),
+	}).Info("Today is not a work day for the teacher. Reminder deferred.")
+}
+
+// ReplayLastQuestion re-sends the question the teacher should currently be answering.
+// Used by support when a teacher reports that a message never arrived.
+func (s *NotificationServiceImpl) ReplayLastQuestion(ctx context.Context, teacherTelegramID int64) (notification.ReportKey, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "ReplayLastQuestion",
+		"teacher_tg_id": teacherTelegramID,
+	})
+	logCtx.Info("Replaying last question for teacher")
+
+	teacherInfo, cycleID, nextReportKey, err := s.findOutstandingReport(ctx, logCtx, teacherTelegramID)
+	if err != nil {
+		return "", err
+	}
+	logCtx = logCtx.WithFields(logrus.Fields{"teacher_id": teacherInfo.ID, "cycle_id": cycleID, "report_key": nextReportKey})
+
+	if err := s.sendSpecificReportQuestion(ctx, teacherInfo, cycleID, nextReportKey, questionModeInitial); err != nil {
+		if errors.Is(err, idb.ErrReportStatusNotFound) {
+			logCtx.Info("Report status disappeared before the replay. Nothing outstanding.")
+			return "", ErrNoOutstandingReports
+		}
+		logCtx.WithError(err).Error("Failed to replay question")
+		return "", err
+	}
+	logCtx.Info("Question replayed successfully")
+	return nextReportKey, nil
+}
+
+// RemindTeacherNow sends a reminder for the teacher's current outstanding report right away,
+// counting it as a reminder (ResponseAttempts is bumped) and dropping any scheduled one.
+func (s *NotificationServiceImpl) RemindTeacherNow(ctx context.Context, teacherTelegramID int64) (notification.ReportKey, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "RemindTeacherNow",
+		"teacher_tg_id": teacherTelegramID,
+	})
+	logCtx.Info("Sending on-demand reminder to teacher")
+
+	teacherInfo, cycleID, nextReportKey, err := s.findOutstandingReport(ctx, logCtx, teacherTelegramID)
+	if err != nil {
+		return "", err
+	}
+	logCtx = logCtx.WithFields(logrus.Fields{"teacher_id": teacherInfo.ID, "cycle_id": cycleID, "report_key": nextReportKey})
+
+	reportStatus, err := s.notifRepo.GetReportStatus(ctx, teacherInfo.ID, cycleID, nextReportKey)
+	if err != nil {
+		if err == idb.ErrReportStatusNotFound {
+			logCtx.Info("Report status disappeared before the reminder. Nothing outstanding.")
+			return "", ErrNoOutstandingReports
+		}
+		logCtx.WithError(err).Error("Could not fetch report status for on-demand reminder")
+		return "", fmt.Errorf("failed to fetch status for %s: %w", nextReportKey, err)
+	}
+
+	if err := s.sendReportQuestion(ctx, logCtx, teacherInfo, reportStatus, questionModeReminder1H); err != nil {
+		return "", err
+	}
+	reportStatus.ResponseAttempts++
+	reportStatus.RemindAt = sql.NullTime{Valid: false} // The teacher was just reminded; a pending scheduled reminder would be a duplicate
+	if err := s.notifRepo.UpdateReportStatus(ctx, reportStatus); err != nil {
+		logCtx.WithError(err).WithField("report_status_id", reportStatus.ID).Error("Failed to update status after on-demand reminder")
+	}
+	logCtx.Info("On-demand reminder sent successfully")
+	return nextReportKey, nil
+}
+
+// findOutstandingReport resolves the teacher and the first unconfirmed report of the newest open cycle the teacher
+// has statuses in. Returns ErrNoOutstandingReports when there is nothing to ask.
+func (s *NotificationServiceImpl) findOutstandingReport(ctx context.Context, logCtx *logrus.Entry, teacherTelegramID int64) (*teacher.Teacher, int32, notification.ReportKey, error) {
+	teacherInfo, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, 0, "", err
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, 0, "", fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+	logCtx = logCtx.WithField("teacher_id", teacherInfo.ID)
+
+	openCycles, err := s.notifRepo.ListCyclesByStatus(ctx, notification.CycleStatusOpen)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list open cycles")
+		return nil, 0, "", fmt.Errorf("failed to list open cycles: %w", err)
+	}
+	// Newest open cycle first. A teacher added after a cycle started, or excluded from it, has no statuses
+	// there, so the search falls back to an older cycle they were actually asked in.
+	for i := len(openCycles) - 1; i >= 0; i-- {
+		cycle := openCycles[i]
+		if cycle.Source == notification.CycleSourceSimulation {
+			continue
+		}
+		statuses, err := s.notifRepo.ListReportStatusesByCycleAndTeacher(ctx, cycle.ID, teacherInfo.ID)
+		if err != nil {
+			logCtx.WithError(err).WithField("cycle_id", cycle.ID).Error("Failed to list report statuses")
+			return nil, 0, "", fmt.Errorf("failed to list report statuses: %w", err)
+		}
+		if len(statuses) == 0 {
+			continue
+		}
+		if reportKey, ok := firstUnconfirmedReport(statuses, determineReportsForCycle(cycle.Type)); ok {
+			return teacherInfo, cycle.ID, reportKey, nil
+		}
+		logCtx.WithField("cycle_id", cycle.ID).Info("Teacher has no outstanding reports in the latest cycle they were asked in.")
+		return nil, 0, "", ErrNoOutstandingReports
+	}
+	logCtx.Info("Teacher has no statuses in any open cycle. Nothing outstanding.")
+	return nil, 0, "", ErrNoOutstandingReports
+}
+
+// firstUnconfirmedReport returns the first of cycleKeys (in question order) that has a status which is not confirmed.
+// Keys without a status are skipped: there is nothing to re-send for them.
+func firstUnconfirmedReport(statuses []*notification.ReportStatus, cycleKeys []notification.ReportKey) (notification.ReportKey, bool) {
+	byKey := make(map[notification.ReportKey]*notification.ReportStatus, len(statuses))
+	for _, rs := range statuses {
+		byKey[rs.ReportKey] = rs
+	}
+	for _, key := range cycleKeys {
+		if rs, ok := byKey[key]; ok && rs.Status != notification.StatusAnsweredYes {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// CheckIntegrity looks for duplicate teachers and orphaned report statuses. It never modifies data.
+func (s *NotificationServiceImpl) CheckIntegrity(ctx context.Context) (*IntegrityReport, error) {
+	logCtx := s.log.WithField("operation", "CheckIntegrity")
+	logCtx.Info("Running integrity check")
+
+	duplicates, err := s.teacherRepo.FindDuplicateTelegramIDs(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to find duplicate Telegram IDs")
+		return nil, fmt.Errorf("failed to find duplicate telegram IDs: %w", err)
+	}
+
+	orphans, err := s.notifRepo.ListOrphanedReportStatuses(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list orphaned report statuses")
+		return nil, fmt.Errorf("failed to list orphaned report statuses: %w", err)
+	}
+
+	logCtx.WithFields(logrus.Fields{
+		"duplicate_telegram_ids": len(duplicates),
+		"orphaned_statuses":      len(orphans),
+	}).Info("Integrity check finished")
+	return &IntegrityReport{DuplicateTelegramIDs: duplicates, OrphanedStatuses: orphans}, nil
+}
+
+// RetryFailedSends re-sends the first question for statuses that are still pending and were never delivered
+// (LastNotifiedAt is only set after a successful send, so it identifies failed initial sends durably).
+func (s *NotificationServiceImpl) RetryFailedSends(ctx context.Context, cycleID int32) (*InitiationResult, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "RetryFailedSends",
+		"cycle_id":  cycleID,
+	})
+	logCtx.Info("Retrying failed initial sends")
+
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if err == idb.ErrCycleNotFound {
+			logCtx.Warn("Cycle not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get cycle")
+		return nil, fmt.Errorf("failed to get cycle %d: %w", cycleID, err)
+	}
+	reportCount := len(determineReportsForCycle(cycle.Type))
+
+	pendingStatuses, err := s.notifRepo.ListReportStatusesByStatusAndCycle(ctx, cycleID, notification.StatusPendingQuestion)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list pending statuses")
+		return nil, fmt.Errorf("failed to list pending statuses for cycle %d: %w", cycleID, err)
+	}
+
+	result := &InitiationResult{CycleID: cycleID}
+	now := time.Now()
+	for _, rs := range pendingStatuses {
+		if rs.ReportKey != firstReportKey || rs.LastNotifiedAt.Valid {
+			continue // Only never-delivered first questions are retried
+		}
+		teacherLogCtx := logCtx.WithFields(logrus.Fields{"teacher_id": rs.TeacherID, "report_key": rs.ReportKey, "report_status_id": rs.ID})
+
+		t, err := s.teacherRepo.GetByID(ctx, rs.TeacherID)
+		if err != nil {
+			teacherLogCtx.WithError(err).Error("Failed to get teacher for retry")
+			result.Failed = append(result.Failed, SendFailure{TeacherID: rs.TeacherID, Err: err})
+			continue
+		}
+		if !t.IsActive {
+			teacherLogCtx.Info("Teacher is inactive. Skipping retry.")
+			continue
+		}
+		if s.isExcludedFromCycle(ctx, teacherLogCtx, cycleID, t.ID) {
+			teacherLogCtx.Info("Teacher is excluded from this cycle. Skipping retry.")
+			continue
+		}
+
+		if err := s.sendInitialQuestion(ctx, teacherLogCtx.WithField("teacher_tg_id", t.TelegramID), t, rs, reportCount, now); err != nil {
+			result.Failed = append(result.Failed, SendFailure{TeacherID: t.ID, Err: err})
+			continue
+		}
+		result.Sent = append(result.Sent, t.ID)
+	}
+
+	logCtx.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Retry of failed sends finished")
+	return result, nil
+}
+
+func (s *NotificationServiceImpl) RunCycleManually(ctx context.Context, cycleType notification.CycleType, cycleDate time.Time, round int) (*ManualRunResult, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":   "RunCycleManually",
+		"cycle_type":  cycleType,
+		"cycle_date":  cycleDate.Format("2006-01-02"),
+		"cycle_round": round,
+	})
+
+	existing, err := s.notifRepo.GetCycleByDateAndType(ctx, cycleDate, cycleType, round)
+	if err != nil {
+		if err != idb.ErrCycleNotFound {
+			logCtx.WithError(err).Error("Failed to get notification cycle")
+			return nil, fmt.Errorf("failed to get notification cycle: %w", err)
+		}
+		result, err := s.InitiateNotificationProcess(ctx, cycleType, cycleDate, round)
+		if err != nil {
+			return nil, err
+		}
+		return &ManualRunResult{InitiationResult: result}, nil
+	}
+
+	logCtx.WithField("cycle_id", existing.ID).Info("Cycle already initiated. Retrying unsent questions only.")
+	var result *InitiationResult
+	err = s.withRunLock(ctx, logCtx, runKey(cycleType, cycleDate, round), func() error {
+		var err error
+		result, err = s.RetryFailedSends(ctx, existing.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ManualRunResult{InitiationResult: result, ExistingCycle: existing}, nil
+}
+
+// runsListLimit caps how many runs ListRuns returns.
+const runsListLimit = 10
+
+// ListRuns returns the most recent initiation runs, optionally limited to one cycle (cycleID 0 means all).
+func (s *NotificationServiceImpl) ListRuns(ctx context.Context, cycleID int32) ([]*notification.RunSummary, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "ListRuns",
+		"cycle_id":  cycleID,
+	})
+	runs, err := s.notifRepo.ListRuns(ctx, cycleID, runsListLimit)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list notification runs")
+		return nil, fmt.Errorf("failed to list notification runs: %w", err)
+	}
+	logCtx.WithField("count", len(runs)).Info("Listed notification runs")
+	return runs, nil
+}
+
+// ListUpcomingReminders returns reminders scheduled to fire within the given window, soonest first.
+func (s *NotificationServiceImpl) ListUpcomingReminders(ctx context.Context, within time.Duration) ([]*UpcomingReminder, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "ListUpcomingReminders",
+		"within":    within.String(),
+	})
+	statuses, err := s.notifRepo.ListUpcomingReminders(ctx, within)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list upcoming reminders")
+		return nil, fmt.Errorf("failed to list upcoming reminders: %w", err)
+	}
+
+	teacherNames := make(map[int64]string)
+	reminders := make([]*UpcomingReminder, 0, len(statuses))
+	for _, rs := range statuses {
+		name, ok := teacherNames[rs.TeacherID]
+		if !ok {
+			t, err := s.teacherRepo.GetByID(ctx, rs.TeacherID)
+			if err != nil {
+				logCtx.WithError(err).WithField("teacher_id", rs.TeacherID).Error("Failed to get teacher for upcoming reminder")
+				return nil, fmt.Errorf("failed to get teacher %d: %w", rs.TeacherID, err)
+			}
+			name = s.teacherFullName(t)
+			teacherNames[rs.TeacherID] = name
+		}
+		reminders = append(reminders, &UpcomingReminder{Status: rs, TeacherName: name})
+	}
+	logCtx.WithField("count", len(reminders)).Info("Listed upcoming reminders")
+	return reminders, nil
+}
+
+// ListRecentSendFailures returns statuses whose latest delivery failed at or after since, newest first.
+func (s *NotificationServiceImpl) ListRecentSendFailures(ctx context.Context, since time.Time) ([]*RecentSendFailure, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "ListRecentSendFailures",
+		"since":     since.Format(time.RFC3339),
+	})
+	failures, err := s.notifRepo.ListRecentSendFailures(ctx, since)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list recent send failures")
+		return nil, fmt.Errorf("failed to list recent send failures: %w", err)
+	}
+
+	teacherIDs := make([]int64, 0, len(failures))
+	for _, f := range failures {
+		teacherIDs = append(teacherIDs, f.TeacherID)
+	}
+	teachers, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to get teachers for send failures")
+		return nil, fmt.Errorf("failed to get teachers for send failures: %w", err)
+	}
+
+	result := make([]*RecentSendFailure, 0, len(failures))
+	for _, f := range failures {
+		name := fmt.Sprintf("ID %d", f.TeacherID) // The teacher row may have been deleted since
+		if t, ok := teachers[f.TeacherID]; ok {
+			name = s.teacherFullName(t)
+		}
+		result = append(result, &RecentSendFailure{Failure: f, TeacherName: name})
+	}
+	logCtx.WithField("count", len(result)).Info("Listed recent send failures")
+	return result, nil
+}
+
+// GetResponseRateStats returns aggregate response KPIs for cycles dated within [from, to).
+func (s *NotificationServiceImpl) GetResponseRateStats(ctx context.Context, from, to time.Time) (*notification.ResponseRateStats, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation": "GetResponseRateStats",
+		"from":      from.Format("2006-01-02"),
+		"to":        to.Format("2006-01-02"),
+	})
+	stats, err := s.notifRepo.GetResponseRateStats(ctx, from, to)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to get response rate stats")
+		return nil, fmt.Errorf("failed to get response rate stats: %w", err)
+	}
+	logCtx.WithField("total_statuses", stats.TotalStatuses).Info("Response rate stats retrieved")
+	return stats, nil
+}
+
+func (s *NotificationServiceImpl) GetTeacherReliability(ctx context.Context, teacherTelegramID int64, from, to time.Time) (*TeacherReliability, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "GetTeacherReliability",
+		"teacher_tg_id": teacherTelegramID,
+		"from":          from.Format("2006-01-02"),
+		"to":            to.Format("2006-01-02"),
+	})
+	t, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+
+	rate, total, err := s.notifRepo.GetTeacherReliability(ctx, t.ID, from, to)
+	if err != nil {
+		logCtx.WithError(err).WithField("teacher_id", t.ID).Error("Failed to get teacher reliability")
+		return nil, fmt.Errorf("failed to get teacher reliability: %w", err)
+	}
+	logCtx.WithFields(logrus.Fields{"teacher_id": t.ID, "total_reports": total}).Info("Teacher reliability retrieved")
+	return &TeacherReliability{Teacher: t, FirstTryRate: rate, TotalReports: total}, nil
+}
+
+// SnoozeReport moves a single status to AWAITING_REMINDER_1H with RemindAt = now + delay.
+// The regular reminder sweep then re-asks the question once the delay has passed.
+func (s *NotificationServiceImpl) SnoozeReport(ctx context.Context, reportStatusID int64, delay time.Duration) (*notification.ReportStatus, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":        "SnoozeReport",
+		"report_status_id": reportStatusID,
+		"delay":            delay.String(),
+	})
+	logCtx.Info("Snoozing report status")
+
+	if delay <= 0 || delay > maxSnoozeDuration {
+		logCtx.Warn("Invalid snooze duration")
+		return nil, ErrInvalidSnoozeDuration
+	}
+
+	reportStatus, err := s.notifRepo.GetReportStatusByID(ctx, reportStatusID)
+	if err != nil {
+		if err == idb.ErrReportStatusNotFound {
+			logCtx.Warn("Report status not found")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get report status by ID")
+		return nil, fmt.Errorf("failed to get report status by ID %d: %w", reportStatusID, err)
+	}
+	logCtx = logCtx.WithFields(logrus.Fields{"teacher_id": reportStatus.TeacherID, "cycle_id": reportStatus.CycleID, "report_key": reportStatus.ReportKey})
+
+	if reportStatus.Status == notification.StatusAnsweredYes {
+		logCtx.Warn("Report status is already confirmed. Nothing to snooze.")
+		return nil, ErrReportAlreadyConfirmed
+	}
+
+	reportStatus.Status = notification.StatusAwaitingReminder1H
+	reportStatus.RemindAt = sql.NullTime{Time: time.Now().Add(delay), Valid: true}
+	reportStatus.UpdatedAt = time.Now()
+	if err := s.notifRepo.UpdateReportStatus(ctx, reportStatus); err != nil {
+		logCtx.WithError(err).Error("Failed to update report status for snooze")
+		return nil, fmt.Errorf("failed to snooze report status ID %d: %w", reportStatusID, err)
+	}
+
+	logCtx.WithField("remind_at", reportStatus.RemindAt.Time.Format(time.RFC3339)).Info("Report status snoozed")
+	return reportStatus, nil
+}
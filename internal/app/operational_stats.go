@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// operationalStatsReminderWindow is how far ahead GetOperationalStats counts scheduled reminders.
+const operationalStatsReminderWindow = time.Hour
+
+// OperationalStats is a point-in-time snapshot of current counts, served as JSON by the stats endpoint.
+type OperationalStats struct {
+	ActiveTeachers       int                                    `json:"active_teachers"`
+	OpenCycles           int                                    `json:"open_cycles"`
+	StatusesByState      map[notification.InteractionStatus]int `json:"statuses_by_state"` // Statuses of open cycles
+	RemindersDueNextHour int                                    `json:"reminders_due_next_hour"`
+	OrphanedStatuses     int                                    `json:"orphaned_statuses"` // Statuses pointing at a deleted cycle; should be 0
+	GeneratedAt          time.Time                              `json:"generated_at"`
+}
+
+// GetOperationalStats returns a cheap snapshot of current counts for operators. Only aggregate queries are used.
+func (s *NotificationServiceImpl) GetOperationalStats(ctx context.Context) (*OperationalStats, error) {
+	logCtx := s.log.WithField("operation", "GetOperationalStats")
+
+	activeTeachers, err := s.teacherRepo.CountActive(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to count active teachers")
+		return nil, fmt.Errorf("failed to count active teachers: %w", err)
+	}
+	openCycles, err := s.notifRepo.ListCyclesByStatus(ctx, notification.CycleStatusOpen)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list open cycles")
+		return nil, fmt.Errorf("failed to list open cycles: %w", err)
+	}
+	statusCounts, err := s.notifRepo.CountReportStatusesByStatus(ctx, notification.CycleStatusOpen)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to count report statuses")
+		return nil, fmt.Errorf("failed to count report statuses: %w", err)
+	}
+	remindersDue, err := s.notifRepo.CountUpcomingReminders(ctx, operationalStatsReminderWindow)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to count upcoming reminders")
+		return nil, fmt.Errorf("failed to count upcoming reminders: %w", err)
+	}
+	orphaned, err := s.notifRepo.CountOrphanedReportStatuses(ctx)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to count orphaned report statuses")
+		return nil, fmt.Errorf("failed to count orphaned report statuses: %w", err)
+	}
+
+	stats := &OperationalStats{
+		ActiveTeachers:       activeTeachers,
+		OpenCycles:           len(openCycles),
+		StatusesByState:      statusCounts,
+		RemindersDueNextHour: remindersDue,
+		OrphanedStatuses:     orphaned,
+		GeneratedAt:          time.Now(),
+	}
+	logCtx.WithFields(logrus.Fields{
+		"active_teachers": stats.ActiveTeachers,
+		"open_cycles":     stats.OpenCycles,
+	}).Debug("Operational stats collected")
+	return stats, nil
+}
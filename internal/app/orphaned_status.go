@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// cancelOrphanedStatus handles a status whose cycle no longer exists (e.g. the cycle row was deleted by hand):
+// the status becomes CANCELLED, so sweeps stop picking it up, and the admin is alerted. Failures are only logged.
+func (s *NotificationServiceImpl) cancelOrphanedStatus(ctx context.Context, logCtx *logrus.Entry, rs *notification.ReportStatus) {
+	logCtx = logCtx.WithFields(logrus.Fields{"report_status_id": rs.ID, "cycle_id": rs.CycleID})
+	logCtx.Warn("Report status references a cycle that no longer exists. Cancelling it.")
+
+	rs.Status = notification.StatusCancelled
+	rs.RemindAt = sql.NullTime{Valid: false}
+	rs.UpdatedAt = time.Now()
+	if err := s.notifRepo.UpdateReportStatus(ctx, rs); err != nil {
+		logCtx.WithError(err).Error("Failed to cancel orphaned report status")
+	}
+
+	adminID := s.currentSettings().AdminTelegramID
+	if adminID == 0 {
+		return
+	}
+	msg := fmt.Sprintf("Внимание: статус %d (преподаватель ID %d, %s) ссылается на несуществующий цикл %d и был отменён. Проверьте базу данных: /check_integrity.",
+		rs.ID, rs.TeacherID, ReportTitle(rs.ReportKey), rs.CycleID)
+	if err := s.telegramClient.SendMessage(adminID, msg, nil); err != nil {
+		logCtx.WithError(err).Error("Failed to alert admin about orphaned report status")
+	}
+}
+
+// isCycleMissing reports whether the cycle row is gone. Results are kept in missingCycles so a sweep looks each
+// cycle up once. Lookup errors other than "not found" count as present: the status is left for the next sweep.
+func (s *NotificationServiceImpl) isCycleMissing(ctx context.Context, logCtx *logrus.Entry, cycleID int32, missingCycles map[int32]bool) bool {
+	if missing, ok := missingCycles[cycleID]; ok {
+		return missing
+	}
+	_, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil && err != idb.ErrCycleNotFound {
+		logCtx.WithError(err).Warn("Failed to check that the cycle of the report status exists")
+		return false
+	}
+	missingCycles[cycleID] = err == idb.ErrCycleNotFound
+	return missingCycles[cycleID]
+}
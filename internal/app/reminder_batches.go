@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// dueReminder is a status a reminder sweep decided to re-ask.
+type dueReminder struct {
+	teacher *teacher.Teacher
+	status  *notification.ReportStatus
+	logCtx  *logrus.Entry
+}
+
+// batchReminders splits due reminders into the messages to send. With CoalesceReminders every teacher gets one
+// batch holding all of their due statuses (in sweep order); otherwise every status is its own batch.
+// Reply-keyboard answers can't tell reports apart, so they always get one message per status.
+func (s *NotificationServiceImpl) batchReminders(due []*dueReminder) [][]*dueReminder {
+	var batches [][]*dueReminder
+	if !s.currentSettings().CoalesceReminders || s.currentSettings().ReplyKeyboardAnswers {
+		for _, r := range due {
+			batches = append(batches, []*dueReminder{r})
+		}
+		return batches
+	}
+	batchIndex := make(map[int64]int)
+	for _, r := range due {
+		if i, ok := batchIndex[r.teacher.ID]; ok {
+			batches[i] = append(batches[i], r)
+			continue
+		}
+		batchIndex[r.teacher.ID] = len(batches)
+		batches = append(batches, []*dueReminder{r})
+	}
+	return batches
+}
+
+// sendReminderBatch sends the reminder for a batch of one teacher's statuses and, on success, marks them
+// as asked in memory (LastNotifiedAt and PENDING_QUESTION). Persisting is left to the caller.
+// A single status is re-asked as usual; several are listed in one message with answer buttons per report.
+func (s *NotificationServiceImpl) sendReminderBatch(ctx context.Context, batch []*dueReminder, mode questionMode) error {
+	if len(batch) == 1 {
+		r := batch[0]
+		if err := s.sendReportQuestion(ctx, r.logCtx, r.teacher, r.status, mode); err != nil {
+			r.logCtx.WithError(err).Error("Failed to send reminder (re-ask question)")
+			return err
+		}
+		return nil
+	}
+
+	teacherInfo := batch[0].teacher
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "sendReminderBatch",
+		"teacher_id":    teacherInfo.ID,
+		"question_mode": mode.String(),
+		"batch_size":    len(batch),
+	})
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Привет, %s! %s\n", s.teacherGreetingName(teacherInfo), reminderBatchHeader(mode)))
+	replyMarkup := &telebot.ReplyMarkup{ResizeKeyboard: true}
+	var rows []telebot.Row
+	for _, r := range batch {
+		if r.status.Status == notification.StatusAnsweredYes {
+			// Never re-ask a report that is already confirmed.
+			r.logCtx.Warn("Attempted to send reminder for an already confirmed report")
+			return fmt.Errorf("cannot send reminder for status %s", r.status.Status)
+		}
+		title := ReportTitle(r.status.ReportKey)
+		text.WriteString(fmt.Sprintf(" - %s\n", title))
+		rows = append(rows, replyMarkup.Row(
+			replyMarkup.Data(title+": Да", fmt.Sprintf("ans_yes_%d", r.status.ID)),
+			replyMarkup.Data("Нет", fmt.Sprintf("ans_no_%d", r.status.ID)),
+		))
+	}
+	text.WriteString("Отметьте, пожалуйста, каждую таблицу кнопками ниже.")
+	if s.currentSettings().ReminderAlreadyDoneNote {
+		text.WriteString("\n" + reminderAlreadyDoneNote)
+	}
+	if batchCycleID, ok := singleCycleID(batch); ok {
+		rows = append(rows, confirmAllRow(replyMarkup, batchCycleID))
+	}
+	replyMarkup.Inline(rows...)
+
+	if err := s.telegramClient.SendMessage(teacherInfo.TelegramID, text.String(), &telebot.SendOptions{ReplyMarkup: replyMarkup}); err != nil {
+		logCtx.WithError(err).Error("Failed to send combined reminder")
+		if !errors.Is(err, domainTelegram.ErrSendCircuitOpen) { // Not a delivery failure; the statuses stay due
+			for _, r := range batch {
+				s.recordSendFailure(ctx, r.logCtx, r.status, err)
+			}
+		}
+		return fmt.Errorf("failed to send combined reminder: %w", err)
+	}
+	logCtx.Info("Successfully sent combined reminder")
+
+	now := time.Now()
+	for _, r := range batch {
+		r.status.LastNotifiedAt = sql.NullTime{Time: now, Valid: true}
+		r.status.Status = notification.StatusPendingQuestion
+	}
+	return nil
+}
+
+// singleCycleID returns the cycle all statuses of the batch belong to, if there is exactly one.
+func singleCycleID(batch []*dueReminder) (int32, bool) {
+	cycleID := batch[0].status.CycleID
+	for _, r := range batch[1:] {
+		if r.status.CycleID != cycleID {
+			return 0, false
+		}
+	}
+	return cycleID, true
+}
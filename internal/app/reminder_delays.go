@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReminderDelays holds the delays of the timed reminder tiers for one cycle type.
+type ReminderDelays struct {
+	AfterNo            time.Duration // First reminder after the teacher answers "No"
+	AfterFirstReminder time.Duration // Optional second-tier reminder after the first one; 0 disables the tier
+}
+
+// defaultReminderDelays applies to cycle types without configured delays.
+var defaultReminderDelays = ReminderDelays{AfterNo: time.Hour}
+
+// reminderDelaysForCycle looks up the reminder delays for the cycle's type.
+// Falls back to defaultReminderDelays if the cycle cannot be loaded, so a reminder is still scheduled.
+func (s *NotificationServiceImpl) reminderDelaysForCycle(ctx context.Context, logCtx *logrus.Entry, cycleID int32) ReminderDelays {
+	cycle, err := s.notifRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		logCtx.WithError(err).Warn("Failed to get cycle for reminder delays. Using defaults.")
+		return defaultReminderDelays
+	}
+	if delays, ok := s.currentSettings().ReminderDelays[cycle.Type]; ok {
+		return delays
+	}
+	return defaultReminderDelays
+}
+
+// reminderJitter returns a random extra delay in [0, ReminderJitter] for a reminder being scheduled.
+func (s *NotificationServiceImpl) reminderJitter() time.Duration {
+	return jitterUpTo(s.currentSettings().ReminderJitter)
+}
+
+// jitterUpTo returns a uniformly random duration in [0, limit]; 0 when limit is not positive.
+func jitterUpTo(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit + 1)
+}
+
+// formatDelay renders a reminder delay for teacher-facing messages, e.g. "час", "2 ч." or "1 ч. 30 мин.".
+func formatDelay(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == time.Hour {
+		return "час"
+	}
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d мин.", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d ч.", hours)
+	default:
+		return fmt.Sprintf("%d ч. %d мин.", hours, minutes)
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderJitter(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit time.Duration
+	}{
+		{name: "disabled", limit: 0},
+		{name: "negative", limit: -time.Minute},
+		{name: "one nanosecond", limit: 1},
+		{name: "ten minutes", limit: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newTestService(nil)
+			svc.UpdateSettings(NotificationSettings{ReminderJitter: tt.limit})
+			for i := 0; i < 1000; i++ {
+				got := svc.reminderJitter()
+				if tt.limit <= 0 && got != 0 {
+					t.Fatalf("reminderJitter() = %v with jitter %v, want 0", got, tt.limit)
+				}
+				if got < 0 || got > max(tt.limit, 0) {
+					t.Fatalf("reminderJitter() = %v, want within [0, %v]", got, tt.limit)
+				}
+			}
+		})
+	}
+}
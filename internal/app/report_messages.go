@@ -0,0 +1,106 @@
+// internal/app/report_messages.go
+package app
+
+import (
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+)
+
+// questionMode selects which template is used when asking a teacher about a report.
+type questionMode int
+
+const (
+	questionModeInitial         questionMode = iota // First time the question is asked in the sequence
+	questionModeReminder1H                          // Re-ask after the teacher answered "No"
+	questionModeReminder4H                          // Optional second re-ask a few hours after the 1-hour one
+	questionModeReminderNextDay                     // Re-ask the day after the question stalled
+)
+
+func (m questionMode) String() string {
+	switch m {
+	case questionModeReminder1H:
+		return "reminder_1h"
+	case questionModeReminder4H:
+		return "reminder_4h"
+	case questionModeReminderNextDay:
+		return "reminder_next_day"
+	default:
+		return "initial"
+	}
+}
+
+// reportQuestions holds the base question for every known report.
+var reportQuestions = map[notification.ReportKey]string{
+	notification.ReportKeyTable1Lessons:  "Заполнена ли Таблица 1: Проведенные уроки (отчёт за текущий период)?",
+	notification.ReportKeyTable3Schedule: "Заполнена ли Таблица 3: Расписание (проверка актуальности)?",
+	notification.ReportKeyTable2OTV:      "Заполнена ли Таблица 2: Таблица ОТВ (все проведенные уроки за всё время)?",
+}
+
+// reminderBatchHeader introduces a combined reminder listing several reports.
+func reminderBatchHeader(mode questionMode) string {
+	switch mode {
+	case questionModeReminder4H:
+		return "Повторное напоминание: остались неподтверждённые таблицы:"
+	case questionModeReminderNextDay:
+		return "Напоминание (вопросы со вчерашнего дня): остались неподтверждённые таблицы:"
+	default:
+		return "Напоминание: остались неподтверждённые таблицы:"
+	}
+}
+
+// reportTitles are short report names for lists shown to users.
+var reportTitles = map[notification.ReportKey]string{
+	notification.ReportKeyTable1Lessons:  "Таблица 1: Проведенные уроки",
+	notification.ReportKeyTable3Schedule: "Таблица 3: Расписание",
+	notification.ReportKeyTable2OTV:      "Таблица 2: Таблица ОТВ",
+}
+
+// ReportTitle returns the user-facing name of a report, falling back to its key.
+func ReportTitle(reportKey notification.ReportKey) string {
+	if title, ok := reportTitles[reportKey]; ok {
+		return title
+	}
+	return string(reportKey)
+}
+
+// reportCountAnnouncement tells the teacher how many reports the cycle asks about, e.g. "Вам нужно подтвердить 3 таблицы."
+func reportCountAnnouncement(count int) string {
+	noun := "таблиц"
+	switch n := count % 100; {
+	case n >= 11 && n <= 14:
+		// 11-14 take "таблиц" despite their last digit
+	case n%10 == 1:
+		noun = "таблицу"
+	case n%10 >= 2 && n%10 <= 4:
+		noun = "таблицы"
+	}
+	return fmt.Sprintf("Вам нужно подтвердить %d %s.", count, noun)
+}
+
+// initialQuestionLeads are prepended to follow-up questions in the initial sequence.
+var initialQuestionLeads = map[notification.ReportKey]string{
+	notification.ReportKeyTable3Schedule: "Отлично! ",
+	notification.ReportKeyTable2OTV:      "Супер! ",
+}
+
+// reminderAlreadyDoneNote is appended to reminders (see NotificationSettings.ReminderAlreadyDoneNote) for teachers
+// who filled the table in after being asked and are unsure how to answer a reminder.
+const reminderAlreadyDoneNote = "Если уже заполнили — нажмите 'Да'."
+
+// buildReportQuestionText returns the question text for a report in the given mode.
+func buildReportQuestionText(reportKey notification.ReportKey, mode questionMode) (string, error) {
+	question, ok := reportQuestions[reportKey]
+	if !ok {
+		return "", fmt.Errorf("unknown report key: %s", reportKey)
+	}
+	switch mode {
+	case questionModeReminder1H:
+		return "Напоминание: " + question, nil
+	case questionModeReminder4H:
+		return "Повторное напоминание: " + question, nil
+	case questionModeReminderNextDay:
+		return "Напоминание (вопрос со вчерашнего дня): " + question, nil
+	default:
+		return initialQuestionLeads[reportKey] + question, nil
+	}
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInitiationInProgress is returned when another initiation of the same cycle date, type and round is sending.
+var ErrInitiationInProgress = fmt.Errorf("another initiation of this cycle is in progress")
+
+// runLockStaleAfter is how long a run lock is honoured. A lock left behind by a crashed process expires after it.
+const runLockStaleAfter = time.Hour
+
+// runKey identifies the initiations that must not send concurrently. The date is normalised like the stored
+// cycle date, so every caller that resolves to the same cycle uses the same key.
+func runKey(cycleType notification.CycleType, cycleDate time.Time, round int) string {
+	return fmt.Sprintf("%s:%s:%d", cycleType, notification.CycleDay(cycleDate).Format(notification.CycleDateKeyLayout), round)
+}
+
+// withRunLock runs fn while holding the run lock for key, or returns ErrInitiationInProgress when it is held
+// elsewhere. The unique index on scheduled cycles only stops a second cycle row; two initiations could still
+// both find that one cycle and send its questions. The lock covers the send phase as well.
+func (s *NotificationServiceImpl) withRunLock(ctx context.Context, logCtx *logrus.Entry, key string, fn func() error) error {
+	owner, err := s.notifRepo.AcquireRunLock(ctx, key, runLockStaleAfter)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to acquire run lock")
+		return fmt.Errorf("failed to acquire run lock: %w", err)
+	}
+	if owner == "" {
+		logCtx.WithField("run_key", key).Warn("Another initiation of this cycle is in progress. Skipping.")
+		return ErrInitiationInProgress
+	}
+	defer func() {
+		// A fresh context: the lock must be released even if ctx was cancelled mid-run. Should this run have
+		// outlived runLockStaleAfter, the owner token keeps it from releasing a lock someone else took over.
+		if err := s.notifRepo.ReleaseRunLock(context.Background(), key, owner); err != nil {
+			logCtx.WithError(err).WithField("run_key", key).Error("Failed to release run lock; it expires on its own")
+		}
+	}()
+	return fn()
+}
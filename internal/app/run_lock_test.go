@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+	"time"
+)
+
+func TestRunKey(t *testing.T) {
+	morning := time.Date(2024, 5, 31, 8, 0, 0, 0, time.Local)
+	base := runKey(notification.CycleTypeEndMonth, morning, notification.FirstRound)
+	tests := []struct {
+		name      string
+		cycleType notification.CycleType
+		date      time.Time
+		round     int
+		same      bool
+	}{
+		{name: "same day, later time", cycleType: notification.CycleTypeEndMonth, date: morning.Add(10 * time.Hour), round: notification.FirstRound, same: true},
+		{name: "next day", cycleType: notification.CycleTypeEndMonth, date: morning.AddDate(0, 0, 1), round: notification.FirstRound, same: false},
+		{name: "other round", cycleType: notification.CycleTypeEndMonth, date: morning, round: 2, same: false},
+		{name: "other type", cycleType: notification.CycleTypeMidMonth, date: morning, round: notification.FirstRound, same: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runKey(tt.cycleType, tt.date, tt.round) == base; got != tt.same {
+				t.Errorf("runKey(%s, %s, %d) == %q is %v, want %v", tt.cycleType, tt.date, tt.round, base, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestConcurrentInitiationsMessageTeachersOnce(t *testing.T) {
+	teachers := []*teacher.Teacher{testTeacher(1, "Анна"), testTeacher(2, "Борис")}
+	svc, repo, client := newTestService(teachers)
+	client.blockFirstSend = true
+	client.sendStarted = make(chan struct{})
+	client.releaseSend = make(chan struct{})
+	ctx := context.Background()
+
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := svc.InitiateNotificationProcess(ctx, notification.CycleTypeMidMonth, testCycleDate, notification.FirstRound)
+		firstDone <- err
+	}()
+	<-client.sendStarted // The first initiation holds the run lock and is sending
+
+	// A second trigger for the same cycle, e.g. a manual run at a different time of the same day.
+	_, err := svc.InitiateNotificationProcess(ctx, notification.CycleTypeMidMonth, testCycleDate.Add(time.Hour), notification.FirstRound)
+	if err != ErrInitiationInProgress {
+		t.Errorf("overlapping initiation: err = %v, want ErrInitiationInProgress", err)
+	}
+
+	close(client.releaseSend)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first initiation: %v", err)
+	}
+	for _, tc := range teachers {
+		if got := len(client.messagesTo(tc.TelegramID)); got != 1 {
+			t.Errorf("teacher %d got %d messages, want 1", tc.ID, got)
+		}
+	}
+	if len(repo.cycles) != 1 {
+		t.Errorf("%d cycles created, want 1", len(repo.cycles))
+	}
+	if len(repo.runLocks) != 0 {
+		t.Errorf("run lock not released: %v", repo.runLocks)
+	}
+}
+
+func TestRunLockTakenOverIsNotReleased(t *testing.T) {
+	svc, repo, _ := newTestService(nil)
+	key := runKey(notification.CycleTypeMidMonth, testCycleDate, notification.FirstRound)
+
+	err := svc.withRunLock(context.Background(), svc.log, key, func() error {
+		// The run outlived runLockStaleAfter and another initiation took the lock over.
+		repo.mu.Lock()
+		repo.runLocks[key] = "other-owner"
+		repo.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRunLock: %v", err)
+	}
+	if owner := repo.runLocks[key]; owner != "other-owner" {
+		t.Errorf("lock owner after the first run finished = %q, want the new holder's lock kept", owner)
+	}
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// simulationCycleType is the cycle type of simulated cycles; it asks every report, so the whole flow can be exercised.
+const simulationCycleType = notification.CycleTypeEndMonth
+
+// SimulateCycle creates a throwaway SIMULATION cycle for a single teacher and sends them the first question.
+// The rest of the flow (answers, reminders, auto-close) runs as for a regular cycle. Other teachers are untouched,
+// and simulated cycles are ignored by date lookups and statistics.
+func (s *NotificationServiceImpl) SimulateCycle(ctx context.Context, teacherTelegramID int64) (*notification.Cycle, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "SimulateCycle",
+		"teacher_tg_id": teacherTelegramID,
+		"simulation":    true,
+	})
+	logCtx.Info("Starting simulated cycle")
+
+	t, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			logCtx.Warn("Teacher not found by Telegram ID")
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+	logCtx = logCtx.WithField("teacher_id", t.ID)
+
+	now := time.Now()
+	cycle := &notification.Cycle{
+		CycleDate: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		Type:      simulationCycleType,
+		Round:     notification.FirstRound,
+		Source:    notification.CycleSourceSimulation,
+	}
+	if err := s.notifRepo.CreateCycle(ctx, cycle); err != nil {
+		logCtx.WithError(err).Error("Failed to create simulation cycle")
+		return nil, fmt.Errorf("failed to create simulation cycle: %w", err)
+	}
+	logCtx = logCtx.WithField("cycle_id", cycle.ID)
+	logCtx.Info("Simulation cycle created")
+
+	var statuses []*notification.ReportStatus
+	for _, reportKey := range determineReportsForCycle(cycle.Type) {
+		statuses = append(statuses, &notification.ReportStatus{
+			TeacherID: t.ID,
+			CycleID:   cycle.ID,
+			ReportKey: reportKey,
+			Status:    notification.StatusPendingQuestion,
+		})
+	}
+	if err := s.notifRepo.BulkCreateReportStatuses(ctx, statuses); err != nil {
+		logCtx.WithError(err).Error("Failed to create simulation report statuses")
+		return nil, fmt.Errorf("failed to create simulation report statuses: %w", err)
+	}
+	logCtx.WithField("count", len(statuses)).Info("Simulation report statuses created")
+
+	reportStatus, err := s.notifRepo.GetReportStatus(ctx, t.ID, cycle.ID, firstReportKey)
+	if err != nil {
+		logCtx.WithError(err).Error("Could not fetch simulation report status for the first question")
+		return nil, fmt.Errorf("failed to fetch status for %s: %w", firstReportKey, err)
+	}
+	teacherLogCtx := logCtx.WithFields(logrus.Fields{"report_key": firstReportKey, "report_status_id": reportStatus.ID})
+	if err := s.sendInitialQuestion(ctx, teacherLogCtx, t, reportStatus, len(statuses), now); err != nil {
+		return nil, err
+	}
+	teacherLogCtx.WithField("status", reportStatus.Status).Info("Simulation first question sent; further transitions follow the regular flow")
+	return cycle, nil
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"teacher_notification_bot/internal/domain/teacher"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoTeachersToExport is returned when the requested roster is empty.
+var ErrNoTeachersToExport = fmt.Errorf("no teachers to export")
+
+// teacherExportHeader is the column layout of the roster CSV.
+var teacherExportHeader = []string{"telegram_id", "first_name", "last_name", "is_active", "created_at"}
+
+// ExportTeachers writes the roster to a CSV file and sends it to the admin as a document.
+// When includeInactive is false only active teachers are exported. Returns the number of exported teachers.
+func (s *AdminService) ExportTeachers(ctx context.Context, performingAdminID int64, includeInactive bool) (int, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "ExportTeachers",
+		"performing_admin_id": performingAdminID,
+		"include_inactive":    includeInactive,
+	})
+	logCtx.Info("Attempting to export teachers")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to export teachers")
+		return 0, ErrAdminNotAuthorized
+	}
+
+	var teachers []*teacher.Teacher
+	var err error
+	if includeInactive {
+		teachers, err = s.teacherRepo.ListAll(ctx)
+	} else {
+		teachers, err = s.teacherRepo.ListActive(ctx)
+	}
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list teachers for export")
+		return 0, fmt.Errorf("failed to list teachers for export: %w", err)
+	}
+	if len(teachers) == 0 {
+		logCtx.Info("No teachers to export")
+		return 0, ErrNoTeachersToExport
+	}
+
+	// The roster is written to a temp file rather than memory so large rosters don't have to be buffered.
+	file, err := os.CreateTemp("", "teachers_export_*.csv")
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to create temp file for export")
+		return 0, fmt.Errorf("failed to create temp file for export: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := writeTeachersCSV(file, teachers); err != nil {
+		file.Close()
+		logCtx.WithError(err).Error("Failed to write teachers CSV")
+		return 0, fmt.Errorf("failed to write teachers CSV: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		logCtx.WithError(err).Error("Failed to close teachers CSV")
+		return 0, fmt.Errorf("failed to close teachers CSV: %w", err)
+	}
+
+	fileName := fmt.Sprintf("teachers_%s.csv", time.Now().Format("2006-01-02"))
+	caption := fmt.Sprintf("Экспорт преподавателей: %d", len(teachers))
+	if err := s.telegramClient.SendDocument(performingAdminID, file.Name(), fileName, caption); err != nil {
+		logCtx.WithError(err).Error("Failed to send teachers CSV")
+		return 0, fmt.Errorf("failed to send teachers CSV: %w", err)
+	}
+
+	logCtx.WithField("count", len(teachers)).Info("Teachers exported successfully")
+	return len(teachers), nil
+}
+
+func writeTeachersCSV(f io.Writer, teachers []*teacher.Teacher) error {
+	w := csv.NewWriter(f)
+	if err := w.Write(teacherExportHeader); err != nil {
+		return err
+	}
+	for _, t := range teachers {
+		record := []string{
+			strconv.FormatInt(t.TelegramID, 10),
+			t.FirstName,
+			t.LastName.String,
+			strconv.FormatBool(t.IsActive),
+			t.CreatedAt.Format(time.RFC3339),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
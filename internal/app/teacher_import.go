@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/teacher"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MaxTeacherImportRows caps how many teachers one ImportTeachers call accepts.
+const MaxTeacherImportRows = 200
+
+var ErrTooManyImportRows = fmt.Errorf("too many teachers to import at once (at most %d)", MaxTeacherImportRows)
+
+// TeacherImportRow is one teacher to create or refresh by Telegram ID.
+type TeacherImportRow struct {
+	TelegramID int64
+	FirstName  string
+	LastName   string
+}
+
+// TeacherImportRowResult is the outcome of one row. Rows after the failing one of an atomic import are not attempted.
+type TeacherImportRowResult struct {
+	Row     TeacherImportRow
+	Created bool // False for an existing teacher whose name was refreshed
+	Err     error
+}
+
+// TeacherImportResult reports an import row by row.
+type TeacherImportResult struct {
+	Rows       []TeacherImportRowResult
+	Atomic     bool
+	RolledBack bool // Atomic import that failed: nothing was saved, even for rows without an error
+}
+
+// ImportTeachers creates or refreshes teachers like EnsureTeacher; deactivated teachers stay deactivated.
+// An atomic import runs in one transaction and saves nothing unless every row succeeds. Otherwise rows are
+// saved one by one and failures are only reported. No welcome messages are sent.
+func (s *AdminService) ImportTeachers(ctx context.Context, performingAdminID int64, rows []TeacherImportRow, atomic bool) (*TeacherImportResult, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":           "ImportTeachers",
+		"performing_admin_id": performingAdminID,
+		"rows_count":          len(rows),
+		"atomic":              atomic,
+	})
+	logCtx.Info("Importing teachers")
+	if performingAdminID != s.adminTelegramID {
+		logCtx.Warn("Unauthorized attempt to import teachers")
+		return nil, ErrAdminNotAuthorized
+	}
+	if len(rows) > MaxTeacherImportRows {
+		return nil, ErrTooManyImportRows
+	}
+
+	result := &TeacherImportResult{Atomic: atomic}
+	importRows := func(repo teacher.Repository) error {
+		for _, row := range rows {
+			rowLogCtx := logCtx.WithField("teacher_tg_id", row.TelegramID)
+			_, created, err := s.ensureTeacher(ctx, rowLogCtx, repo, row.TelegramID, row.FirstName, row.LastName, false)
+			result.Rows = append(result.Rows, TeacherImportRowResult{Row: row, Created: created, Err: err})
+			if err != nil && atomic {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !atomic {
+		_ = importRows(s.teacherRepo) // Best effort: errors are reported per row
+		logCtx.Info("Teacher import finished")
+		return result, nil
+	}
+
+	if err := s.teacherRepo.WithTx(ctx, importRows); err != nil {
+		result.RolledBack = true
+		rowFailed := len(result.Rows) > 0 && result.Rows[len(result.Rows)-1].Err != nil
+		if !rowFailed {
+			// Every row went through, so the transaction itself failed (begin or commit).
+			logCtx.WithError(err).Error("Teacher import transaction failed")
+			return nil, fmt.Errorf("teacher import transaction failed: %w", err)
+		}
+		logCtx.WithError(err).Warn("Teacher import rolled back")
+		return result, nil
+	}
+	logCtx.Info("Teacher import committed")
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TeacherSummary lists what a teacher still has to confirm.
+type TeacherSummary struct {
+	Teacher     *teacher.Teacher
+	Cycle       *notification.Cycle      // Latest open cycle; nil when no cycle is open
+	Outstanding []notification.ReportKey // Unconfirmed reports of Cycle, in question order
+}
+
+// GetTeacherSummary returns the teacher's unconfirmed reports in the latest open cycle.
+// idb.ErrTeacherNotFound is returned for unknown users; inactive teachers are returned as is.
+func (s *NotificationServiceImpl) GetTeacherSummary(ctx context.Context, teacherTelegramID int64) (*TeacherSummary, error) {
+	logCtx := s.log.WithFields(logrus.Fields{
+		"operation":     "GetTeacherSummary",
+		"teacher_tg_id": teacherTelegramID,
+	})
+
+	t, err := s.teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+	if err != nil {
+		if err == idb.ErrTeacherNotFound {
+			return nil, err
+		}
+		logCtx.WithError(err).Error("Failed to get teacher by Telegram ID")
+		return nil, fmt.Errorf("failed to get teacher by Telegram ID: %w", err)
+	}
+	summary := &TeacherSummary{Teacher: t}
+	if !t.IsActive {
+		return summary, nil
+	}
+	logCtx = logCtx.WithField("teacher_id", t.ID)
+
+	openCycles, err := s.notifRepo.ListCyclesByStatus(ctx, notification.CycleStatusOpen)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list open cycles")
+		return nil, fmt.Errorf("failed to list open cycles: %w", err)
+	}
+	for _, cycle := range openCycles { // Ordered by cycle date, oldest first
+		if cycle.Source != notification.CycleSourceSimulation {
+			summary.Cycle = cycle
+		}
+	}
+	if summary.Cycle == nil {
+		return summary, nil
+	}
+	logCtx = logCtx.WithField("cycle_id", summary.Cycle.ID)
+
+	if s.isExcludedFromCycle(ctx, logCtx, summary.Cycle.ID, t.ID) {
+		return summary, nil
+	}
+
+	statuses, err := s.notifRepo.ListReportStatusesByCycleAndTeacher(ctx, summary.Cycle.ID, t.ID)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to list report statuses")
+		return nil, fmt.Errorf("failed to list report statuses: %w", err)
+	}
+	confirmed := make(map[notification.ReportKey]bool, len(statuses))
+	asked := make(map[notification.ReportKey]bool, len(statuses))
+	for _, rs := range statuses {
+		asked[rs.ReportKey] = true
+		confirmed[rs.ReportKey] = rs.Status == notification.StatusAnsweredYes
+	}
+	for _, key := range determineReportsForCycle(summary.Cycle.Type) {
+		if asked[key] && !confirmed[key] {
+			summary.Outstanding = append(summary.Outstanding, key)
+		}
+	}
+	logCtx.WithField("outstanding_count", len(summary.Outstanding)).Info("Built teacher summary")
+	return summary, nil
+}
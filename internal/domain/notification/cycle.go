@@ -3,11 +3,43 @@ package notification
 
 import "time"
 
+// CycleStatus tells whether a cycle still needs reminder sweeps.
+type CycleStatus string
+
+const (
+	CycleStatusOpen   CycleStatus = "OPEN"
+	CycleStatusClosed CycleStatus = "CLOSED" // Skipped by reminder sweeps
+)
+
+// CycleSource tells how a cycle was created.
+type CycleSource string
+
+const (
+	CycleSourceScheduled  CycleSource = "SCHEDULED"  // Regular cycle created by the scheduler or an admin
+	CycleSourceSimulation CycleSource = "SIMULATION" // Throwaway QA cycle; ignored by date lookups and stats
+)
+
+// FirstRound is the round of a cycle started once per date and type, as the scheduler does.
+const FirstRound = 1
+
 // Cycle represents a single notification run (e.g., mid-month May 2025).
 // Corresponds to the 'notification_cycles' table in schema B003.
 type Cycle struct {
-	ID        int32     // SERIAL in DB
-	CycleDate time.Time // Specific date of the cycle
-	Type      CycleType // e.g., MID_MONTH, END_MONTH
+	ID        int32       // SERIAL in DB
+	CycleDate time.Time   // Specific date of the cycle
+	Type      CycleType   // e.g., MID_MONTH, END_MONTH
+	Round     int         // 1 for the first initiation of a date and type; later rounds count up
+	Status    CycleStatus // OPEN until all work is done or an admin closes it
+	Source    CycleSource // SCHEDULED unless created by /simulate
 	CreatedAt time.Time
 }
+
+// CycleDateKeyLayout formats a cycle's calendar date (see CycleDay) wherever it identifies the cycle.
+const CycleDateKeyLayout = "2006-01-02"
+
+// CycleDay truncates t to midnight of its calendar date in the scheduler's location (server local time),
+// so cycles created and looked up with different time components or zones agree on the date.
+func CycleDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
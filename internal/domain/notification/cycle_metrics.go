@@ -0,0 +1,23 @@
+// internal/domain/notification/cycle_metrics.go
+package notification
+
+import "time"
+
+// CycleMetrics is the snapshot of a cycle's key numbers taken when it closes, kept for spotting trends.
+type CycleMetrics struct {
+	CycleID          int32
+	CycleDate        time.Time // Filled when listing; not stored with the snapshot
+	CycleType        CycleType // Filled when listing; not stored with the snapshot
+	TeachersNotified int       // Distinct teachers who got at least one question
+	TotalStatuses    int
+	Confirmations    int           // Statuses answered "Yes"
+	RemindersSent    int           // Same-day reminders plus ResponseAttempts (next-day and /remind)
+	AverageLatency   time.Duration // Mean time from the last question or reminder to the confirmation
+	CompletionTime   time.Duration // From the cycle's creation until it closed
+	RecordedAt       time.Time
+}
+
+// ConfirmationRate returns the share of confirmed statuses in percent.
+func (m *CycleMetrics) ConfirmationRate() float64 {
+	return Percentage(m.Confirmations, m.TotalStatuses)
+}
@@ -0,0 +1,22 @@
+package notification
+
+import "time"
+
+// DefaultDateLayout is the layout of calendar dates in bot messages unless DATE_FORMAT overrides it.
+const DefaultDateLayout = "2006-01-02"
+
+// dateLayout is the layout FormatDate uses. Set once at startup with SetDateLayout.
+var dateLayout = DefaultDateLayout
+
+// SetDateLayout sets the layout of calendar dates in bot messages (DATE_FORMAT). An empty layout keeps the default.
+// Call it once at startup, before any message is sent.
+func SetDateLayout(layout string) {
+	if layout != "" {
+		dateLayout = layout
+	}
+}
+
+// FormatDate renders a calendar date, such as a cycle date, for messages to the admin and the manager.
+func FormatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
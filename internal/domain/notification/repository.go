@@ -3,31 +3,115 @@ package notification
 
 import (
 	"context"
+	"teacher_notification_bot/internal/domain/teacher"
 	"time"
 )
 
 // Repository defines operations for NotificationCycle and TeacherReportStatus.
 type Repository interface {
 	// NotificationCycle methods
+	// CreateCycle inserts the cycle. When a scheduled cycle with the same date, type and round exists, cycle is
+	// filled with that one and ErrDuplicateCycle is returned.
 	CreateCycle(ctx context.Context, cycle *Cycle) error
 	GetCycleByID(ctx context.Context, id int32) (*Cycle, error)
-	GetCycleByDateAndType(ctx context.Context, cycleDate time.Time, cycleType CycleType) (*Cycle, error)
+	// GetCycleByDateAndType, GetLatestCycle and GetResponseRateStats only consider SCHEDULED cycles.
+	GetCycleByDateAndType(ctx context.Context, cycleDate time.Time, cycleType CycleType, round int) (*Cycle, error)
+	GetLatestCycle(ctx context.Context) (*Cycle, error) // Most recent cycle by date, regardless of type
+	UpdateCycleStatus(ctx context.Context, cycleID int32, status CycleStatus) error
+	// ListCyclesByStatus returns the cycles in the status, oldest date first. The status column is indexed, so the
+	// auto-close sweep lists OPEN cycles without scanning the whole table.
+	ListCyclesByStatus(ctx context.Context, status CycleStatus) ([]*Cycle, error)
+	// CountOutstandingReportStatuses counts unconfirmed statuses of active, non-excluded teachers in the cycle.
+	CountOutstandingReportStatuses(ctx context.Context, cycleID int32) (int, error)
+
+	// Per-cycle teacher exclusions (e.g. a teacher on leave). Adding an existing exclusion is a no-op.
+	AddCycleExclusion(ctx context.Context, cycleID int32, teacherID int64) error
+	RemoveCycleExclusion(ctx context.Context, cycleID int32, teacherID int64) error
+	IsTeacherExcludedFromCycle(ctx context.Context, cycleID int32, teacherID int64) (bool, error)
+	ListExcludedTeacherIDs(ctx context.Context, cycleID int32) ([]int64, error)
+
+	// Completion markers: set once a teacher got the final "all confirmed" messages for a cycle.
+	// Marking an already marked teacher is a no-op.
+	MarkCompletionNotified(ctx context.Context, cycleID int32, teacherID int64) error
+	IsCompletionNotified(ctx context.Context, cycleID int32, teacherID int64) (bool, error)
+
+	// Delivery failures: RecordSendFailure bumps the status's failure count and keeps the latest error.
+	RecordSendFailure(ctx context.Context, reportStatusID int64, sendErr string) error
+	// ListRecentSendFailures returns statuses whose latest failed delivery happened at or after since, newest first.
+	ListRecentSendFailures(ctx context.Context, since time.Time) ([]*SendFailure, error)
+
+	// RecordRemindersSent bumps the same-day (1-hour and 4-hour) reminder count of the statuses. ResponseAttempts
+	// is not touched: it counts next-day and /remind reminders only, which the first-try statistics rely on.
+	RecordRemindersSent(ctx context.Context, reportStatusIDs []int64) error
+
+	// MarkProxyConfirmed records that the statuses were confirmed by someone else on the teacher's behalf.
+	MarkProxyConfirmed(ctx context.Context, reportStatusIDs []int64, proxyTelegramID int64) error
+
+	// Initiation run history
+	RecordRun(ctx context.Context, summary *RunSummary) error
+	ListRuns(ctx context.Context, cycleID int32, limit int) ([]*RunSummary, error) // cycleID 0 lists runs of all cycles; newest first
+
+	// Run locks keep overlapping initiations of the same cycle from sending twice. AcquireRunLock returns the new
+	// holder's owner token, or "" while another holder's lock is younger than staleAfter. ReleaseRunLock only
+	// deletes the lock if owner still holds it, so a holder whose lock went stale and was taken over is a no-op.
+	AcquireRunLock(ctx context.Context, runKey string, staleAfter time.Duration) (owner string, err error)
+	ReleaseRunLock(ctx context.Context, runKey string, owner string) error
+
+	// Per-cycle metrics snapshots. ComputeCycleMetrics aggregates the cycle's statuses (CompletionTime is left to
+	// the caller); RecordCycleMetrics stores a snapshot, replacing an earlier one for a reopened and closed again cycle.
+	ComputeCycleMetrics(ctx context.Context, cycleID int32) (*CycleMetrics, error)
+	RecordCycleMetrics(ctx context.Context, m CycleMetrics) error
+	ListCycleMetrics(ctx context.Context, limit int) ([]*CycleMetrics, error) // Newest cycle first
 
 	// TeacherReportStatus methods
 	CreateReportStatus(ctx context.Context, rs *ReportStatus) error
 	BulkCreateReportStatuses(ctx context.Context, statuses []*ReportStatus) error // For initializing a cycle
+	// UpdateReportStatus persists Status, LastNotifiedAt, ResponseAttempts and RemindAt; UpdatedAt is set by the database.
 	UpdateReportStatus(ctx context.Context, rs *ReportStatus) error
+	BulkUpdateReportStatuses(ctx context.Context, statuses []*ReportStatus) error // Single transaction; per-item failures are reported, not fatal
+	// ResetReportKeyStatuses turns every ANSWERED_YES status of the key in the cycle back into a fresh, never-sent
+	// PENDING_QUESTION and returns the reset statuses. Other keys and unconfirmed statuses are untouched.
+	ResetReportKeyStatuses(ctx context.Context, cycleID int32, reportKey ReportKey) ([]*ReportStatus, error)
 	GetReportStatus(ctx context.Context, teacherID int64, cycleID int32, reportKey ReportKey) (*ReportStatus, error)
 	GetReportStatusByID(ctx context.Context, id int64) (*ReportStatus, error) // Useful for direct updates from reminders
+	// GetLatestPendingQuestion returns the teacher's most recently asked PENDING_QUESTION status in an OPEN cycle,
+	// or ErrReportStatusNotFound. Used to map reply-keyboard answers, which carry no status ID, to a report.
+	GetLatestPendingQuestion(ctx context.Context, teacherID int64) (*ReportStatus, error)
 	ListReportStatusesByCycleAndTeacher(ctx context.Context, cycleID int32, teacherID int64) ([]*ReportStatus, error)
+	// GetTeacherWithStatuses loads a teacher and their statuses in the cycle (in question order) with one query.
+	// The statuses are empty when the teacher has none in the cycle; a missing teacher is an error.
+	GetTeacherWithStatuses(ctx context.Context, teacherID int64, cycleID int32) (*teacher.Teacher, []*ReportStatus, error)
 	ListReportStatusesByCycle(ctx context.Context, cycleID int32) ([]*ReportStatus, error) // For admin/overview
 	ListReportStatusesByStatusAndCycle(ctx context.Context, cycleID int32, status InteractionStatus) ([]*ReportStatus, error)
+	// ListReportStatusesUpdatedSince returns statuses with updated_at >= since, oldest change first (for incremental exports).
+	ListReportStatusesUpdatedSince(ctx context.Context, since time.Time) ([]*ReportStatus, error)
 	ListReportStatusesForReminders(ctx context.Context, cycleID int32, status InteractionStatus, notifiedBefore time.Time) ([]*ReportStatus, error)
 
 	// AreAllReportsConfirmedForTeacher checks if a teacher has confirmed all required reports for a cycle.
 	// expectedReportKeys are the keys relevant for the given cycle type.
 	AreAllReportsConfirmedForTeacher(ctx context.Context, teacherID int64, cycleID int32, expectedReportKeys []ReportKey) (bool, error)
-	// ListDueReminders fetches report statuses that are due for a 1-hour reminder.
+	// ListDueReminders fetches report statuses of OPEN cycles that are due for a 1-hour reminder.
 	ListDueReminders(ctx context.Context, targetStatus InteractionStatus, remindAtOrBefore time.Time) ([]*ReportStatus, error)
-	ListStalledStatusesFromPreviousDay(ctx context.Context, statusesToConsider []InteractionStatus, startOfPreviousDay, endOfPreviousDay time.Time) ([]*ReportStatus, error)
+	// CountReportStatusesByStatus counts the statuses of cycles in the given cycle status, grouped by interaction status.
+	CountReportStatusesByStatus(ctx context.Context, cycleStatus CycleStatus) (map[InteractionStatus]int, error)
+	// CountUpcomingReminders counts reminders of OPEN cycles scheduled within the given window from now.
+	CountUpcomingReminders(ctx context.Context, within time.Duration) (int, error)
+	// ListUpcomingReminders returns statuses of active teachers in OPEN cycles whose reminder fires within
+	// the given window from now, soonest first.
+	ListUpcomingReminders(ctx context.Context, within time.Duration) ([]*ReportStatus, error)
+	// GetResponseRateStats aggregates statuses of cycles dated within [from, to).
+	GetResponseRateStats(ctx context.Context, from, to time.Time) (*ResponseRateStats, error)
+	// GetTeacherReliability returns the share (in percent) of the teacher's statuses in cycles dated within
+	// [from, to) that were confirmed without any reminder, and how many statuses there were. No statuses yields 0, 0.
+	GetTeacherReliability(ctx context.Context, teacherID int64, from, to time.Time) (firstTryRate float64, totalReports int, err error)
+	// FindMissingStatuses returns, per teacher with at least one status in the cycle, the expected keys that have
+	// no status (in the order of expectedKeys). Teachers missing nothing are absent from the map.
+	FindMissingStatuses(ctx context.Context, cycleID int32, expectedKeys []ReportKey) (map[int64][]ReportKey, error)
+	// ListOrphanedReportStatuses returns statuses whose teacher or cycle row no longer exists.
+	ListOrphanedReportStatuses(ctx context.Context) ([]*ReportStatus, error)
+	// CountOrphanedReportStatuses counts statuses whose cycle row no longer exists and that are not CANCELLED yet.
+	CountOrphanedReportStatuses(ctx context.Context) (int, error)
+	// ListStalledStatusesFromPreviousDay returns statuses of OPEN cycles last notified within the given day and still
+	// in one of statusesToConsider. A non-empty cycleTypes limits them to cycles of those types.
+	ListStalledStatusesFromPreviousDay(ctx context.Context, statusesToConsider []InteractionStatus, startOfPreviousDay, endOfPreviousDay time.Time, cycleTypes []CycleType) ([]*ReportStatus, error)
 }
@@ -0,0 +1,21 @@
+// internal/domain/notification/run.go
+package notification
+
+import "time"
+
+// RunSummary is the durable record of one InitiateNotificationProcess run.
+type RunSummary struct {
+	ID               int64
+	CycleID          int32
+	TeachersTargeted int // Active, non-excluded teachers at the time of the run
+	StatusesCreated  int
+	MessagesSent     int
+	MessagesFailed   int
+	StartedAt        time.Time
+	FinishedAt       time.Time
+}
+
+// Duration returns how long the run took.
+func (r *RunSummary) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
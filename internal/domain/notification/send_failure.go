@@ -0,0 +1,15 @@
+// internal/domain/notification/send_failure.go
+package notification
+
+import "time"
+
+// SendFailure is a report status whose question or reminder could not be delivered.
+type SendFailure struct {
+	ReportStatusID int64
+	TeacherID      int64
+	CycleID        int32
+	ReportKey      ReportKey
+	FailureCount   int    // Failed deliveries of this status so far
+	LastError      string // Error of the most recent failed delivery
+	LastFailedAt   time.Time
+}
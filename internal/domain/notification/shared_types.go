@@ -1,6 +1,15 @@
 // internal/domain/notification/shared_types.go
 package notification
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownReportKey is returned when external input names a report that does not exist.
+var ErrUnknownReportKey = fmt.Errorf("unknown report key")
+
 // ReportKey identifies the specific table/report being queried.
 type ReportKey string
 
@@ -10,6 +19,48 @@ const (
 	ReportKeyTable2OTV      ReportKey = "TABLE_2_OTV"      // FR3.2 (only end of month) [cite: 62]
 )
 
+// AllReportKeys lists every report key known to the system, in the order teachers are asked about them
+// (Table 1 → Table 3 → Table 2). This is the single source of the question sequence; see Sequence.
+func AllReportKeys() []ReportKey {
+	return []ReportKey{ReportKeyTable1Lessons, ReportKeyTable3Schedule, ReportKeyTable2OTV}
+}
+
+// Sequence returns the key's position in the question order. Unknown keys sort after all known ones.
+func (k ReportKey) Sequence() int {
+	keys := AllReportKeys()
+	for i, known := range keys {
+		if k == known {
+			return i
+		}
+	}
+	return len(keys)
+}
+
+// SortReportKeys sorts keys into question order, in place.
+func SortReportKeys(keys []ReportKey) {
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].Sequence() < keys[j].Sequence() })
+}
+
+// IsValid reports whether the key is one of the known report keys.
+func (k ReportKey) IsValid() bool {
+	for _, known := range AllReportKeys() {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseReportKey normalizes external input (trims spaces, upper-cases) and validates it.
+// Use it wherever a report key arrives from users or callbacks rather than from the database.
+func ParseReportKey(raw string) (ReportKey, error) {
+	key := ReportKey(strings.ToUpper(strings.TrimSpace(raw)))
+	if !key.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknownReportKey, raw)
+	}
+	return key, nil
+}
+
 // InteractionStatus represents the state of a teacher's response to a report query.
 type InteractionStatus string // FR6.1 [cite: 72]
 
@@ -18,8 +69,10 @@ const (
 	StatusAnsweredYes             InteractionStatus = "ANSWERED_YES"
 	StatusAnsweredNo              InteractionStatus = "ANSWERED_NO"
 	StatusAwaitingReminder1H      InteractionStatus = "AWAITING_REMINDER_1H"       // FR4.2 [cite: 66, 67]
+	StatusAwaitingReminder4H      InteractionStatus = "AWAITING_REMINDER_4H"       // Optional tier after the 1-hour reminder
 	StatusAwaitingReminderNextDay InteractionStatus = "AWAITING_REMINDER_NEXT_DAY" // FR4.3 [cite: 68]
 	StatusNextDayReminderSent     InteractionStatus = "NEXT_DAY_REMINDER_SENT"
+	StatusCancelled               InteractionStatus = "CANCELLED" // Terminal: the status's cycle no longer exists, so it is never asked again
 	// StatusCycleFullyConfirmed might be a status for the teacher overall, rather than per report.
 	// For now, individual report statuses cover FR6.1 [cite: 72]
 )
@@ -31,3 +84,16 @@ const (
 	CycleTypeMidMonth CycleType = "MID_MONTH" // For 15th of month notifications [cite: 14, 56]
 	CycleTypeEndMonth CycleType = "END_MONTH" // For last day of month notifications [cite: 14, 57]
 )
+
+// DisplayName renders the cycle type for user-facing messages. Logs keep the raw value.
+// Unknown types fall back to the raw value.
+func (t CycleType) DisplayName() string {
+	switch t {
+	case CycleTypeMidMonth:
+		return "середина месяца"
+	case CycleTypeEndMonth:
+		return "конец месяца"
+	default:
+		return string(t)
+	}
+}
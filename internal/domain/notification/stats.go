@@ -0,0 +1,37 @@
+// internal/domain/notification/stats.go
+package notification
+
+// ResponseRateStats aggregates report statuses of all cycles within a date range.
+type ResponseRateStats struct {
+	TotalStatuses     int // Report statuses in the range
+	ConfirmedStatuses int // Statuses answered "Yes"
+	FirstAskConfirmed int // Statuses answered "Yes" without any reminder (ResponseAttempts == 0)
+	TeachersCount     int // Distinct teachers with at least one status
+	TotalReminders    int // Sum of ResponseAttempts
+}
+
+// ConfirmationRate returns the share of confirmed statuses in percent.
+func (s *ResponseRateStats) ConfirmationRate() float64 {
+	return Percentage(s.ConfirmedStatuses, s.TotalStatuses)
+}
+
+// FirstAskRate returns the share of statuses confirmed on the first ask in percent.
+func (s *ResponseRateStats) FirstAskRate() float64 {
+	return Percentage(s.FirstAskConfirmed, s.TotalStatuses)
+}
+
+// AverageRemindersPerTeacher returns how many reminders a teacher needed on average.
+func (s *ResponseRateStats) AverageRemindersPerTeacher() float64 {
+	if s.TeachersCount == 0 {
+		return 0
+	}
+	return float64(s.TotalReminders) / float64(s.TeachersCount)
+}
+
+// Percentage returns part of total in percent, or 0 when total is 0.
+func Percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
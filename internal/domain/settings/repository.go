@@ -0,0 +1,17 @@
+// internal/domain/settings/repository.go
+package settings
+
+import "context"
+
+// Keys of runtime-adjustable settings.
+const (
+	KeyManagerTelegramID         = "manager_telegram_id"
+	KeyManagerCriticalReportKeys = "manager_critical_report_keys"
+)
+
+// Repository persists key/value system settings that admins can change without a restart.
+type Repository interface {
+	// Get returns the stored value, or the database package's ErrSettingNotFound when the key was never set.
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string) error
+}
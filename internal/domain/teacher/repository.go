@@ -8,8 +8,21 @@ import (
 type Repository interface {
 	Create(ctx context.Context, teacher *Teacher) error
 	GetByID(ctx context.Context, id int64) (*Teacher, error)
+	// GetByIDs loads several teachers in one query. IDs without a row are simply absent from the map.
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]*Teacher, error)
 	GetByTelegramID(ctx context.Context, telegramID int64) (*Teacher, error)
 	Update(ctx context.Context, teacher *Teacher) error // Should handle updates to FirstName, LastName, IsActive
-	ListActive(ctx context.Context) ([]*Teacher, error)
-	ListAll(ctx context.Context) ([]*Teacher, error) // For admin purposes
+	ListActive(ctx context.Context) ([]*Teacher, error) // Ordered by first name, last name, then ID
+	ListAll(ctx context.Context) ([]*Teacher, error)    // For admin purposes
+	CountActive(ctx context.Context) (int, error)
+	// CountActiveWithNameKey counts active teachers other than excludeID whose full name has the given NameKey.
+	CountActiveWithNameKey(ctx context.Context, nameKey string, excludeID int64) (int, error)
+	// UpsertTeacher inserts the teacher or updates the row with the same Telegram ID, reporting whether a row was created.
+	// An inactive teacher is only reactivated when allowReactivate is true. t is refreshed from the stored row.
+	UpsertTeacher(ctx context.Context, t *Teacher, allowReactivate bool) (created bool, err error)
+	// FindDuplicateTelegramIDs is a diagnostic returning Telegram IDs shared by more than one row.
+	FindDuplicateTelegramIDs(ctx context.Context) ([]int64, error)
+	// WithTx runs fn against a repository bound to one transaction: everything fn did is committed when it
+	// returns nil and rolled back when it returns an error.
+	WithTx(ctx context.Context, fn func(txRepo Repository) error) error
 }
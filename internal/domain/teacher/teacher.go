@@ -2,16 +2,73 @@ package teacher
 
 import (
 	"database/sql"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // Teacher represents a teacher in the system.
 type Teacher struct {
-	ID         int64
-	TelegramID int64
-	FirstName  string
-	LastName   sql.NullString // To handle optional last name
-	IsActive   bool
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID                      int64
+	TelegramID              int64
+	FirstName               string
+	LastName                sql.NullString // To handle optional last name
+	IsActive                bool
+	NotifyManagerOnComplete bool           // Whether the manager is pinged once this teacher confirms all reports
+	WorkDays                WorkDays       // Weekdays reminders may be sent on; zero value means every day
+	HasStartedBot           bool           // Set once the teacher has interacted with the bot; Telegram blocks messages before that
+	ReminderDelayOverride   sql.NullInt64  // Seconds until the reminder after a "No"; replaces the cycle's delay when set
+	DisplayName             sql.NullString // Name to address the teacher by (e.g. a nickname); FirstName/LastName stay the record
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// FullName returns the first and last name separated by a space, or just the first name
+// when no last name is stored.
+func (t *Teacher) FullName() string {
+	if t.LastName.Valid && strings.TrimSpace(t.LastName.String) != "" {
+		return t.FirstName + " " + t.LastName.String
+	}
+	return t.FirstName
+}
+
+// GreetingName returns the name to address the teacher by: the display name when set, otherwise the first name.
+func (t *Teacher) GreetingName() string {
+	if t.DisplayName.Valid && strings.TrimSpace(t.DisplayName.String) != "" {
+		return t.DisplayName.String
+	}
+	return t.FirstName
+}
+
+// ReminderDelay returns the teacher's own reminder delay, if one is set.
+func (t *Teacher) ReminderDelay() (time.Duration, bool) {
+	if !t.ReminderDelayOverride.Valid {
+		return 0, false
+	}
+	return time.Duration(t.ReminderDelayOverride.Int64) * time.Second, true
+}
+
+// NameKey normalizes a name for comparing teachers: lower case, with runs of whitespace collapsed to one space.
+func NameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// FormatName title-cases every word of a name, including hyphenated parts
+// (e.g. "иВАН пЕТРОВ-водкин" -> "Иван Петров-Водкин"). It is meant for display only.
+func FormatName(name string) string {
+	runes := []rune(name)
+	startOfWord := true
+	for i, r := range runes {
+		if unicode.IsSpace(r) || r == '-' {
+			startOfWord = true
+			continue
+		}
+		if startOfWord {
+			runes[i] = unicode.ToUpper(r)
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+		startOfWord = false
+	}
+	return string(runes)
 }
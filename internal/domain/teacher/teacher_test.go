@@ -0,0 +1,39 @@
+package teacher
+
+import "testing"
+
+func TestFormatName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "иВАН пЕТРОВ-водкин", want: "Иван Петров-Водкин"},
+		{name: "ANNA", want: "Anna"},
+		{name: "john  smith", want: "John  Smith"},
+		{name: "mary-jane", want: "Mary-Jane"},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := FormatName(tt.name); got != tt.want {
+			t.Errorf("FormatName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{a: "Иван Петров", b: "иван петров", same: true},
+		{a: "Иван  Петров ", b: " Иван Петров", same: true},
+		{a: "Anna\tSmith", b: "anna smith", same: true},
+		{a: "Иван Петров", b: "Иван Сидоров", same: false},
+		{a: "Иван", b: "Иван Петров", same: false},
+	}
+	for _, tt := range tests {
+		if got := NameKey(tt.a) == NameKey(tt.b); got != tt.same {
+			t.Errorf("NameKey(%q) == NameKey(%q) is %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package teacher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkDays is a bitmask of weekdays a teacher wants to receive reminders on (bit N = time.Weekday N).
+// The zero value means "every day", so teachers without a configured schedule are unaffected.
+type WorkDays uint8
+
+// AllWorkDays is the explicit "every day" value; it behaves the same as the zero value.
+const AllWorkDays WorkDays = 0
+
+// weekdayNames maps accepted input tokens to weekdays. Both English and Russian abbreviations are accepted.
+var weekdayNames = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+	"пн": time.Monday, "вт": time.Tuesday, "ср": time.Wednesday, "чт": time.Thursday,
+	"пт": time.Friday, "сб": time.Saturday, "вс": time.Sunday,
+}
+
+// Includes reports whether the teacher works on the given weekday.
+func (w WorkDays) Includes(day time.Weekday) bool {
+	if w == AllWorkDays {
+		return true
+	}
+	return w&(1<<uint(day)) != 0
+}
+
+// NextWorkDay returns the first moment after from, shifted by whole days, that falls on a work day.
+// The time of day is preserved.
+func (w WorkDays) NextWorkDay(from time.Time) time.Time {
+	for i := 1; i <= 7; i++ {
+		candidate := from.AddDate(0, 0, i)
+		if w.Includes(candidate.Weekday()) {
+			return candidate
+		}
+	}
+	return from.AddDate(0, 0, 1) // Unreachable for a non-empty mask; kept as a safe fallback
+}
+
+// String renders the mask as a comma-separated list of English abbreviations, Monday first.
+func (w WorkDays) String() string {
+	if w == AllWorkDays {
+		return "all"
+	}
+	names := make([]string, 0, 7)
+	for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		if w.Includes(day) {
+			names = append(names, day.String()[:3])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseWorkDays parses a comma-separated weekday list such as "Mon,Wed,Fri" or "пн,ср,пт".
+// "all" resets the schedule to every day.
+func ParseWorkDays(raw string) (WorkDays, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return AllWorkDays, fmt.Errorf("work days list is empty")
+	}
+	if raw == "all" {
+		return AllWorkDays, nil
+	}
+
+	var mask WorkDays
+	for _, token := range strings.Split(raw, ",") {
+		day, ok := weekdayNames[strings.TrimSpace(token)]
+		if !ok {
+			return AllWorkDays, fmt.Errorf("unknown weekday %q", token)
+		}
+		mask |= 1 << uint(day)
+	}
+	return mask, nil
+}
@@ -0,0 +1,51 @@
+package teacher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorkDays(t *testing.T) {
+	weekdays := WorkDays(1<<uint(time.Monday) | 1<<uint(time.Wednesday) | 1<<uint(time.Friday))
+	tests := []struct {
+		raw     string
+		want    WorkDays
+		wantErr bool
+	}{
+		{raw: "Mon,Wed,Fri", want: weekdays},
+		{raw: "пн, ср, пт", want: weekdays},
+		{raw: " mon , WED,fri ", want: weekdays},
+		{raw: "sun", want: WorkDays(1 << uint(time.Sunday))},
+		{raw: "all", want: AllWorkDays},
+		{raw: "ALL", want: AllWorkDays},
+		{raw: "", wantErr: true},
+		{raw: "mon,funday", wantErr: true},
+		{raw: "mon,,fri", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWorkDays(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWorkDays(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseWorkDays(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestWorkDaysIncludes(t *testing.T) {
+	mask, err := ParseWorkDays("mon,fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		want := day == time.Monday || day == time.Friday
+		if got := mask.Includes(day); got != want {
+			t.Errorf("%v.Includes(%v) = %v, want %v", mask, day, got, want)
+		}
+		if !AllWorkDays.Includes(day) {
+			t.Errorf("AllWorkDays.Includes(%v) = false", day)
+		}
+	}
+}
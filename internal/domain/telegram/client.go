@@ -1,9 +1,27 @@
 package telegram
 
-import "gopkg.in/telebot.v3"
+import (
+	"fmt"
+
+	"gopkg.in/telebot.v3"
+)
+
+// ErrSendCircuitOpen is returned without contacting Telegram while sends are paused after repeated failures
+// (e.g. a Telegram outage). Callers should treat it as "try again later", not as a permanent failure.
+var ErrSendCircuitOpen = fmt.Errorf("telegram sends are paused after repeated failures")
 
 // Client defines an interface for sending messages via a Telegram bot.
 // This helps in decoupling the application logic from the specific bot library.
 type Client interface {
 	SendMessage(recipientChatID int64, text string, options *telebot.SendOptions) error
+	// SendLongMessage sends text split into MaxMessageLength chunks (see SplitMessage), in order.
+	SendLongMessage(recipientChatID int64, text string, options *telebot.SendOptions) error
+	// SendDocument uploads the file at filePath, presenting it to the recipient as fileName.
+	SendDocument(recipientChatID int64, filePath string, fileName string, caption string) error
+}
+
+// BotIdentity is the Telegram account the configured bot token belongs to.
+type BotIdentity struct {
+	ID       int64
+	Username string
 }
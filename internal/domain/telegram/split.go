@@ -0,0 +1,59 @@
+package telegram
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is Telegram's limit for a single text message, in characters.
+const MaxMessageLength = 4096
+
+// SplitMessage splits text into chunks of at most limit characters, breaking only between lines.
+// A single line longer than limit is the one exception and is cut into limit-sized pieces.
+func SplitMessage(text string, limit int) []string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if currentLen > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLen := utf8.RuneCountInString(line)
+		if currentLen+lineLen <= limit {
+			current.WriteString(line)
+			currentLen += lineLen
+			continue
+		}
+		flush()
+		for lineLen > limit {
+			cut := runeOffset(line, limit)
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+			lineLen -= limit
+		}
+		current.WriteString(line)
+		currentLen = lineLen
+	}
+	flush()
+	return chunks
+}
+
+// runeOffset returns the byte offset of the n-th rune in s.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
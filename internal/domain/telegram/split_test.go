@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{name: "fits", text: "short", limit: 10, want: []string{"short"}},
+		{name: "exactly at limit", text: "abcde", limit: 5, want: []string{"abcde"}},
+		{name: "no limit", text: "anything", limit: 0, want: []string{"anything"}},
+		{name: "breaks between lines", text: "aaa\nbbb\nccc", limit: 8, want: []string{"aaa\nbbb\n", "ccc"}},
+		{name: "long line is cut", text: "abcdefgh", limit: 3, want: []string{"abc", "def", "gh"}},
+		{name: "long line after short one", text: "ab\ncdefgh", limit: 4, want: []string{"ab\n", "cdef", "gh"}},
+		{name: "counts characters, not bytes", text: "привет\nмир", limit: 7, want: []string{"привет\n", "мир"}},
+		{name: "cuts multi-byte runes whole", text: "абвгде", limit: 4, want: []string{"абвг", "де"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitMessage(tt.text, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("SplitMessage(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			if strings.Join(got, "") != tt.text {
+				t.Errorf("chunks do not add up to the original text")
+			}
+			for _, chunk := range got {
+				if tt.limit > 0 && utf8.RuneCountInString(chunk) > tt.limit {
+					t.Errorf("chunk %q is longer than %d characters", chunk, tt.limit)
+				}
+			}
+		})
+	}
+}
+
+func TestRuneOffset(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want int
+	}{
+		{s: "abc", n: 0, want: 0},
+		{s: "abc", n: 2, want: 2},
+		{s: "abc", n: 3, want: 3},
+		{s: "abc", n: 10, want: 3},
+		{s: "ая", n: 1, want: 2},
+		{s: "", n: 1, want: 0},
+	}
+	for _, tt := range tests {
+		if got := runeOffset(tt.s, tt.n); got != tt.want {
+			t.Errorf("runeOffset(%q, %d) = %d, want %d", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
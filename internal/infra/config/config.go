@@ -5,8 +5,8 @@ import (
 	"os"
 	"strconv"
 	"strings" // For LogLevel normalization
-
-	"github.com/joho/godotenv"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
 )
 
 // AppConfig holds all configuration for the application
@@ -15,19 +15,59 @@ type AppConfig struct {
 	DatabaseURL                  string
 	AdminTelegramID              int64
 	ManagerTelegramID            int64
+	AllowedChatIDs               []int64 // Group chats the bot responds in besides private chats (ALLOWED_CHAT_IDS); empty for private chats only
 	LogLevel                     string
 	Environment                  string
 	CronSpec15th                 string
-	CronSpecDailyCheckForLastDay string // For the daily check for last day of month
-	CronSpecReminderCheck        string // For checking 1-hour reminders
-	CronSpecNextDayCheck         string // For checking next-day reminders
+	CronSpecDailyCheckForLastDay string         // For the daily check for last day of month
+	CronSpecReminderCheck        string         // For checking 1-hour reminders
+	CronSpecNextDayCheck         string         // For checking next-day reminders
+	CronSpecNextDayCheckEndMonth string         // Separate, usually earlier next-day check for end-of-month cycles; empty uses CronSpecNextDayCheck
+	CronSpecPrenotify            string         // Daily check whether tomorrow is a cycle day, for the eve-of-cycle notice
+	PrenotifyEnabled             bool           // Tell teachers the day before a cycle that it is coming
+	CycleCacheTTL                time.Duration  // How long cycle lookups are cached in memory; 0 disables the cache
+	SandboxMode                  bool           // Redirect all outgoing messages to the admin (demos/staging)
+	NormalizeNameCasing          bool           // Title-case teacher names when displaying them
+	WelcomeMessageEnabled        bool           // Send a welcome message to teachers added via /add_teacher
+	WelcomeMessageTemplate       string         // "{name}" is replaced with the teacher's first name
+	DeactivationMessageEnabled   bool           // Tell teachers removed via /remove_teacher that reminders stop
+	DeactivationMessageTemplate  string         // "{name}" is replaced with the teacher's first name
+	MaxTeacherNameLength         int            // Longest accepted first or last name, in characters
+	HTTPAddr                     string         // Listen address of the health/stats HTTP server; empty disables it
+	UnknownCallbackAction        string         // respond, log or ignore: how to treat callbacks no handler recognizes
+	OverlappingCycleAction       string         // warn or skip: what a new cycle does with teachers still busy with another open cycle
+	LogLevelRevertAfter          time.Duration  // How long a /loglevel override lasts before reverting; 0 keeps it
+	DBRetryAttempts              int            // Attempts for hot-path queries when the database is unreachable; 1 disables retrying
+	DBRetryBaseDelay             time.Duration  // Backoff before the first retry; doubled for every further retry
+	StrictStatusUpdates          bool           // Reject report status updates whose teacher, cycle or report key differ from the stored row
+	SchedulerRetryDelay          time.Duration  // Wait before re-running a cycle initiation that failed because the database was down
+	SchedulerMaxRetries          int            // Re-runs of such an initiation before giving up; 0 only alerts the admin
+	TelegramBreakerThreshold     int            // Consecutive Telegram send failures that pause sending; 0 disables the breaker
+	TelegramBreakerCooldown      time.Duration  // How long sending stays paused before a probe send is attempted
+	Reminder1HDelayMidMonth      time.Duration  // First reminder after a "No" in mid-month cycles
+	Reminder1HDelayEndMonth      time.Duration  // First reminder after a "No" in end-of-month cycles
+	Reminder4HDelayMidMonth      time.Duration  // Optional second reminder after the first one in mid-month cycles; 0 disables it
+	Reminder4HDelayEndMonth      time.Duration  // Optional second reminder after the first one in end-of-month cycles; 0 disables it
+	ReminderJitter               time.Duration  // Random extra delay (0..value) added to each scheduled reminder to spread sweeps; 0 disables it
+	MaxAnswerAge                 time.Duration  // Reject teacher answers given longer than this after the latest question or reminder; 0 means no limit
+	CoalesceReminders            bool           // Combine a teacher's due reminders into one message per sweep
+	ReplyKeyboardAnswers         bool           // Ask with a reply keyboard ("Да"/"Нет" as text) instead of inline buttons
+	ConfirmDestructiveCommands   bool           // Ask for a "Подтвердить"/"Отмена" click before /remove_teacher deactivates anyone
+	AnnounceReportCount          bool           // Tell teachers in the first question how many reports the cycle asks about
+	ReminderAlreadyDoneNote      bool           // Add "Если уже заполнили — нажмите 'Да'." to reminders (never to the first question)
+	AdminTimezone                string         // IANA zone for timestamps shown to the admin (ADMIN_TIMEZONE); empty for server local time
+	AdminLocation                *time.Location // AdminTimezone, loaded
+	DateFormat                   string         // Go layout of calendar dates (e.g. cycle dates) in admin and manager messages
+	ManagerCriticalReports       string         // Comma-separated report keys the manager confirmation waits for; "all" or empty for every report
+	ManagerKickoffAnnouncement   bool           // Send the manager a summary when a cycle is initiated
 }
 
 // Load reads configuration from environment variables and .env file (if present).
+// It can be called again at runtime to re-read both (see /reload_config).
 func Load() (*AppConfig, error) {
-	// Attempt to load .env file. Errors are ignored if the file doesn't exist.
-	// godotenv.Load will not override existing env variables.
-	_ = godotenv.Load()
+	// Attempt to load .env file. It is skipped if the file doesn't exist.
+	// Variables set in the real environment are not overridden.
+	loadDotEnv()
 
 	cfg := &AppConfig{}
 	var err error
@@ -51,13 +91,18 @@ func Load() (*AppConfig, error) {
 		return nil, fmt.Errorf("invalid ADMIN_TELEGRAM_ID: %w", err)
 	}
 
+	// MANAGER_TELEGRAM_ID is optional: when unset (0), manager-facing messages are skipped.
 	managerIDStr := os.Getenv("MANAGER_TELEGRAM_ID")
-	if managerIDStr == "" {
-		return nil, fmt.Errorf("MANAGER_TELEGRAM_ID is not set")
+	if managerIDStr != "" {
+		cfg.ManagerTelegramID, err = strconv.ParseInt(managerIDStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MANAGER_TELEGRAM_ID: %w", err)
+		}
 	}
-	cfg.ManagerTelegramID, err = strconv.ParseInt(managerIDStr, 10, 64)
+
+	cfg.AllowedChatIDs, err = getEnvInt64List("ALLOWED_CHAT_IDS")
 	if err != nil {
-		return nil, fmt.Errorf("invalid MANAGER_TELEGRAM_ID: %w", err)
+		return nil, err
 	}
 
 	cfg.LogLevel = strings.ToLower(os.Getenv("LOG_LEVEL"))
@@ -88,6 +133,271 @@ func Load() (*AppConfig, error) {
 	if cfg.CronSpecNextDayCheck == "" {
 		cfg.CronSpecNextDayCheck = "0 9 * * *" // Default: 9 AM daily
 	}
+	cfg.CronSpecNextDayCheckEndMonth = os.Getenv("CRON_SPEC_NEXT_DAY_CHECK_END_MONTH") // Default: same sweep as mid-month
+
+	cfg.CronSpecPrenotify = os.Getenv("CRON_SPEC_PRENOTIFY")
+	if cfg.CronSpecPrenotify == "" {
+		cfg.CronSpecPrenotify = "0 18 * * *" // Default: 6 PM daily
+	}
+
+	cfg.PrenotifyEnabled, err = getEnvBool("PRENOTIFY_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.CycleCacheTTL, err = getEnvDuration("CYCLE_CACHE_TTL", 1*time.Minute) // Default: 1 minute
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SandboxMode, err = getEnvBool("SANDBOX_MODE", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.NormalizeNameCasing, err = getEnvBool("NORMALIZE_NAME_CASING", true)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.WelcomeMessageEnabled, err = getEnvBool("WELCOME_MESSAGE_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.WelcomeMessageTemplate = os.Getenv("WELCOME_MESSAGE_TEMPLATE")
+	if cfg.WelcomeMessageTemplate == "" {
+		cfg.WelcomeMessageTemplate = "Здравствуйте, {name}! Вас добавили в бот напоминаний о заполнении таблиц. Я буду присылать вопросы 15-го числа и в последний день месяца."
+	}
+
+	cfg.DeactivationMessageEnabled, err = getEnvBool("DEACTIVATION_MESSAGE_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DeactivationMessageTemplate = os.Getenv("DEACTIVATION_MESSAGE_TEMPLATE")
+	if cfg.DeactivationMessageTemplate == "" {
+		cfg.DeactivationMessageTemplate = "Здравствуйте, {name}! Ваш аккаунт в боте напоминаний о заполнении таблиц деактивирован, напоминания больше приходить не будут. Если это ошибка, свяжитесь с администратором."
+	}
+
+	cfg.LogLevelRevertAfter, err = getEnvDuration("LOG_LEVEL_REVERT_AFTER", 30*time.Minute) // Default: 30 minutes
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.DBRetryAttempts, err = getEnvInt("DB_RETRY_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DBRetryAttempts < 1 {
+		return nil, fmt.Errorf("invalid DB_RETRY_ATTEMPTS: must be at least 1")
+	}
+	cfg.DBRetryBaseDelay, err = getEnvDuration("DB_RETRY_BASE_DELAY", 200*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	cfg.StrictStatusUpdates, err = getEnvBool("STRICT_STATUS_UPDATES", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SchedulerRetryDelay, err = getEnvDuration("SCHEDULER_RETRY_DELAY", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SchedulerMaxRetries, err = getEnvInt("SCHEDULER_MAX_RETRIES", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.TelegramBreakerThreshold, err = getEnvInt("TELEGRAM_BREAKER_THRESHOLD", 5)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TelegramBreakerCooldown, err = getEnvDuration("TELEGRAM_BREAKER_COOLDOWN", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.MaxTeacherNameLength, err = getEnvInt("MAX_TEACHER_NAME_LENGTH", 128)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.HTTPAddr = os.Getenv("HTTP_ADDR") // Default: disabled
+
+	cfg.UnknownCallbackAction = strings.ToLower(os.Getenv("UNKNOWN_CALLBACK_ACTION"))
+	if cfg.UnknownCallbackAction == "" {
+		cfg.UnknownCallbackAction = "respond" // Default: tell the user the action is unknown
+	}
+
+	cfg.OverlappingCycleAction = strings.ToLower(os.Getenv("OVERLAPPING_CYCLE_ACTION"))
+	if cfg.OverlappingCycleAction == "" {
+		cfg.OverlappingCycleAction = "warn" // Default: ask anyway, but alert the admin
+	}
+
+	// REMINDER_1H_DELAY and REMINDER_4H_DELAY apply to both cycle types unless overridden per type.
+	reminder1HDelay, err := getEnvDuration("REMINDER_1H_DELAY", 1*time.Hour) // Default: 1 hour
+	if err != nil {
+		return nil, err
+	}
+	cfg.Reminder1HDelayMidMonth, err = getEnvDuration("REMINDER_1H_DELAY_MID_MONTH", reminder1HDelay)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Reminder1HDelayEndMonth, err = getEnvDuration("REMINDER_1H_DELAY_END_MONTH", reminder1HDelay)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Reminder1HDelayMidMonth == 0 || cfg.Reminder1HDelayEndMonth == 0 {
+		return nil, fmt.Errorf("REMINDER_1H_DELAY values must be positive")
+	}
+
+	reminder4HDelay, err := getEnvDuration("REMINDER_4H_DELAY", 0) // Default: tier disabled
+	if err != nil {
+		return nil, err
+	}
+	cfg.Reminder4HDelayMidMonth, err = getEnvDuration("REMINDER_4H_DELAY_MID_MONTH", reminder4HDelay)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Reminder4HDelayEndMonth, err = getEnvDuration("REMINDER_4H_DELAY_END_MONTH", reminder4HDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ReminderJitter, err = getEnvDuration("REMINDER_JITTER", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.MaxAnswerAge, err = getEnvDuration("MAX_ANSWER_AGE", 0) // Default: answers are accepted at any time
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.CoalesceReminders, err = getEnvBool("COALESCE_REMINDERS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ReplyKeyboardAnswers, err = getEnvBool("REPLY_KEYBOARD_ANSWERS", false) // Default: inline buttons
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ConfirmDestructiveCommands, err = getEnvBool("CONFIRM_DESTRUCTIVE_COMMANDS", true)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.AnnounceReportCount, err = getEnvBool("ANNOUNCE_REPORT_COUNT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ReminderAlreadyDoneNote, err = getEnvBool("REMINDER_ALREADY_DONE_NOTE", true)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.AdminTimezone = os.Getenv("ADMIN_TIMEZONE")
+	cfg.AdminLocation = time.Local // Default: server local time
+	if cfg.AdminTimezone != "" {
+		cfg.AdminLocation, err = time.LoadLocation(cfg.AdminTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ADMIN_TIMEZONE %q: %w", cfg.AdminTimezone, err)
+		}
+	}
+
+	cfg.DateFormat = os.Getenv("DATE_FORMAT")
+	if cfg.DateFormat == "" {
+		cfg.DateFormat = notification.DefaultDateLayout // Default: ISO dates
+	}
+	if err := validateDateLayout(cfg.DateFormat); err != nil {
+		return nil, fmt.Errorf("invalid DATE_FORMAT %q: %w", cfg.DateFormat, err)
+	}
+
+	cfg.ManagerCriticalReports = os.Getenv("MANAGER_CRITICAL_REPORTS")
+	if cfg.ManagerCriticalReports == "" {
+		cfg.ManagerCriticalReports = "all" // Default: the manager is told once every report is confirmed
+	}
+
+	cfg.ManagerKickoffAnnouncement, err = getEnvBool("MANAGER_KICKOFF_ANNOUNCEMENT", false)
+	if err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
+
+// getEnvBool parses a boolean environment variable, returning def when it is unset.
+func getEnvBool(key string, def bool) (bool, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// getEnvInt64List parses a comma-separated list of integers, returning nil when it is unset.
+func getEnvInt64List(key string) ([]int64, error) {
+	raw := os.Getenv(key)
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var values []int64
+	for _, part := range strings.Split(raw, ",") {
+		value, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// getEnvInt parses an integer environment variable, returning def when it is unset.
+func getEnvInt(key string, def int) (int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// getEnvDuration parses a non-negative Go duration environment variable, returning def when it is unset.
+// validateDateLayout checks that a Go time layout renders a sample date so that the day, month and year
+// can be read back, which catches typos such as "DD.MM.YYYY" that Go would print verbatim.
+func validateDateLayout(layout string) error {
+	sample := time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, sample.Format(layout))
+	if err != nil {
+		return err
+	}
+	if !parsed.Equal(sample) {
+		return fmt.Errorf("the layout must contain the day, month and year (e.g. 2006-01-02 or 02.01.2006)")
+	}
+	return nil
+}
+
+func getEnvDuration(key string, def time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("invalid %s: must not be negative", key)
+	}
+	return value, nil
+}
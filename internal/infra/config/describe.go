@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in Describe output.
+const redactedValue = "<redacted>"
+
+// Entry is one named setting as printed by Describe.
+type Entry struct {
+	Name  string // Environment variable the value comes from
+	Value string
+}
+
+// Describe lists the effective configuration for operators, in a stable order.
+// Secrets (the Telegram token and the database URL) are never included, only whether they are set.
+func (c *AppConfig) Describe() []Entry {
+	return []Entry{
+		{"TELEGRAM_TOKEN", redactSecret(c.TelegramToken)},
+		{"DATABASE_URL", redactSecret(c.DatabaseURL)},
+		{"ADMIN_TELEGRAM_ID", strconv.FormatInt(c.AdminTelegramID, 10)},
+		{"MANAGER_TELEGRAM_ID", strconv.FormatInt(c.ManagerTelegramID, 10)},
+		{"ALLOWED_CHAT_IDS", formatInt64List(c.AllowedChatIDs)},
+		{"LOG_LEVEL", c.LogLevel},
+		{"ENVIRONMENT", c.Environment},
+		{"TZ", time.Local.String()},
+		{"ADMIN_TIMEZONE", c.AdminLocation.String()},
+		{"DATE_FORMAT", c.DateFormat},
+		{"CRON_SPEC_15TH", c.CronSpec15th},
+		{"CRON_SPEC_DAILY_FOR_LAST_DAY_CHECK", c.CronSpecDailyCheckForLastDay},
+		{"CRON_SPEC_REMINDER_CHECK", c.CronSpecReminderCheck},
+		{"CRON_SPEC_NEXT_DAY_CHECK", c.CronSpecNextDayCheck},
+		{"CRON_SPEC_NEXT_DAY_CHECK_END_MONTH", c.CronSpecNextDayCheckEndMonth},
+		{"CRON_SPEC_PRENOTIFY", c.CronSpecPrenotify},
+		{"PRENOTIFY_ENABLED", strconv.FormatBool(c.PrenotifyEnabled)},
+		{"REMINDER_1H_DELAY_MID_MONTH", c.Reminder1HDelayMidMonth.String()},
+		{"REMINDER_1H_DELAY_END_MONTH", c.Reminder1HDelayEndMonth.String()},
+		{"REMINDER_4H_DELAY_MID_MONTH", c.Reminder4HDelayMidMonth.String()},
+		{"REMINDER_4H_DELAY_END_MONTH", c.Reminder4HDelayEndMonth.String()},
+		{"REMINDER_JITTER", c.ReminderJitter.String()},
+		{"MAX_ANSWER_AGE", c.MaxAnswerAge.String()},
+		{"COALESCE_REMINDERS", strconv.FormatBool(c.CoalesceReminders)},
+		{"REPLY_KEYBOARD_ANSWERS", strconv.FormatBool(c.ReplyKeyboardAnswers)},
+		{"CONFIRM_DESTRUCTIVE_COMMANDS", strconv.FormatBool(c.ConfirmDestructiveCommands)},
+		{"ANNOUNCE_REPORT_COUNT", strconv.FormatBool(c.AnnounceReportCount)},
+		{"REMINDER_ALREADY_DONE_NOTE", strconv.FormatBool(c.ReminderAlreadyDoneNote)},
+		{"MANAGER_CRITICAL_REPORTS", c.ManagerCriticalReports},
+		{"MANAGER_KICKOFF_ANNOUNCEMENT", strconv.FormatBool(c.ManagerKickoffAnnouncement)},
+		{"CYCLE_CACHE_TTL", c.CycleCacheTTL.String()},
+		{"SANDBOX_MODE", strconv.FormatBool(c.SandboxMode)},
+		{"NORMALIZE_NAME_CASING", strconv.FormatBool(c.NormalizeNameCasing)},
+		{"WELCOME_MESSAGE_ENABLED", strconv.FormatBool(c.WelcomeMessageEnabled)},
+		{"DEACTIVATION_MESSAGE_ENABLED", strconv.FormatBool(c.DeactivationMessageEnabled)},
+		{"MAX_TEACHER_NAME_LENGTH", strconv.Itoa(c.MaxTeacherNameLength)},
+		{"HTTP_ADDR", c.HTTPAddr},
+		{"UNKNOWN_CALLBACK_ACTION", c.UnknownCallbackAction},
+		{"OVERLAPPING_CYCLE_ACTION", c.OverlappingCycleAction},
+		{"LOG_LEVEL_REVERT_AFTER", c.LogLevelRevertAfter.String()},
+		{"DB_RETRY_ATTEMPTS", strconv.Itoa(c.DBRetryAttempts)},
+		{"DB_RETRY_BASE_DELAY", c.DBRetryBaseDelay.String()},
+		{"STRICT_STATUS_UPDATES", strconv.FormatBool(c.StrictStatusUpdates)},
+		{"SCHEDULER_RETRY_DELAY", c.SchedulerRetryDelay.String()},
+		{"SCHEDULER_MAX_RETRIES", strconv.Itoa(c.SchedulerMaxRetries)},
+		{"TELEGRAM_BREAKER_THRESHOLD", strconv.Itoa(c.TelegramBreakerThreshold)},
+		{"TELEGRAM_BREAKER_COOLDOWN", c.TelegramBreakerCooldown.String()},
+	}
+}
+
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// String renders an entry as "NAME=value", quoting the value so empty settings stay visible.
+func (e Entry) String() string {
+	return fmt.Sprintf("%s=%q", e.Name, e.Value)
+}
+
+// formatInt64List renders IDs the way they are configured: comma-separated.
+func formatInt64List(values []int64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.FormatInt(v, 10)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joho/godotenv"
+)
+
+// reloadableSettings are the environment variables /reload_config applies while the bot runs.
+// Everything else is wired into long-lived objects at startup and only changes after a restart.
+var reloadableSettings = map[string]bool{
+	"CRON_SPEC_15TH":                     true,
+	"CRON_SPEC_DAILY_FOR_LAST_DAY_CHECK": true,
+	"CRON_SPEC_REMINDER_CHECK":           true,
+	"CRON_SPEC_NEXT_DAY_CHECK":           true,
+	"CRON_SPEC_NEXT_DAY_CHECK_END_MONTH": true,
+	"CRON_SPEC_PRENOTIFY":                true,
+	"PRENOTIFY_ENABLED":                  true,
+	"REMINDER_1H_DELAY_MID_MONTH":        true,
+	"REMINDER_1H_DELAY_END_MONTH":        true,
+	"REMINDER_4H_DELAY_MID_MONTH":        true,
+	"REMINDER_4H_DELAY_END_MONTH":        true,
+	"REMINDER_JITTER":                    true,
+	"MAX_ANSWER_AGE":                     true,
+	"COALESCE_REMINDERS":                 true,
+	"ANNOUNCE_REPORT_COUNT":              true,
+	"REMINDER_ALREADY_DONE_NOTE":         true,
+	"MANAGER_KICKOFF_ANNOUNCEMENT":       true,
+	"NORMALIZE_NAME_CASING":              true,
+}
+
+// ReloadDiff lists the settings that differ between the running configuration and a freshly loaded one.
+type ReloadDiff struct {
+	Applicable      []string // Names of settings that can be applied at runtime
+	RestartRequired []string // Names of settings that only take effect after a restart
+}
+
+// Empty reports whether nothing changed.
+func (d ReloadDiff) Empty() bool {
+	return len(d.Applicable) == 0 && len(d.RestartRequired) == 0
+}
+
+// DiffForReload compares c with next setting by setting.
+func (c *AppConfig) DiffForReload(next *AppConfig) ReloadDiff {
+	var diff ReloadDiff
+	current, fresh := c.Describe(), next.Describe()
+	for i := range current {
+		name := current[i].Name
+		switch name {
+		case "TELEGRAM_TOKEN", "DATABASE_URL":
+			continue // Redacted in Describe; compared below
+		}
+		if current[i].Value == fresh[i].Value {
+			continue
+		}
+		if reloadableSettings[name] {
+			diff.Applicable = append(diff.Applicable, name)
+		} else {
+			diff.RestartRequired = append(diff.RestartRequired, name)
+		}
+	}
+	if c.TelegramToken != next.TelegramToken {
+		diff.RestartRequired = append(diff.RestartRequired, "TELEGRAM_TOKEN")
+	}
+	if c.DatabaseURL != next.DatabaseURL {
+		diff.RestartRequired = append(diff.RestartRequired, "DATABASE_URL")
+	}
+	return diff
+}
+
+// WithReloadable returns a copy of c with the runtime-adjustable settings taken from next; c itself is not
+// modified.
+func (c *AppConfig) WithReloadable(next *AppConfig) *AppConfig {
+	merged := *c
+	merged.CronSpec15th = next.CronSpec15th
+	merged.CronSpecDailyCheckForLastDay = next.CronSpecDailyCheckForLastDay
+	merged.CronSpecReminderCheck = next.CronSpecReminderCheck
+	merged.CronSpecNextDayCheck = next.CronSpecNextDayCheck
+	merged.CronSpecNextDayCheckEndMonth = next.CronSpecNextDayCheckEndMonth
+	merged.CronSpecPrenotify = next.CronSpecPrenotify
+	merged.PrenotifyEnabled = next.PrenotifyEnabled
+	merged.Reminder1HDelayMidMonth = next.Reminder1HDelayMidMonth
+	merged.Reminder1HDelayEndMonth = next.Reminder1HDelayEndMonth
+	merged.Reminder4HDelayMidMonth = next.Reminder4HDelayMidMonth
+	merged.Reminder4HDelayEndMonth = next.Reminder4HDelayEndMonth
+	merged.ReminderJitter = next.ReminderJitter
+	merged.MaxAnswerAge = next.MaxAnswerAge
+	merged.CoalesceReminders = next.CoalesceReminders
+	merged.AnnounceReportCount = next.AnnounceReportCount
+	merged.ReminderAlreadyDoneNote = next.ReminderAlreadyDoneNote
+	merged.ManagerKickoffAnnouncement = next.ManagerKickoffAnnouncement
+	merged.NormalizeNameCasing = next.NormalizeNameCasing
+	return &merged
+}
+
+// Live is the running configuration as handlers see it. A reload stores a new snapshot instead of editing
+// the current one, so a handler reading Current while /reload_config runs gets either the old or the new
+// configuration, never a mix.
+type Live struct {
+	applyMu sync.Mutex // Serializes ApplyReloadable so concurrent reloads don't drop each other's changes
+	current atomic.Pointer[AppConfig]
+}
+
+// NewLive wraps the configuration loaded at startup.
+func NewLive(cfg *AppConfig) *Live {
+	l := &Live{}
+	l.current.Store(cfg)
+	return l
+}
+
+// Current returns the configuration in effect. Callers must treat it as read-only.
+func (l *Live) Current() *AppConfig {
+	return l.current.Load()
+}
+
+// ApplyReloadable makes the runtime-adjustable settings of next current and returns the new snapshot.
+func (l *Live) ApplyReloadable(next *AppConfig) *AppConfig {
+	l.applyMu.Lock()
+	defer l.applyMu.Unlock()
+	merged := l.current.Load().WithReloadable(next)
+	l.current.Store(merged)
+	return merged
+}
+
+var (
+	dotEnvMu   sync.Mutex
+	dotEnvKeys = make(map[string]bool) // Variables whose value came from .env rather than the real environment
+)
+
+// loadDotEnv applies the .env file (if present) to the process environment. Variables set in the real
+// environment win, as with godotenv.Load, but values taken from .env earlier are refreshed, so a reload
+// picks up edits to the file.
+func loadDotEnv() {
+	values, err := godotenv.Read()
+	if err != nil {
+		return // No .env file
+	}
+	dotEnvMu.Lock()
+	defer dotEnvMu.Unlock()
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set && !dotEnvKeys[key] {
+			continue
+		}
+		_ = os.Setenv(key, value)
+		dotEnvKeys[key] = true
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApplyReloadableSwapsSnapshot(t *testing.T) {
+	startup := &AppConfig{AdminTelegramID: 1, CronSpec15th: "0 10 15 * *", MaxAnswerAge: time.Hour}
+	live := NewLive(startup)
+	next := &AppConfig{AdminTelegramID: 2, CronSpec15th: "0 11 15 * *", MaxAnswerAge: 2 * time.Hour}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() { // Handlers read while the reload runs; -race flags any in-place write
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = live.Current().CronSpec15th
+			}
+		}()
+	}
+	applied := live.ApplyReloadable(next)
+	wg.Wait()
+
+	if live.Current() != applied {
+		t.Fatal("Current does not return the applied snapshot")
+	}
+	if applied.CronSpec15th != next.CronSpec15th || applied.MaxAnswerAge != next.MaxAnswerAge {
+		t.Errorf("reloadable settings not applied: %+v", applied)
+	}
+	if applied.AdminTelegramID != 1 {
+		t.Errorf("AdminTelegramID = %d, want the startup value 1", applied.AdminTelegramID)
+	}
+	if startup.CronSpec15th != "0 10 15 * *" || startup.MaxAnswerAge != time.Hour {
+		t.Errorf("startup configuration was modified: %+v", startup)
+	}
+}
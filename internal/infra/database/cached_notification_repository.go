@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
+)
+
+// CachedNotificationRepository decorates a notification.Repository with a short-lived
+// in-memory cache for cycle lookups. All other methods are delegated unchanged.
+// Any CreateCycle or UpdateCycleStatus call drops the whole cache so a new "latest" cycle
+// or a status change is never hidden.
+type CachedNotificationRepository struct {
+	notification.Repository
+	ttl time.Duration
+
+	mu         sync.RWMutex
+	generation uint64 // Bumped on every invalidation; guards against storing results fetched before it
+	cyclesByID map[int32]cachedCycle
+	latest     *cachedCycle
+}
+
+type cachedCycle struct {
+	cycle     notification.Cycle
+	expiresAt time.Time
+}
+
+func NewCachedNotificationRepository(next notification.Repository, ttl time.Duration) *CachedNotificationRepository {
+	return &CachedNotificationRepository{
+		Repository: next,
+		ttl:        ttl,
+		cyclesByID: make(map[int32]cachedCycle),
+	}
+}
+
+func (r *CachedNotificationRepository) CreateCycle(ctx context.Context, cycle *notification.Cycle) error {
+	err := r.Repository.CreateCycle(ctx, cycle)
+	r.invalidate() // Invalidate even on error: the insert may have committed before the error surfaced
+	return err
+}
+
+func (r *CachedNotificationRepository) UpdateCycleStatus(ctx context.Context, cycleID int32, status notification.CycleStatus) error {
+	err := r.Repository.UpdateCycleStatus(ctx, cycleID, status)
+	r.invalidate()
+	return err
+}
+
+func (r *CachedNotificationRepository) GetCycleByID(ctx context.Context, id int32) (*notification.Cycle, error) {
+	now := time.Now()
+	r.mu.RLock()
+	entry, ok := r.cyclesByID[id]
+	generation := r.generation
+	r.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		cycle := entry.cycle // Return a copy so callers can't mutate the cached value
+		return &cycle, nil
+	}
+
+	cycle, err := r.Repository.GetCycleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.cyclesByID[id] = cachedCycle{cycle: *cycle, expiresAt: now.Add(r.ttl)}
+	}
+	r.mu.Unlock()
+	return cycle, nil
+}
+
+func (r *CachedNotificationRepository) GetLatestCycle(ctx context.Context) (*notification.Cycle, error) {
+	now := time.Now()
+	r.mu.RLock()
+	entry := r.latest
+	generation := r.generation
+	r.mu.RUnlock()
+	if entry != nil && now.Before(entry.expiresAt) {
+		cycle := entry.cycle
+		return &cycle, nil
+	}
+
+	cycle, err := r.Repository.GetLatestCycle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.latest = &cachedCycle{cycle: *cycle, expiresAt: now.Add(r.ttl)}
+	}
+	r.mu.Unlock()
+	return cycle, nil
+}
+
+// invalidate drops every cached cycle.
+func (r *CachedNotificationRepository) invalidate() {
+	r.mu.Lock()
+	r.generation++
+	r.cyclesByID = make(map[int32]cachedCycle)
+	r.latest = nil
+	r.mu.Unlock()
+}
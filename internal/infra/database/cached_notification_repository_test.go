@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"teacher_notification_bot/internal/domain/notification"
+	"testing"
+	"time"
+)
+
+// countingCycleRepo serves a single cycle and counts the lookups that reach it. When block is set, the next
+// GetCycleByID reads the cycle, signals fetched and waits for release before returning it.
+type countingCycleRepo struct {
+	notification.Repository
+
+	mu      sync.Mutex
+	cycle   notification.Cycle
+	byID    int
+	latest  int
+	block   bool
+	fetched chan struct{}
+	release chan struct{}
+}
+
+func (r *countingCycleRepo) GetCycleByID(_ context.Context, id int32) (*notification.Cycle, error) {
+	r.mu.Lock()
+	if id != r.cycle.ID {
+		r.mu.Unlock()
+		return nil, ErrCycleNotFound
+	}
+	r.byID++
+	cycle := r.cycle
+	block := r.block
+	r.block = false
+	r.mu.Unlock()
+	if block {
+		close(r.fetched)
+		<-r.release
+	}
+	return &cycle, nil
+}
+
+func (r *countingCycleRepo) GetLatestCycle(context.Context) (*notification.Cycle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latest++
+	cycle := r.cycle
+	return &cycle, nil
+}
+
+func (r *countingCycleRepo) CreateCycle(_ context.Context, cycle *notification.Cycle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cycle.ID = r.cycle.ID + 1
+	r.cycle = *cycle
+	return nil
+}
+
+func (r *countingCycleRepo) UpdateCycleStatus(_ context.Context, _ int32, status notification.CycleStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cycle.Status = status
+	return nil
+}
+
+func (r *countingCycleRepo) lookups() (byID, latest int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.byID, r.latest
+}
+
+func newCountingCycleRepo() *countingCycleRepo {
+	return &countingCycleRepo{cycle: notification.Cycle{ID: 1, Type: notification.CycleTypeMidMonth, Status: notification.CycleStatusOpen}}
+}
+
+func TestCachedCycleExpiresAfterTTL(t *testing.T) {
+	backend := newCountingCycleRepo()
+	const ttl = 30 * time.Millisecond
+	cached := NewCachedNotificationRepository(backend, ttl)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := cached.GetCycleByID(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.GetLatestCycle(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if byID, latest := backend.lookups(); byID != 1 || latest != 1 {
+		t.Fatalf("within the TTL: %d ID and %d latest lookups reached the database, want 1 each", byID, latest)
+	}
+
+	time.Sleep(2 * ttl)
+	if _, err := cached.GetCycleByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.GetLatestCycle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if byID, latest := backend.lookups(); byID != 2 || latest != 2 {
+		t.Errorf("after the TTL: %d ID and %d latest lookups reached the database, want 2 each", byID, latest)
+	}
+}
+
+func TestCachedCycleInvalidatedOnWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		write      func(ctx context.Context, r *CachedNotificationRepository) error
+		wantLatest int32
+		wantStatus notification.CycleStatus
+	}{
+		{name: "CreateCycle", write: func(ctx context.Context, r *CachedNotificationRepository) error {
+			return r.CreateCycle(ctx, &notification.Cycle{Type: notification.CycleTypeEndMonth, Status: notification.CycleStatusOpen})
+		}, wantLatest: 2, wantStatus: notification.CycleStatusOpen},
+		{name: "UpdateCycleStatus", write: func(ctx context.Context, r *CachedNotificationRepository) error {
+			return r.UpdateCycleStatus(ctx, 1, notification.CycleStatusClosed)
+		}, wantLatest: 1, wantStatus: notification.CycleStatusClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cached := NewCachedNotificationRepository(newCountingCycleRepo(), time.Hour)
+			ctx := context.Background()
+			if _, err := cached.GetLatestCycle(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.write(ctx, cached); err != nil {
+				t.Fatal(err)
+			}
+
+			latest, err := cached.GetLatestCycle(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest.ID != tt.wantLatest || latest.Status != tt.wantStatus {
+				t.Errorf("latest cycle = %d (%s), want %d (%s)", latest.ID, latest.Status, tt.wantLatest, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCachedCycleFetchedBeforeInvalidationIsNotStored(t *testing.T) {
+	backend := newCountingCycleRepo()
+	backend.block = true
+	backend.fetched = make(chan struct{})
+	backend.release = make(chan struct{})
+	cached := NewCachedNotificationRepository(backend, time.Hour)
+	ctx := context.Background()
+
+	stale := make(chan *notification.Cycle)
+	go func() {
+		cycle, _ := cached.GetCycleByID(ctx, 1)
+		stale <- cycle
+	}()
+	<-backend.fetched // The lookup has read the OPEN cycle but not stored it yet
+	if err := cached.UpdateCycleStatus(ctx, 1, notification.CycleStatusClosed); err != nil {
+		t.Fatal(err)
+	}
+	close(backend.release)
+	if cycle := <-stale; cycle.Status != notification.CycleStatusOpen {
+		t.Fatalf("in-flight lookup returned %s, want the OPEN cycle it read", cycle.Status)
+	}
+
+	cycle, err := cached.GetCycleByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cycle.Status != notification.CycleStatusClosed {
+		t.Errorf("status = %s, want CLOSED: the value read before the update was cached", cycle.Status)
+	}
+}
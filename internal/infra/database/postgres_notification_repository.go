@@ -3,69 +3,542 @@ package database
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"strings"
 	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
 	"time"
 
 	"github.com/lib/pq" // For pq.Array and driver registration
+	"github.com/sirupsen/logrus"
 )
 
 // Custom errors specific to notification repository
 var ErrCycleNotFound = fmt.Errorf("notification cycle not found")
+var ErrDuplicateCycle = fmt.Errorf("a scheduled cycle with this date, type and round already exists")
 var ErrReportStatusNotFound = fmt.Errorf("teacher report status not found")
+var ErrCycleExclusionNotFound = fmt.Errorf("teacher is not excluded from this cycle")
 var ErrDuplicateReportStatus = fmt.Errorf("duplicate teacher report status (teacher_id, cycle_id, report_key)")
+var ErrStatusIdentityMismatch = fmt.Errorf("teacher report status does not match the stored teacher, cycle or report key")
+
+// BulkUpdateError is returned by BulkUpdateReportStatuses when some rows could not be updated.
+// All other rows of the batch were committed.
+type BulkUpdateError struct {
+	Failures map[int64]error // Keyed by report status ID
+}
+
+func (e *BulkUpdateError) Error() string {
+	return fmt.Sprintf("bulk update failed for %d report status(es)", len(e.Failures))
+}
+
+// cycleColumns is the column list shared by every query that loads a full cycle row.
+// Keep it in sync with scanCycle.
+const cycleColumns = `id, cycle_date, cycle_type, round, status, source, created_at`
+
+// scanCycle scans a row selected with cycleColumns.
+func scanCycle(row rowScanner) (*notification.Cycle, error) {
+	cycle := &notification.Cycle{}
+	if err := row.Scan(&cycle.ID, &cycle.CycleDate, &cycle.Type, &cycle.Round, &cycle.Status, &cycle.Source, &cycle.CreatedAt); err != nil {
+		return nil, err
+	}
+	return cycle, nil
+}
+
+// cycleDateOnly is the date normalisation shared by cycle inserts and lookups (see notification.CycleDay).
+func cycleDateOnly(t time.Time) time.Time {
+	return notification.CycleDay(t)
+}
 
 type PostgresNotificationRepository struct {
-	db *sql.DB
+	db             *sql.DB
+	retry          RetryPolicy
+	strictIdentity bool // Status updates must match the stored teacher, cycle and report key
+	log            *logrus.Entry
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func NewPostgresNotificationRepository(db *sql.DB, baseLogger *logrus.Entry) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{db: db, log: baseLogger}
+}
+
+// statusLogger carries the identifying fields of a report status, so DB errors can be matched with service logs.
+func (r *PostgresNotificationRepository) statusLogger(operation string, rs *notification.ReportStatus) *logrus.Entry {
+	return r.log.WithFields(logrus.Fields{
+		"operation":        operation,
+		"report_status_id": rs.ID,
+		"teacher_id":       rs.TeacherID,
+		"cycle_id":         rs.CycleID,
+		"report_key":       rs.ReportKey,
+	})
+}
+
+// WithRetry enables retrying of the queries used during cycle initiation on transient connection errors.
+func (r *PostgresNotificationRepository) WithRetry(policy RetryPolicy) *PostgresNotificationRepository {
+	r.retry = policy
+	return r
+}
+
+// WithStrictIdentity makes status updates also match the in-memory teacher, cycle and report key against the
+// stored row and fail with ErrStatusIdentityMismatch when they differ. Updates go by ID alone otherwise, so
+// this catches service code that mixed up statuses; it costs nothing unless a mismatch happens.
+func (r *PostgresNotificationRepository) WithStrictIdentity(strict bool) *PostgresNotificationRepository {
+	r.strictIdentity = strict
+	return r
 }
 
-func NewPostgresNotificationRepository(db *sql.DB) *PostgresNotificationRepository {
-	return &PostgresNotificationRepository{db: db}
+// reportStatusUpdate returns the statement and arguments used by UpdateReportStatus and BulkUpdateReportStatuses.
+func (r *PostgresNotificationRepository) reportStatusUpdate(rs *notification.ReportStatus) (string, []any) {
+	args := []any{rs.Status, rs.LastNotifiedAt, rs.ResponseAttempts, rs.RemindAt, rs.ID}
+	identityCheck := ""
+	if r.strictIdentity {
+		identityCheck = " AND teacher_id = $6 AND cycle_id = $7 AND report_key = $8"
+		args = append(args, rs.TeacherID, rs.CycleID, rs.ReportKey)
+	}
+	query := `UPDATE teacher_report_statuses
+               SET status = $1, last_notified_at = $2, response_attempts = $3, remind_at = $4
+               WHERE id = $5` + identityCheck + `
+               RETURNING updated_at` // updated_at is owned by the trigger; RETURNING reports the value it wrote
+	return query, args
+}
+
+// noUpdatedRowError explains a status update that matched no row. In strict mode the row may exist
+// with a different identity, which is reported as ErrStatusIdentityMismatch.
+func (r *PostgresNotificationRepository) noUpdatedRowError(ctx context.Context, q queryRower, rs *notification.ReportStatus) error {
+	if !r.strictIdentity {
+		return ErrReportStatusNotFound
+	}
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM teacher_report_statuses WHERE id = $1)`, rs.ID).Scan(&exists); err != nil {
+		return fmt.Errorf("error checking teacher report status existence: %w", err)
+	}
+	if !exists {
+		return ErrReportStatusNotFound
+	}
+	r.statusLogger("UpdateReportStatus", rs).Error("Report status update rejected: identity differs from the stored row")
+	return ErrStatusIdentityMismatch
 }
 
 // --- NotificationCycle Methods ---
 
 func (r *PostgresNotificationRepository) CreateCycle(ctx context.Context, cycle *notification.Cycle) error {
-	query := `INSERT INTO notification_cycles (cycle_date, cycle_type)
-               VALUES ($1, $2)
-               RETURNING id, created_at`
-	// Ensure CycleDate is just the date part if necessary, though DATE type handles it.
-	err := r.db.QueryRowContext(ctx, query, cycle.CycleDate, cycle.Type).Scan(&cycle.ID, &cycle.CreatedAt)
+	query := `INSERT INTO notification_cycles (cycle_date, cycle_type, round, source)
+               VALUES ($1, $2, $3, $4)
+               ON CONFLICT (cycle_date, cycle_type, round, source) WHERE source = 'SCHEDULED' DO NOTHING
+               RETURNING id, status, created_at`
+	if cycle.Source == "" {
+		cycle.Source = notification.CycleSourceScheduled
+	}
+	if cycle.Round == 0 {
+		cycle.Round = notification.FirstRound
+	}
+	// Store the same calendar date GetCycleByDateAndType looks up, whatever time component the caller passed.
+	cycle.CycleDate = cycleDateOnly(cycle.CycleDate)
+	// Not retried: a connection lost after the commit would insert the cycle twice.
+	err := r.db.QueryRowContext(ctx, query, cycle.CycleDate, cycle.Type, cycle.Round, cycle.Source).Scan(&cycle.ID, &cycle.Status, &cycle.CreatedAt)
+	if err == sql.ErrNoRows {
+		// Another initiation created the cycle first; hand the caller that one.
+		existingQuery := `SELECT ` + cycleColumns + ` FROM notification_cycles
+               WHERE cycle_date = $1 AND cycle_type = $2 AND round = $3 AND source = $4`
+		existing, errLookup := scanCycle(r.db.QueryRowContext(ctx, existingQuery, cycle.CycleDate, cycle.Type, cycle.Round, cycle.Source))
+		if errLookup != nil {
+			return fmt.Errorf("error getting the existing notification cycle: %w", errLookup)
+		}
+		*cycle = *existing
+		return ErrDuplicateCycle
+	}
 	if err != nil {
-		// Consider specific pq error for unique constraint if any added later
 		return fmt.Errorf("error creating notification cycle: %w", err)
 	}
 	return nil
 }
 
 func (r *PostgresNotificationRepository) GetCycleByID(ctx context.Context, id int32) (*notification.Cycle, error) {
-	query := `SELECT id, cycle_date, cycle_type, created_at FROM notification_cycles WHERE id = $1`
-	cycle := notification.Cycle{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&cycle.ID, &cycle.CycleDate, &cycle.Type, &cycle.CreatedAt)
+	query := `SELECT ` + cycleColumns + ` FROM notification_cycles WHERE id = $1`
+	cycle, err := scanCycle(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrCycleNotFound
 		}
 		return nil, fmt.Errorf("error getting notification cycle by ID: %w", err)
 	}
-	return &cycle, nil
+	return cycle, nil
 }
 
-func (r *PostgresNotificationRepository) GetCycleByDateAndType(ctx context.Context, cycleDate time.Time, cycleType notification.CycleType) (*notification.Cycle, error) {
-	query := `SELECT id, cycle_date, cycle_type, created_at FROM notification_cycles WHERE cycle_date = $1 AND cycle_type = $2 ORDER BY created_at DESC LIMIT 1`
-	cycle := notification.Cycle{}
+func (r *PostgresNotificationRepository) GetCycleByDateAndType(ctx context.Context, cycleDate time.Time, cycleType notification.CycleType, round int) (*notification.Cycle, error) {
+	query := `SELECT ` + cycleColumns + ` FROM notification_cycles WHERE cycle_date = $1 AND cycle_type = $2 AND round = $3 AND source = $4 ORDER BY created_at DESC LIMIT 1`
 	// Normalize cycleDate to just date part if it contains time
-	dateOnly := time.Date(cycleDate.Year(), cycleDate.Month(), cycleDate.Day(), 0, 0, 0, 0, cycleDate.Location())
-	err := r.db.QueryRowContext(ctx, query, dateOnly, cycleType).Scan(&cycle.ID, &cycle.CycleDate, &cycle.Type, &cycle.CreatedAt)
+	dateOnly := cycleDateOnly(cycleDate)
+	var cycle *notification.Cycle
+	err := r.retry.do(ctx, func() error {
+		var err error
+		cycle, err = scanCycle(r.db.QueryRowContext(ctx, query, dateOnly, cycleType, round, notification.CycleSourceScheduled))
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrCycleNotFound
 		}
 		return nil, fmt.Errorf("error getting notification cycle by date and type: %w", err)
 	}
-	return &cycle, nil
+	return cycle, nil
+}
+
+func (r *PostgresNotificationRepository) GetLatestCycle(ctx context.Context) (*notification.Cycle, error) {
+	query := `SELECT ` + cycleColumns + ` FROM notification_cycles WHERE source = $1 ORDER BY cycle_date DESC, round DESC, created_at DESC LIMIT 1`
+	cycle, err := scanCycle(r.db.QueryRowContext(ctx, query, notification.CycleSourceScheduled))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCycleNotFound
+		}
+		return nil, fmt.Errorf("error getting latest notification cycle: %w", err)
+	}
+	return cycle, nil
+}
+
+func (r *PostgresNotificationRepository) UpdateCycleStatus(ctx context.Context, cycleID int32, status notification.CycleStatus) error {
+	query := `UPDATE notification_cycles SET status = $1 WHERE id = $2`
+	res, err := r.db.ExecContext(ctx, query, status, cycleID)
+	if err != nil {
+		return fmt.Errorf("error updating notification cycle status: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking updated notification cycle: %w", err)
+	}
+	if affected == 0 {
+		return ErrCycleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) ListCyclesByStatus(ctx context.Context, status notification.CycleStatus) ([]*notification.Cycle, error) {
+	query := `SELECT ` + cycleColumns + ` FROM notification_cycles WHERE status = $1 ORDER BY cycle_date ASC, id ASC`
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("error listing notification cycles by status: %w", err)
+	}
+	defer rows.Close()
+
+	var cycles []*notification.Cycle
+	for rows.Next() {
+		cycle, err := scanCycle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning notification cycle: %w", err)
+		}
+		cycles = append(cycles, cycle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification cycles: %w", err)
+	}
+	return cycles, nil
+}
+
+// CountOutstandingReportStatuses counts unconfirmed statuses of the cycle that still need work:
+// those of active teachers who are not excluded from the cycle.
+func (r *PostgresNotificationRepository) CountOutstandingReportStatuses(ctx context.Context, cycleID int32) (int, error) {
+	query := `SELECT COUNT(*)
+               FROM teacher_report_statuses trs
+               JOIN teachers t ON t.id = trs.teacher_id
+               WHERE trs.cycle_id = $1
+                 AND trs.status <> $2
+                 AND t.is_active
+                 AND NOT EXISTS (
+                     SELECT 1 FROM cycle_teacher_exclusions e
+                     WHERE e.cycle_id = trs.cycle_id AND e.teacher_id = trs.teacher_id
+                 )`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, cycleID, notification.StatusAnsweredYes).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting outstanding report statuses: %w", err)
+	}
+	return count, nil
+}
+
+// --- Cycle Exclusion Methods ---
+
+func (r *PostgresNotificationRepository) AddCycleExclusion(ctx context.Context, cycleID int32, teacherID int64) error {
+	query := `INSERT INTO cycle_teacher_exclusions (cycle_id, teacher_id)
+               VALUES ($1, $2)
+               ON CONFLICT (cycle_id, teacher_id) DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, cycleID, teacherID); err != nil {
+		return fmt.Errorf("error adding cycle exclusion: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) RemoveCycleExclusion(ctx context.Context, cycleID int32, teacherID int64) error {
+	query := `DELETE FROM cycle_teacher_exclusions WHERE cycle_id = $1 AND teacher_id = $2`
+	res, err := r.db.ExecContext(ctx, query, cycleID, teacherID)
+	if err != nil {
+		return fmt.Errorf("error removing cycle exclusion: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking removed cycle exclusion: %w", err)
+	}
+	if affected == 0 {
+		return ErrCycleExclusionNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) IsTeacherExcludedFromCycle(ctx context.Context, cycleID int32, teacherID int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM cycle_teacher_exclusions WHERE cycle_id = $1 AND teacher_id = $2)`
+	var excluded bool
+	if err := r.db.QueryRowContext(ctx, query, cycleID, teacherID).Scan(&excluded); err != nil {
+		return false, fmt.Errorf("error checking cycle exclusion: %w", err)
+	}
+	return excluded, nil
+}
+
+func (r *PostgresNotificationRepository) ListExcludedTeacherIDs(ctx context.Context, cycleID int32) ([]int64, error) {
+	query := `SELECT teacher_id FROM cycle_teacher_exclusions WHERE cycle_id = $1 ORDER BY teacher_id`
+	var rows *sql.Rows
+	err := r.retry.do(ctx, func() error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query, cycleID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing cycle exclusions: %w", err)
+	}
+	defer rows.Close()
+
+	var teacherIDs []int64
+	for rows.Next() {
+		var teacherID int64
+		if err := rows.Scan(&teacherID); err != nil {
+			return nil, fmt.Errorf("error scanning cycle exclusion: %w", err)
+		}
+		teacherIDs = append(teacherIDs, teacherID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cycle exclusions: %w", err)
+	}
+	return teacherIDs, nil
+}
+
+// --- Completion Marker Methods ---
+
+func (r *PostgresNotificationRepository) MarkCompletionNotified(ctx context.Context, cycleID int32, teacherID int64) error {
+	query := `INSERT INTO cycle_teacher_completions (cycle_id, teacher_id)
+               VALUES ($1, $2)
+               ON CONFLICT (cycle_id, teacher_id) DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, cycleID, teacherID); err != nil {
+		return fmt.Errorf("error marking completion notified: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) RecordSendFailure(ctx context.Context, reportStatusID int64, sendErr string) error {
+	query := `UPDATE teacher_report_statuses
+               SET send_failure_count = send_failure_count + 1, last_send_error = $1, last_send_failed_at = NOW()
+               WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, sendErr, reportStatusID)
+	if err != nil {
+		return fmt.Errorf("error recording send failure: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrReportStatusNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) RecordRemindersSent(ctx context.Context, reportStatusIDs []int64) error {
+	if len(reportStatusIDs) == 0 {
+		return nil
+	}
+	query := `UPDATE teacher_report_statuses SET reminders_sent = reminders_sent + 1 WHERE id = ANY($1)`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(reportStatusIDs)); err != nil {
+		return fmt.Errorf("error counting sent reminders: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) MarkProxyConfirmed(ctx context.Context, reportStatusIDs []int64, proxyTelegramID int64) error {
+	if len(reportStatusIDs) == 0 {
+		return nil
+	}
+	query := `UPDATE teacher_report_statuses SET confirmed_by_telegram_id = $1 WHERE id = ANY($2)`
+	if _, err := r.db.ExecContext(ctx, query, proxyTelegramID, pq.Array(reportStatusIDs)); err != nil {
+		return fmt.Errorf("error marking report statuses as proxy-confirmed: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) ListRecentSendFailures(ctx context.Context, since time.Time) ([]*notification.SendFailure, error) {
+	query := `SELECT id, teacher_id, cycle_id, report_key, send_failure_count, COALESCE(last_send_error, ''), last_send_failed_at
+               FROM teacher_report_statuses
+               WHERE last_send_failed_at >= $1
+               ORDER BY last_send_failed_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("error querying recent send failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := make([]*notification.SendFailure, 0)
+	for rows.Next() {
+		f := &notification.SendFailure{}
+		if err := rows.Scan(&f.ReportStatusID, &f.TeacherID, &f.CycleID, &f.ReportKey, &f.FailureCount, &f.LastError, &f.LastFailedAt); err != nil {
+			return nil, fmt.Errorf("error scanning send failure row: %w", err)
+		}
+		failures = append(failures, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating send failure rows: %w", err)
+	}
+	return failures, nil
+}
+
+func (r *PostgresNotificationRepository) IsCompletionNotified(ctx context.Context, cycleID int32, teacherID int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM cycle_teacher_completions WHERE cycle_id = $1 AND teacher_id = $2)`
+	var notified bool
+	if err := r.db.QueryRowContext(ctx, query, cycleID, teacherID).Scan(&notified); err != nil {
+		return false, fmt.Errorf("error checking completion marker: %w", err)
+	}
+	return notified, nil
+}
+
+// --- Notification Run Methods ---
+
+func (r *PostgresNotificationRepository) RecordRun(ctx context.Context, summary *notification.RunSummary) error {
+	query := `INSERT INTO notification_runs (cycle_id, teachers_targeted, statuses_created, messages_sent, messages_failed, started_at, finished_at)
+               VALUES ($1, $2, $3, $4, $5, $6, $7)
+               RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, summary.CycleID, summary.TeachersTargeted, summary.StatusesCreated,
+		summary.MessagesSent, summary.MessagesFailed, summary.StartedAt, summary.FinishedAt).Scan(&summary.ID)
+	if err != nil {
+		return fmt.Errorf("error recording notification run: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) ListRuns(ctx context.Context, cycleID int32, limit int) ([]*notification.RunSummary, error) {
+	query := `SELECT id, cycle_id, teachers_targeted, statuses_created, messages_sent, messages_failed, started_at, finished_at
+               FROM notification_runs
+               WHERE ($1 = 0 OR cycle_id = $1)
+               ORDER BY started_at DESC, id DESC
+               LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, cycleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing notification runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*notification.RunSummary
+	for rows.Next() {
+		run := &notification.RunSummary{}
+		if err := rows.Scan(&run.ID, &run.CycleID, &run.TeachersTargeted, &run.StatusesCreated,
+			&run.MessagesSent, &run.MessagesFailed, &run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, fmt.Errorf("error scanning notification run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *PostgresNotificationRepository) AcquireRunLock(ctx context.Context, runKey string, staleAfter time.Duration) (string, error) {
+	query := `INSERT INTO notification_run_locks (run_key, owner) VALUES ($1, $3)
+               ON CONFLICT (run_key) DO UPDATE SET acquired_at = NOW(), owner = EXCLUDED.owner
+               WHERE notification_run_locks.acquired_at < NOW() - make_interval(secs => $2)
+               RETURNING owner`
+	var owner string
+	err := r.db.QueryRowContext(ctx, query, runKey, staleAfter.Seconds(), rand.Text()).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return "", nil // Held by someone else and not stale yet
+	}
+	if err != nil {
+		return "", fmt.Errorf("error acquiring run lock: %w", err)
+	}
+	return owner, nil
+}
+
+func (r *PostgresNotificationRepository) ReleaseRunLock(ctx context.Context, runKey string, owner string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM notification_run_locks WHERE run_key = $1 AND owner = $2`, runKey, owner); err != nil {
+		return fmt.Errorf("error releasing run lock: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) ComputeCycleMetrics(ctx context.Context, cycleID int32) (*notification.CycleMetrics, error) {
+	query := `SELECT COUNT(DISTINCT teacher_id) FILTER (WHERE last_notified_at IS NOT NULL),
+			   COUNT(*),
+			   COUNT(*) FILTER (WHERE status = $2),
+			   COALESCE(SUM(response_attempts + reminders_sent), 0),
+			   COALESCE(AVG(EXTRACT(EPOCH FROM (confirmed_at - COALESCE(last_notified_at, created_at))))
+			            FILTER (WHERE status = $2 AND confirmed_at IS NOT NULL), 0)
+			   FROM teacher_report_statuses
+			   WHERE cycle_id = $1`
+	m := notification.CycleMetrics{CycleID: cycleID}
+	var avgSeconds float64
+	err := r.db.QueryRowContext(ctx, query, cycleID, notification.StatusAnsweredYes).Scan(
+		&m.TeachersNotified, &m.TotalStatuses, &m.Confirmations, &m.RemindersSent, &avgSeconds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error computing cycle metrics: %w", err)
+	}
+	m.AverageLatency = time.Duration(avgSeconds * float64(time.Second))
+	return &m, nil
+}
+
+func (r *PostgresNotificationRepository) RecordCycleMetrics(ctx context.Context, m notification.CycleMetrics) error {
+	query := `INSERT INTO cycle_metrics (cycle_id, teachers_notified, total_statuses, confirmations, reminders_sent, avg_confirmation_seconds, completion_seconds)
+               VALUES ($1, $2, $3, $4, $5, $6, $7)
+               ON CONFLICT (cycle_id) DO UPDATE SET
+                   teachers_notified = EXCLUDED.teachers_notified,
+                   total_statuses = EXCLUDED.total_statuses,
+                   confirmations = EXCLUDED.confirmations,
+                   reminders_sent = EXCLUDED.reminders_sent,
+                   avg_confirmation_seconds = EXCLUDED.avg_confirmation_seconds,
+                   completion_seconds = EXCLUDED.completion_seconds,
+                   recorded_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, m.CycleID, m.TeachersNotified, m.TotalStatuses, m.Confirmations, m.RemindersSent,
+		m.AverageLatency.Seconds(), int64(m.CompletionTime.Seconds()))
+	if err != nil {
+		return fmt.Errorf("error recording cycle metrics: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) ListCycleMetrics(ctx context.Context, limit int) ([]*notification.CycleMetrics, error) {
+	query := `SELECT cm.cycle_id, nc.cycle_date, nc.cycle_type, cm.teachers_notified, cm.total_statuses, cm.confirmations,
+			   cm.reminders_sent, cm.avg_confirmation_seconds, cm.completion_seconds, cm.recorded_at
+			   FROM cycle_metrics cm
+			   JOIN notification_cycles nc ON nc.id = cm.cycle_id
+			   ORDER BY nc.cycle_date DESC, cm.cycle_id DESC
+			   LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing cycle metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []*notification.CycleMetrics
+	for rows.Next() {
+		m := &notification.CycleMetrics{}
+		var avgSeconds float64
+		var completionSeconds int64
+		if err := rows.Scan(&m.CycleID, &m.CycleDate, &m.CycleType, &m.TeachersNotified, &m.TotalStatuses, &m.Confirmations,
+			&m.RemindersSent, &avgSeconds, &completionSeconds, &m.RecordedAt); err != nil {
+			return nil, fmt.Errorf("error scanning cycle metrics: %w", err)
+		}
+		m.AverageLatency = time.Duration(avgSeconds * float64(time.Second))
+		m.CompletionTime = time.Duration(completionSeconds) * time.Second
+		metrics = append(metrics, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cycle metrics: %w", err)
+	}
+	return metrics, nil
 }
 
 // --- TeacherReportStatus Methods ---
@@ -79,6 +552,7 @@ func (r *PostgresNotificationRepository) CreateReportStatus(ctx context.Context,
 		if strings.Contains(err.Error(), "teacher_cycle_report_unique") { // Check for unique constraint violation
 			return ErrDuplicateReportStatus
 		}
+		r.statusLogger("CreateReportStatus", rs).WithError(err).Error("Failed to create teacher report status")
 		return fmt.Errorf("error creating teacher report status: %w", err)
 	}
 	return nil
@@ -89,7 +563,12 @@ func (r *PostgresNotificationRepository) BulkCreateReportStatuses(ctx context.Co
 		return nil
 	}
 
-	txn, err := r.db.BeginTx(ctx, nil)
+	var txn *sql.Tx
+	err := r.retry.do(ctx, func() error {
+		var err error
+		txn, err = r.db.BeginTx(ctx, nil)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction for bulk create: %w", err)
 	}
@@ -105,6 +584,7 @@ func (r *PostgresNotificationRepository) BulkCreateReportStatuses(ctx context.Co
 	for _, rs := range statuses {
 		_, err := stmt.ExecContext(ctx, rs.TeacherID, rs.CycleID, rs.ReportKey, rs.Status, rs.LastNotifiedAt, rs.ResponseAttempts, rs.RemindAt)
 		if err != nil {
+			r.statusLogger("BulkCreateReportStatuses", rs).WithError(err).Error("Failed to insert teacher report status in bulk create")
 			if strings.Contains(err.Error(), "teacher_cycle_report_unique") {
 				// Potentially log this or decide on overall failure/partial success
 				return fmt.Errorf("error in bulk create (status for T:%d, C:%d, K:%s): %w, Detail: %w", rs.TeacherID, rs.CycleID, rs.ReportKey, ErrDuplicateReportStatus, err)
@@ -117,33 +597,112 @@ func (r *PostgresNotificationRepository) BulkCreateReportStatuses(ctx context.Co
 }
 
 func (r *PostgresNotificationRepository) UpdateReportStatus(ctx context.Context, rs *notification.ReportStatus) error {
-	query := `UPDATE teacher_report_statuses
-               SET status = $1, last_notified_at = $2, response_attempts = $3, updated_at = NOW(), remind_at = $4
-               WHERE id = $5
-               RETURNING updated_at` // updated_at also set by trigger
-	err := r.db.QueryRowContext(ctx, query, rs.Status, rs.LastNotifiedAt, rs.ResponseAttempts, rs.RemindAt, rs.ID).Scan(&rs.UpdatedAt)
+	query, args := r.reportStatusUpdate(rs)
+	err := r.retry.do(ctx, func() error {
+		return r.db.QueryRowContext(ctx, query, args...).Scan(&rs.UpdatedAt)
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return ErrReportStatusNotFound
+			return r.noUpdatedRowError(ctx, r.db, rs)
 		}
+		r.statusLogger("UpdateReportStatus", rs).WithError(err).Error("Failed to update teacher report status")
 		return fmt.Errorf("error updating teacher report status: %w", err)
 	}
 	return nil
 }
 
+func (r *PostgresNotificationRepository) ResetReportKeyStatuses(ctx context.Context, cycleID int32, reportKey notification.ReportKey) ([]*notification.ReportStatus, error) {
+	query := `UPDATE teacher_report_statuses
+               SET status = $1, last_notified_at = NULL, response_attempts = 0, reminders_sent = 0, remind_at = NULL
+               WHERE cycle_id = $2 AND report_key = $3 AND status = $4
+               RETURNING id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at`
+	rows, err := r.db.QueryContext(ctx, query, notification.StatusPendingQuestion, cycleID, reportKey, notification.StatusAnsweredYes)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"operation":  "ResetReportKeyStatuses",
+			"cycle_id":   cycleID,
+			"report_key": reportKey,
+		}).WithError(err).Error("Failed to reset report statuses")
+		return nil, fmt.Errorf("error resetting report statuses: %w", err)
+	}
+	defer rows.Close()
+	return scanReportStatuses(rows)
+}
+
+func (r *PostgresNotificationRepository) BulkUpdateReportStatuses(ctx context.Context, statuses []*notification.ReportStatus) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for bulk update: %w", err)
+	}
+	defer txn.Rollback() // Rollback if not committed
+
+	// Every row uses the same statement text, so it is prepared once.
+	updateQuery, _ := r.reportStatusUpdate(statuses[0])
+	stmt, err := txn.PrepareContext(ctx, updateQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement for bulk update: %w", err)
+	}
+	defer stmt.Close()
+
+	failures := make(map[int64]error)
+	for _, rs := range statuses {
+		// A savepoint per row keeps one failing row from aborting the whole transaction.
+		if _, err := txn.ExecContext(ctx, "SAVEPOINT bulk_update_row"); err != nil {
+			return fmt.Errorf("failed to create savepoint for bulk update: %w", err)
+		}
+		_, args := r.reportStatusUpdate(rs)
+		err := stmt.QueryRowContext(ctx, args...).Scan(&rs.UpdatedAt)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				failures[rs.ID] = r.noUpdatedRowError(ctx, txn, rs)
+			} else {
+				r.statusLogger("BulkUpdateReportStatuses", rs).WithError(err).Error("Failed to update teacher report status in bulk update")
+				failures[rs.ID] = fmt.Errorf("error updating teacher report status: %w", err)
+			}
+			if _, err := txn.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_update_row"); err != nil {
+				return fmt.Errorf("failed to roll back to savepoint for bulk update: %w", err)
+			}
+			continue
+		}
+		if _, err := txn.ExecContext(ctx, "RELEASE SAVEPOINT bulk_update_row"); err != nil {
+			return fmt.Errorf("failed to release savepoint for bulk update: %w", err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit bulk update: %w", err)
+	}
+	if len(failures) > 0 {
+		return &BulkUpdateError{Failures: failures}
+	}
+	return nil
+}
+
 func (r *PostgresNotificationRepository) GetReportStatus(ctx context.Context, teacherID int64, cycleID int32, reportKey notification.ReportKey) (*notification.ReportStatus, error) {
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
                FROM teacher_report_statuses
                WHERE teacher_id = $1 AND cycle_id = $2 AND report_key = $3`
 	rs := notification.ReportStatus{}
-	err := r.db.QueryRowContext(ctx, query, teacherID, cycleID, reportKey).Scan(
-		&rs.ID, &rs.TeacherID, &rs.CycleID, &rs.ReportKey, &rs.Status,
-		&rs.LastNotifiedAt, &rs.ResponseAttempts, &rs.CreatedAt, &rs.UpdatedAt, &rs.RemindAt,
-	)
+	err := r.retry.do(ctx, func() error {
+		return r.db.QueryRowContext(ctx, query, teacherID, cycleID, reportKey).Scan(
+			&rs.ID, &rs.TeacherID, &rs.CycleID, &rs.ReportKey, &rs.Status,
+			&rs.LastNotifiedAt, &rs.ResponseAttempts, &rs.CreatedAt, &rs.UpdatedAt, &rs.RemindAt,
+		)
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrReportStatusNotFound
 		}
+		r.log.WithFields(logrus.Fields{
+			"operation":  "GetReportStatus",
+			"teacher_id": teacherID,
+			"cycle_id":   cycleID,
+			"report_key": reportKey,
+		}).WithError(err).Error("Failed to get teacher report status")
 		return nil, fmt.Errorf("error getting teacher report status: %w", err)
 	}
 	return &rs, nil
@@ -161,11 +720,51 @@ func (r *PostgresNotificationRepository) GetReportStatusByID(ctx context.Context
 		if err == sql.ErrNoRows {
 			return nil, ErrReportStatusNotFound
 		}
+		r.log.WithFields(logrus.Fields{
+			"operation":        "GetReportStatusByID",
+			"report_status_id": id,
+		}).WithError(err).Error("Failed to get teacher report status by ID")
 		return nil, fmt.Errorf("error getting teacher report status by ID: %w", err)
 	}
 	return &rs, nil
 }
 
+func (r *PostgresNotificationRepository) GetLatestPendingQuestion(ctx context.Context, teacherID int64) (*notification.ReportStatus, error) {
+	query := `SELECT trs.id, trs.teacher_id, trs.cycle_id, trs.report_key, trs.status, trs.last_notified_at, trs.response_attempts, trs.created_at, trs.updated_at, trs.remind_at
+			   FROM teacher_report_statuses trs
+			   JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE trs.teacher_id = $1 AND trs.status = $2 AND trs.last_notified_at IS NOT NULL AND nc.status = $3
+			   ORDER BY trs.last_notified_at DESC, trs.id DESC
+			   LIMIT 1`
+	rs := notification.ReportStatus{}
+	err := r.db.QueryRowContext(ctx, query, teacherID, notification.StatusPendingQuestion, notification.CycleStatusOpen).Scan(
+		&rs.ID, &rs.TeacherID, &rs.CycleID, &rs.ReportKey, &rs.Status,
+		&rs.LastNotifiedAt, &rs.ResponseAttempts, &rs.CreatedAt, &rs.UpdatedAt, &rs.RemindAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrReportStatusNotFound
+		}
+		r.log.WithFields(logrus.Fields{
+			"operation":  "GetLatestPendingQuestion",
+			"teacher_id": teacherID,
+		}).WithError(err).Error("Failed to get latest pending question")
+		return nil, fmt.Errorf("error getting latest pending question: %w", err)
+	}
+	return &rs, nil
+}
+
+// reportKeyOrder is the question order as a query parameter, for ORDER BY array_position(...).
+// Unknown keys get NULL positions and sort last.
+func reportKeyOrder() interface{} {
+	keys := notification.AllReportKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = string(key)
+	}
+	return pq.Array(names)
+}
+
 // Helper to scan multiple rows
 func scanReportStatuses(rows *sql.Rows) ([]*notification.ReportStatus, error) {
 	statuses := make([]*notification.ReportStatus, 0)
@@ -188,8 +787,8 @@ func scanReportStatuses(rows *sql.Rows) ([]*notification.ReportStatus, error) {
 func (r *PostgresNotificationRepository) ListReportStatusesByCycleAndTeacher(ctx context.Context, cycleID int32, teacherID int64) ([]*notification.ReportStatus, error) {
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
                 FROM teacher_report_statuses
-                WHERE cycle_id = $1 AND teacher_id = $2 ORDER BY report_key` // Order for consistent processing
-	rows, err := r.db.QueryContext(ctx, query, cycleID, teacherID)
+                WHERE cycle_id = $1 AND teacher_id = $2 ORDER BY array_position($3::varchar[], report_key::varchar), report_key` // Question order
+	rows, err := r.db.QueryContext(ctx, query, cycleID, teacherID, reportKeyOrder())
 	if err != nil {
 		return nil, fmt.Errorf("error querying report statuses by cycle and teacher: %w", err)
 	}
@@ -197,11 +796,78 @@ func (r *PostgresNotificationRepository) ListReportStatusesByCycleAndTeacher(ctx
 	return scanReportStatuses(rows)
 }
 
+// GetTeacherWithStatuses returns ErrTeacherNotFound when the teacher does not exist.
+func (r *PostgresNotificationRepository) GetTeacherWithStatuses(ctx context.Context, teacherID int64, cycleID int32) (*teacher.Teacher, []*notification.ReportStatus, error) {
+	// The LEFT JOIN keeps the teacher row when there are no statuses; its status columns are then NULL.
+	query := `SELECT t.` + strings.ReplaceAll(teacherColumns, ", ", ", t.") + `,
+                     trs.id, trs.teacher_id, trs.cycle_id, trs.report_key, trs.status, trs.last_notified_at, trs.response_attempts, trs.created_at, trs.updated_at, trs.remind_at
+                FROM teachers t
+                LEFT JOIN teacher_report_statuses trs ON trs.teacher_id = t.id AND trs.cycle_id = $2
+                WHERE t.id = $1
+                ORDER BY array_position($3::varchar[], trs.report_key::varchar), trs.report_key` // Question order
+	logCtx := r.log.WithFields(logrus.Fields{
+		"operation":  "GetTeacherWithStatuses",
+		"teacher_id": teacherID,
+		"cycle_id":   cycleID,
+	})
+
+	rows, err := r.db.QueryContext(ctx, query, teacherID, cycleID, reportKeyOrder())
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to query teacher with report statuses")
+		return nil, nil, fmt.Errorf("error querying teacher with report statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var t *teacher.Teacher
+	statuses := make([]*notification.ReportStatus, 0)
+	for rows.Next() {
+		rowTeacher := &teacher.Teacher{}
+		var (
+			statusID, statusTeacherID, responseAttempts sql.NullInt64
+			statusCycleID                               sql.NullInt32
+			reportKey, status                           sql.NullString
+			createdAt, updatedAt                        sql.NullTime
+			rs                                          notification.ReportStatus
+		)
+		if err := rows.Scan(
+			&rowTeacher.ID, &rowTeacher.TelegramID, &rowTeacher.FirstName, &rowTeacher.LastName, &rowTeacher.IsActive,
+			&rowTeacher.NotifyManagerOnComplete, &rowTeacher.WorkDays, &rowTeacher.HasStartedBot, &rowTeacher.ReminderDelayOverride, &rowTeacher.DisplayName,
+			&rowTeacher.CreatedAt, &rowTeacher.UpdatedAt,
+			&statusID, &statusTeacherID, &statusCycleID, &reportKey, &status,
+			&rs.LastNotifiedAt, &responseAttempts, &createdAt, &updatedAt, &rs.RemindAt,
+		); err != nil {
+			return nil, nil, fmt.Errorf("error scanning teacher with report status row: %w", err)
+		}
+		if t == nil {
+			t = rowTeacher
+		}
+		if !statusID.Valid {
+			continue // Teacher without statuses in the cycle
+		}
+		rs.ID = statusID.Int64
+		rs.TeacherID = statusTeacherID.Int64
+		rs.CycleID = statusCycleID.Int32
+		rs.ReportKey = notification.ReportKey(reportKey.String)
+		rs.Status = notification.InteractionStatus(status.String)
+		rs.ResponseAttempts = int(responseAttempts.Int64)
+		rs.CreatedAt = createdAt.Time
+		rs.UpdatedAt = updatedAt.Time
+		statuses = append(statuses, &rs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating teacher with report status rows: %w", err)
+	}
+	if t == nil {
+		return nil, nil, ErrTeacherNotFound
+	}
+	return t, statuses, nil
+}
+
 func (r *PostgresNotificationRepository) ListReportStatusesByCycle(ctx context.Context, cycleID int32) ([]*notification.ReportStatus, error) {
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
                 FROM teacher_report_statuses
-                WHERE cycle_id = $1 ORDER BY teacher_id, report_key`
-	rows, err := r.db.QueryContext(ctx, query, cycleID)
+                WHERE cycle_id = $1 ORDER BY teacher_id, array_position($2::varchar[], report_key::varchar), report_key`
+	rows, err := r.db.QueryContext(ctx, query, cycleID, reportKeyOrder())
 	if err != nil {
 		return nil, fmt.Errorf("error querying report statuses by cycle: %w", err)
 	}
@@ -212,8 +878,8 @@ func (r *PostgresNotificationRepository) ListReportStatusesByCycle(ctx context.C
 func (r *PostgresNotificationRepository) ListReportStatusesByStatusAndCycle(ctx context.Context, cycleID int32, status notification.InteractionStatus) ([]*notification.ReportStatus, error) {
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
                 FROM teacher_report_statuses
-                WHERE cycle_id = $1 AND status = $2 ORDER BY teacher_id, report_key`
-	rows, err := r.db.QueryContext(ctx, query, cycleID, status)
+                WHERE cycle_id = $1 AND status = $2 ORDER BY teacher_id, array_position($3::varchar[], report_key::varchar), report_key`
+	rows, err := r.db.QueryContext(ctx, query, cycleID, status, reportKeyOrder())
 	if err != nil {
 		return nil, fmt.Errorf("error querying report statuses by status and cycle: %w", err)
 	}
@@ -221,6 +887,19 @@ func (r *PostgresNotificationRepository) ListReportStatusesByStatusAndCycle(ctx
 	return scanReportStatuses(rows)
 }
 
+func (r *PostgresNotificationRepository) ListReportStatusesUpdatedSince(ctx context.Context, since time.Time) ([]*notification.ReportStatus, error) {
+	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
+                FROM teacher_report_statuses
+                WHERE updated_at >= $1
+                ORDER BY updated_at ASC, id ASC` // id breaks ties so pollers can page deterministically
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("error querying report statuses updated since %s: %w", since.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+	return scanReportStatuses(rows)
+}
+
 func (r *PostgresNotificationRepository) ListReportStatusesForReminders(ctx context.Context, cycleID int32, status notification.InteractionStatus, notifiedBefore time.Time) ([]*notification.ReportStatus, error) {
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
                 FROM teacher_report_statuses
@@ -265,8 +944,9 @@ func (r *PostgresNotificationRepository) ListDueReminders(ctx context.Context, t
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
 			   FROM teacher_report_statuses
 			   WHERE status = $1 AND remind_at IS NOT NULL AND remind_at <= $2
+				 AND cycle_id IN (SELECT id FROM notification_cycles WHERE status = $3)
 			   ORDER BY remind_at ASC` // Process older ones first
-	rows, err := r.db.QueryContext(ctx, query, targetStatus, remindAtOrBefore)
+	rows, err := r.db.QueryContext(ctx, query, targetStatus, remindAtOrBefore, notification.CycleStatusOpen)
 	if err != nil {
 		return nil, fmt.Errorf("error querying for due reminders (status: %s): %w", targetStatus, err)
 	}
@@ -275,11 +955,70 @@ func (r *PostgresNotificationRepository) ListDueReminders(ctx context.Context, t
 	return scanReportStatuses(rows)
 }
 
+func (r *PostgresNotificationRepository) CountReportStatusesByStatus(ctx context.Context, cycleStatus notification.CycleStatus) (map[notification.InteractionStatus]int, error) {
+	query := `SELECT trs.status, COUNT(*)
+			   FROM teacher_report_statuses trs
+			   JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE nc.status = $1
+			   GROUP BY trs.status`
+	rows, err := r.db.QueryContext(ctx, query, cycleStatus)
+	if err != nil {
+		return nil, fmt.Errorf("error counting report statuses by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[notification.InteractionStatus]int)
+	for rows.Next() {
+		var status notification.InteractionStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("error scanning report status count: %w", err)
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report status counts: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *PostgresNotificationRepository) CountUpcomingReminders(ctx context.Context, within time.Duration) (int, error) {
+	now := time.Now()
+	query := `SELECT COUNT(*)
+			   FROM teacher_report_statuses trs
+			   JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE trs.remind_at IS NOT NULL AND trs.remind_at BETWEEN $1 AND $2
+				 AND nc.status = $3`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, now, now.Add(within), notification.CycleStatusOpen).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting upcoming reminders: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresNotificationRepository) ListUpcomingReminders(ctx context.Context, within time.Duration) ([]*notification.ReportStatus, error) {
+	now := time.Now()
+	query := `SELECT trs.id, trs.teacher_id, trs.cycle_id, trs.report_key, trs.status, trs.last_notified_at, trs.response_attempts, trs.created_at, trs.updated_at, trs.remind_at
+			   FROM teacher_report_statuses trs
+			   JOIN teachers t ON t.id = trs.teacher_id
+			   JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE trs.remind_at IS NOT NULL AND trs.remind_at BETWEEN $1 AND $2
+				 AND t.is_active = TRUE AND nc.status = $3
+			   ORDER BY trs.remind_at ASC`
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(within), notification.CycleStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("error querying for upcoming reminders: %w", err)
+	}
+	defer rows.Close()
+	return scanReportStatuses(rows)
+}
+
 func (r *PostgresNotificationRepository) ListStalledStatusesFromPreviousDay(
 	ctx context.Context,
 	statusesToConsider []notification.InteractionStatus,
 	startOfPreviousDay time.Time, // e.g., Yesterday 00:00:00
 	endOfPreviousDay time.Time, // e.g., Yesterday 23:59:59.999999
+	cycleTypes []notification.CycleType, // Empty for every cycle type
 ) ([]*notification.ReportStatus, error) {
 	if len(statusesToConsider) == 0 {
 		return []*notification.ReportStatus{}, nil
@@ -290,17 +1029,127 @@ func (r *PostgresNotificationRepository) ListStalledStatusesFromPreviousDay(
 	for i, s := range statusesToConsider {
 		statusStrings[i] = string(s)
 	}
+	cycleTypeStrings := make([]string, len(cycleTypes))
+	for i, t := range cycleTypes {
+		cycleTypeStrings[i] = string(t)
+	}
 
 	query := `SELECT id, teacher_id, cycle_id, report_key, status, last_notified_at, response_attempts, created_at, updated_at, remind_at
 			   FROM teacher_report_statuses
 			   WHERE last_notified_at >= $1 AND last_notified_at <= $2
 				 AND status = ANY($3::varchar[])
+				 AND cycle_id IN (SELECT id FROM notification_cycles
+				                  WHERE status = $4 AND (cardinality($5::varchar[]) = 0 OR cycle_type = ANY($5::varchar[])))
 			   ORDER BY last_notified_at ASC`
 
-	rows, err := r.db.QueryContext(ctx, query, startOfPreviousDay, endOfPreviousDay, pq.Array(statusStrings))
+	rows, err := r.db.QueryContext(ctx, query, startOfPreviousDay, endOfPreviousDay, pq.Array(statusStrings), notification.CycleStatusOpen, pq.Array(cycleTypeStrings))
 	if err != nil {
 		return nil, fmt.Errorf("error querying for stalled statuses from previous day: %w", err)
 	}
 	defer rows.Close()
 	return scanReportStatuses(rows)
 }
+
+func (r *PostgresNotificationRepository) FindMissingStatuses(ctx context.Context, cycleID int32, expectedKeys []notification.ReportKey) (map[int64][]notification.ReportKey, error) {
+	missing := make(map[int64][]notification.ReportKey)
+	if len(expectedKeys) == 0 {
+		return missing, nil
+	}
+	keys := make([]string, len(expectedKeys))
+	for i, k := range expectedKeys {
+		keys[i] = string(k)
+	}
+
+	query := `SELECT t.teacher_id, k.report_key
+                FROM (SELECT DISTINCT teacher_id FROM teacher_report_statuses WHERE cycle_id = $1) t
+                CROSS JOIN unnest($2::varchar[]) WITH ORDINALITY AS k(report_key, position)
+                WHERE NOT EXISTS (
+                    SELECT 1 FROM teacher_report_statuses trs
+                    WHERE trs.cycle_id = $1 AND trs.teacher_id = t.teacher_id AND trs.report_key = k.report_key
+                )
+                ORDER BY t.teacher_id, k.position`
+	rows, err := r.db.QueryContext(ctx, query, cycleID, pq.Array(keys))
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"operation": "FindMissingStatuses",
+			"cycle_id":  cycleID,
+		}).WithError(err).Error("Failed to find missing report statuses")
+		return nil, fmt.Errorf("error finding missing report statuses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var teacherID int64
+		var key notification.ReportKey
+		if err := rows.Scan(&teacherID, &key); err != nil {
+			return nil, fmt.Errorf("error scanning missing report status row: %w", err)
+		}
+		missing[teacherID] = append(missing[teacherID], key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating missing report status rows: %w", err)
+	}
+	return missing, nil
+}
+
+func (r *PostgresNotificationRepository) CountOrphanedReportStatuses(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*)
+			   FROM teacher_report_statuses trs
+			   LEFT JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE nc.id IS NULL AND trs.status <> $1`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, notification.StatusCancelled).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting orphaned report statuses: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresNotificationRepository) ListOrphanedReportStatuses(ctx context.Context) ([]*notification.ReportStatus, error) {
+	query := `SELECT trs.id, trs.teacher_id, trs.cycle_id, trs.report_key, trs.status, trs.last_notified_at, trs.response_attempts, trs.created_at, trs.updated_at, trs.remind_at
+			   FROM teacher_report_statuses trs
+			   LEFT JOIN teachers t ON t.id = trs.teacher_id
+			   LEFT JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE t.id IS NULL OR nc.id IS NULL
+			   ORDER BY trs.id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying orphaned report statuses: %w", err)
+	}
+	defer rows.Close()
+	return scanReportStatuses(rows)
+}
+
+func (r *PostgresNotificationRepository) GetResponseRateStats(ctx context.Context, from, to time.Time) (*notification.ResponseRateStats, error) {
+	query := `SELECT COUNT(*),
+			   COUNT(*) FILTER (WHERE trs.status = $3),
+			   COUNT(*) FILTER (WHERE trs.status = $3 AND trs.response_attempts = 0),
+			   COUNT(DISTINCT trs.teacher_id),
+			   COALESCE(SUM(trs.response_attempts), 0)
+			   FROM teacher_report_statuses trs
+			   JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE nc.cycle_date >= $1 AND nc.cycle_date < $2 AND nc.source = $4`
+	stats := notification.ResponseRateStats{}
+	err := r.db.QueryRowContext(ctx, query, from, to, notification.StatusAnsweredYes, notification.CycleSourceScheduled).Scan(
+		&stats.TotalStatuses, &stats.ConfirmedStatuses, &stats.FirstAskConfirmed, &stats.TeachersCount, &stats.TotalReminders,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting response rate stats: %w", err)
+	}
+	return &stats, nil
+}
+
+func (r *PostgresNotificationRepository) GetTeacherReliability(ctx context.Context, teacherID int64, from, to time.Time) (float64, int, error) {
+	query := `SELECT COUNT(*),
+			   COUNT(*) FILTER (WHERE trs.status = $4 AND trs.response_attempts = 0)
+			   FROM teacher_report_statuses trs
+			   JOIN notification_cycles nc ON nc.id = trs.cycle_id
+			   WHERE trs.teacher_id = $1 AND nc.cycle_date >= $2 AND nc.cycle_date < $3 AND nc.source = $5`
+	var totalReports, firstTryConfirmed int
+	err := r.db.QueryRowContext(ctx, query, teacherID, from, to, notification.StatusAnsweredYes, notification.CycleSourceScheduled).Scan(
+		&totalReports, &firstTryConfirmed,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error getting teacher reliability: %w", err)
+	}
+	return notification.Percentage(firstTryConfirmed, totalReports), totalReports, nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"teacher_notification_bot/internal/domain/notification"
+	"testing"
+	"time"
+)
+
+// statusUpdates answers report status updates by ID: updated rows return updatedAt, missing IDs match no
+// row and failing IDs return errBroken.
+func statusUpdates(updatedAt time.Time, missing, failing int64) func(string, []driver.Value) (*fakeRows, error) {
+	return func(query string, args []driver.Value) (*fakeRows, error) {
+		if !strings.Contains(query, "UPDATE teacher_report_statuses") {
+			return nil, nil // Savepoints
+		}
+		switch args[4] {
+		case missing:
+			return &fakeRows{columns: []string{"updated_at"}}, nil
+		case failing:
+			return nil, errBroken
+		}
+		return &fakeRows{columns: []string{"updated_at"}, values: [][]driver.Value{{updatedAt}}}, nil
+	}
+}
+
+var errBroken = errors.New("value violates check constraint")
+
+func testStatuses() []*notification.ReportStatus {
+	statuses := make([]*notification.ReportStatus, 3)
+	for i := range statuses {
+		statuses[i] = &notification.ReportStatus{ID: int64(i + 1), Status: notification.StatusAnsweredYes}
+	}
+	return statuses
+}
+
+func TestBulkUpdateReportStatusesReportsFailuresPerRow(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	db, fake := newFakeDB(t, statusUpdates(updatedAt, 2, 3))
+	repo := NewPostgresNotificationRepository(db, discardLogger())
+	statuses := testStatuses()
+
+	err := repo.BulkUpdateReportStatuses(context.Background(), statuses)
+
+	var bulkErr *BulkUpdateError
+	if !errors.As(err, &bulkErr) {
+		t.Fatalf("err = %v, want *BulkUpdateError", err)
+	}
+	if len(bulkErr.Failures) != 2 || bulkErr.Failures[2] != ErrReportStatusNotFound || !errors.Is(bulkErr.Failures[3], errBroken) {
+		t.Errorf("failures = %v, want status 2 not found and status 3 broken", bulkErr.Failures)
+	}
+	if !statuses[0].UpdatedAt.Equal(updatedAt) {
+		t.Errorf("status 1: UpdatedAt = %v, want %v", statuses[0].UpdatedAt, updatedAt)
+	}
+
+	var rolledBack, released int
+	log := fake.statements()
+	for _, stmt := range log {
+		switch stmt {
+		case "ROLLBACK TO SAVEPOINT bulk_update_row":
+			rolledBack++
+		case "RELEASE SAVEPOINT bulk_update_row":
+			released++
+		}
+	}
+	if rolledBack != 2 || released != 1 {
+		t.Errorf("%d rows rolled back to the savepoint and %d released, want 2 and 1", rolledBack, released)
+	}
+	if last := log[len(log)-1]; last != "COMMIT" {
+		t.Errorf("transaction ended with %q, want COMMIT so the successful row is kept", last)
+	}
+}
+
+func TestBulkUpdateReportStatusesMatchesPerRowUpdates(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	db, _ := newFakeDB(t, statusUpdates(updatedAt, 2, 3))
+	repo := NewPostgresNotificationRepository(db, discardLogger())
+
+	bulk := testStatuses()
+	var bulkErr *BulkUpdateError
+	if err := repo.BulkUpdateReportStatuses(context.Background(), bulk); !errors.As(err, &bulkErr) {
+		t.Fatalf("err = %v, want *BulkUpdateError", err)
+	}
+	for i, rs := range testStatuses() {
+		rowErr := repo.UpdateReportStatus(context.Background(), rs)
+		bulkRowErr := bulkErr.Failures[rs.ID]
+		for _, target := range []error{ErrReportStatusNotFound, errBroken} {
+			if errors.Is(rowErr, target) != errors.Is(bulkRowErr, target) {
+				t.Errorf("status %d: per-row error %v, bulk error %v", rs.ID, rowErr, bulkRowErr)
+			}
+		}
+		if (rowErr == nil) != (bulkRowErr == nil) {
+			t.Errorf("status %d: per-row error %v, bulk error %v", rs.ID, rowErr, bulkRowErr)
+		}
+		if !rs.UpdatedAt.Equal(bulk[i].UpdatedAt) {
+			t.Errorf("status %d: per-row UpdatedAt %v, bulk %v", rs.ID, rs.UpdatedAt, bulk[i].UpdatedAt)
+		}
+	}
+}
+
+func TestBulkCreateReportStatusesRollsBackOnFailure(t *testing.T) {
+	db, fake := newFakeDB(t, func(query string, args []driver.Value) (*fakeRows, error) {
+		if strings.Contains(query, "INSERT INTO teacher_report_statuses") && args[0] == int64(2) {
+			return nil, errBroken
+		}
+		return nil, nil
+	})
+	repo := NewPostgresNotificationRepository(db, discardLogger())
+	statuses := []*notification.ReportStatus{
+		{TeacherID: 1, CycleID: 1, ReportKey: notification.ReportKeyTable1Lessons, Status: notification.StatusPendingQuestion},
+		{TeacherID: 2, CycleID: 1, ReportKey: notification.ReportKeyTable1Lessons, Status: notification.StatusPendingQuestion},
+	}
+
+	if err := repo.BulkCreateReportStatuses(context.Background(), statuses); !errors.Is(err, errBroken) {
+		t.Fatalf("err = %v, want the failing insert's error", err)
+	}
+	log := fake.statements()
+	if last := log[len(log)-1]; last != "ROLLBACK" {
+		t.Errorf("transaction ended with %q, want ROLLBACK so no status of the batch is kept", last)
+	}
+}
+
+// statusTable keeps one teacher_report_statuses row, written by status updates and read by ID lookups.
+type statusTable struct {
+	id                            int64
+	status                        driver.Value
+	lastNotifiedAt, remindAt      driver.Value
+	responseAttempts              driver.Value
+	createdAt, updatedAt          time.Time
+	teacherID, cycleID, reportKey driver.Value
+}
+
+func (st *statusTable) handle(query string, args []driver.Value) (*fakeRows, error) {
+	switch {
+	case strings.Contains(query, "UPDATE teacher_report_statuses"):
+		if args[4] != st.id {
+			return &fakeRows{columns: []string{"updated_at"}}, nil
+		}
+		st.status, st.lastNotifiedAt, st.responseAttempts, st.remindAt = args[0], args[1], args[2], args[3]
+		st.updatedAt = time.Now()
+		return &fakeRows{columns: []string{"updated_at"}, values: [][]driver.Value{{st.updatedAt}}}, nil
+	case strings.Contains(query, "FROM teacher_report_statuses WHERE id = $1"):
+		columns := []string{"id", "teacher_id", "cycle_id", "report_key", "status", "last_notified_at", "response_attempts", "created_at", "updated_at", "remind_at"}
+		if args[0] != st.id {
+			return &fakeRows{columns: columns}, nil
+		}
+		row := []driver.Value{st.id, st.teacherID, st.cycleID, st.reportKey, st.status, st.lastNotifiedAt, st.responseAttempts, st.createdAt, st.updatedAt, st.remindAt}
+		return &fakeRows{columns: columns, values: [][]driver.Value{row}}, nil
+	}
+	return nil, nil
+}
+
+func TestReportStatusRemindAtRoundTrip(t *testing.T) {
+	table := &statusTable{id: 7, teacherID: int64(1), cycleID: int64(2), reportKey: string(notification.ReportKeyTable1Lessons),
+		status: string(notification.StatusPendingQuestion), responseAttempts: int64(0), createdAt: time.Now()}
+	db, _ := newFakeDB(t, table.handle)
+	repo := NewPostgresNotificationRepository(db, discardLogger())
+	ctx := context.Background()
+
+	rs, err := repo.GetReportStatusByID(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remindAt := time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC)
+	rs.Status = notification.StatusAwaitingReminder1H
+	rs.RemindAt = sql.NullTime{Time: remindAt, Valid: true}
+	if err := repo.UpdateReportStatus(ctx, rs); err != nil {
+		t.Fatalf("UpdateReportStatus: %v", err)
+	}
+
+	reloaded, err := repo.GetReportStatusByID(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Status != notification.StatusAwaitingReminder1H || !reloaded.RemindAt.Valid || !reloaded.RemindAt.Time.Equal(remindAt) {
+		t.Errorf("reloaded status %s with remind_at %v, want AWAITING_REMINDER_1H at %v", reloaded.Status, reloaded.RemindAt, remindAt)
+	}
+	if reloaded.LastNotifiedAt.Valid {
+		t.Errorf("last_notified_at = %v, want it still unset", reloaded.LastNotifiedAt.Time)
+	}
+
+	// Clearing the reminder is persisted too.
+	reloaded.RemindAt = sql.NullTime{}
+	if err := repo.UpdateReportStatus(ctx, reloaded); err != nil {
+		t.Fatalf("UpdateReportStatus: %v", err)
+	}
+	cleared, err := repo.GetReportStatusByID(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleared.RemindAt.Valid {
+		t.Errorf("after clearing: remind_at = %v, want it unset", cleared.RemindAt.Time)
+	}
+}
+
+func TestRunLockReleaseMatchesOwner(t *testing.T) {
+	var acquireOwner, releaseOwner driver.Value
+	db, _ := newFakeDB(t, func(query string, args []driver.Value) (*fakeRows, error) {
+		switch {
+		case strings.Contains(query, "INSERT INTO notification_run_locks"):
+			acquireOwner = args[2]
+			return &fakeRows{columns: []string{"owner"}, values: [][]driver.Value{{args[2]}}}, nil
+		case strings.Contains(query, "DELETE FROM notification_run_locks"):
+			if !strings.Contains(query, "owner = $2") {
+				t.Errorf("release does not match the owner:\n%s", query)
+			}
+			releaseOwner = args[1]
+		}
+		return nil, nil
+	})
+	repo := NewPostgresNotificationRepository(db, discardLogger())
+	ctx := context.Background()
+
+	owner, err := repo.AcquireRunLock(ctx, "MID_MONTH:2024-05-15:1", time.Hour)
+	if err != nil {
+		t.Fatalf("AcquireRunLock: %v", err)
+	}
+	if owner == "" || owner != acquireOwner {
+		t.Fatalf("AcquireRunLock returned owner %q, stored %v", owner, acquireOwner)
+	}
+	if err := repo.ReleaseRunLock(ctx, "MID_MONTH:2024-05-15:1", owner); err != nil {
+		t.Fatalf("ReleaseRunLock: %v", err)
+	}
+	if releaseOwner != owner {
+		t.Errorf("released with owner %v, want %q", releaseOwner, owner)
+	}
+
+	// Each acquisition gets its own token.
+	if other, err := repo.AcquireRunLock(ctx, "MID_MONTH:2024-05-15:1", time.Hour); err != nil || other == owner {
+		t.Errorf("second acquisition: owner %q (err %v), want a token other than %q", other, err, owner)
+	}
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+var ErrSettingNotFound = fmt.Errorf("system setting not found")
+
+type PostgresSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSettingsRepository(db *sql.DB) *PostgresSettingsRepository {
+	return &PostgresSettingsRepository{db: db}
+}
+
+func (r *PostgresSettingsRepository) Get(ctx context.Context, key string) (string, error) {
+	query := `SELECT value FROM system_settings WHERE key = $1`
+	var value string
+	err := r.db.QueryRowContext(ctx, query, key).Scan(&value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrSettingNotFound
+		}
+		return "", fmt.Errorf("error getting system setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (r *PostgresSettingsRepository) Set(ctx context.Context, key string, value string) error {
+	query := `INSERT INTO system_settings (key, value)
+               VALUES ($1, $2)
+               ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()`
+	if _, err := r.db.ExecContext(ctx, query, key, value); err != nil {
+		return fmt.Errorf("error setting system setting %s: %w", key, err)
+	}
+	return nil
+}
@@ -8,24 +8,91 @@ import (
 
 	"teacher_notification_bot/internal/domain/teacher" // Adjust import path
 
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver; pq.Array for ANY($1) parameters
+	"github.com/sirupsen/logrus"
 )
 
 // Custom errors
 var ErrTeacherNotFound = fmt.Errorf("teacher not found")
 var ErrDuplicateTelegramID = fmt.Errorf("teacher with this Telegram ID already exists")
 
+// teacherColumns is the column list shared by every query that loads a full teacher row.
+// Keep it in sync with scanTeacher.
+const teacherColumns = `id, telegram_id, first_name, last_name, is_active, notify_manager_on_complete, work_days, has_started_bot, reminder_delay_override_seconds, display_name, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTeacher scans a row selected with teacherColumns.
+func scanTeacher(row rowScanner) (*teacher.Teacher, error) {
+	t := &teacher.Teacher{}
+	err := row.Scan(&t.ID, &t.TelegramID, &t.FirstName, &t.LastName, &t.IsActive, &t.NotifyManagerOnComplete, &t.WorkDays, &t.HasStartedBot, &t.ReminderDelayOverride, &t.DisplayName, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx, so the same queries can run inside a transaction.
+type dbtx interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
 type PostgresTeacherRepository struct {
-	db *sql.DB
+	db    dbtx
+	conn  *sql.DB // Starts transactions; nil for a repository bound to one (see WithTx)
+	retry RetryPolicy
+	log   *logrus.Entry
 }
 
-func NewPostgresTeacherRepository(db *sql.DB) *PostgresTeacherRepository {
-	return &PostgresTeacherRepository{db: db}
+func NewPostgresTeacherRepository(db *sql.DB, baseLogger *logrus.Entry) *PostgresTeacherRepository {
+	return &PostgresTeacherRepository{db: db, conn: db, log: baseLogger}
+}
+
+// WithTx runs fn with a repository bound to one transaction, committed when fn returns nil and rolled back
+// otherwise. Queries are not retried inside it: a dropped connection aborts the transaction anyway.
+// Calling WithTx on a transaction-bound repository joins the outer transaction.
+func (r *PostgresTeacherRepository) WithTx(ctx context.Context, fn func(txRepo teacher.Repository) error) error {
+	if r.conn == nil {
+		return fn(r)
+	}
+	txn, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin teacher transaction: %w", err)
+	}
+	defer txn.Rollback() // Rollback if not committed
+
+	if err := fn(&PostgresTeacherRepository{db: txn, log: r.log}); err != nil {
+		return err
+	}
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit teacher transaction: %w", err)
+	}
+	return nil
+}
+
+// teacherLogger carries the identifying fields of a teacher, so DB errors can be matched with service logs.
+func (r *PostgresTeacherRepository) teacherLogger(operation string, t *teacher.Teacher) *logrus.Entry {
+	return r.log.WithFields(logrus.Fields{
+		"operation":     operation,
+		"teacher_id":    t.ID,
+		"teacher_tg_id": t.TelegramID,
+	})
+}
+
+// WithRetry enables retrying of hot-path queries on transient connection errors.
+func (r *PostgresTeacherRepository) WithRetry(policy RetryPolicy) *PostgresTeacherRepository {
+	r.retry = policy
+	return r
 }
 
 func (r *PostgresTeacherRepository) Create(ctx context.Context, t *teacher.Teacher) error {
-	query := `INSERT INTO teachers (telegram_id, first_name, last_name, is_active)
-               VALUES ($1, $2, $3, $4)
+	query := `INSERT INTO teachers (telegram_id, first_name, last_name, is_active, notify_manager_on_complete, work_days, has_started_bot)
+               VALUES ($1, $2, $3, $4, $5, $6, $7)
                RETURNING id, created_at, updated_at`
 
 	// Ensure IsActive is set, default to true if not explicitly provided for a new teacher.
@@ -35,23 +102,49 @@ func (r *PostgresTeacherRepository) Create(ctx context.Context, t *teacher.Teach
 		// For clarity, let's assume t.IsActive is set by the caller (e.g. application service).
 	}
 
-	err := r.db.QueryRowContext(ctx, query, t.TelegramID, t.FirstName, t.LastName, t.IsActive).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, t.TelegramID, t.FirstName, t.LastName, t.IsActive, t.NotifyManagerOnComplete, t.WorkDays, t.HasStartedBot).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
 	if err != nil {
 		// Basic check for unique violation on telegram_id.
 		// More robust check might involve specific pq error codes.
 		if strings.Contains(err.Error(), "unique constraint") && strings.Contains(err.Error(), "teachers_telegram_id_key") { // Example check
 			return ErrDuplicateTelegramID
 		}
+		r.teacherLogger("Create", t).WithError(err).Error("Failed to create teacher")
 		return fmt.Errorf("error creating teacher: %w", err)
 	}
 	return nil
 }
 
+// UpsertTeacher inserts or updates the teacher by Telegram ID in a single statement.
+// Per-teacher settings (manager notifications, work days) of an existing row are left untouched.
+func (r *PostgresTeacherRepository) UpsertTeacher(ctx context.Context, t *teacher.Teacher, allowReactivate bool) (bool, error) {
+	// xmax is 0 only for a freshly inserted row version, which tells an insert apart from an update.
+	// updated_at is owned by the set_timestamp_teachers trigger, which also fires for the DO UPDATE branch.
+	query := `INSERT INTO teachers (telegram_id, first_name, last_name, is_active, notify_manager_on_complete, work_days)
+               VALUES ($1, $2, $3, $4, $5, $6)
+               ON CONFLICT (telegram_id) DO UPDATE
+               SET first_name = EXCLUDED.first_name,
+                   last_name = EXCLUDED.last_name,
+                   is_active = CASE WHEN $7 THEN EXCLUDED.is_active ELSE teachers.is_active AND EXCLUDED.is_active END
+               RETURNING ` + teacherColumns + `, (xmax = 0)`
+
+	stored := &teacher.Teacher{}
+	var created bool
+	err := r.db.QueryRowContext(ctx, query, t.TelegramID, t.FirstName, t.LastName, t.IsActive, t.NotifyManagerOnComplete, t.WorkDays, allowReactivate).Scan(
+		&stored.ID, &stored.TelegramID, &stored.FirstName, &stored.LastName, &stored.IsActive,
+		&stored.NotifyManagerOnComplete, &stored.WorkDays, &stored.HasStartedBot, &stored.ReminderDelayOverride, &stored.DisplayName, &stored.CreatedAt, &stored.UpdatedAt, &created)
+	if err != nil {
+		r.teacherLogger("UpsertTeacher", t).WithError(err).Error("Failed to upsert teacher")
+		return false, fmt.Errorf("error upserting teacher: %w", err)
+	}
+	*t = *stored
+	return created, nil
+}
+
 func (r *PostgresTeacherRepository) GetByID(ctx context.Context, id int64) (*teacher.Teacher, error) {
-	query := `SELECT id, telegram_id, first_name, last_name, is_active, created_at, updated_at
+	query := `SELECT ` + teacherColumns + `
                FROM teachers WHERE id = $1`
-	t := &teacher.Teacher{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.TelegramID, &t.FirstName, &t.LastName, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+	t, err := scanTeacher(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrTeacherNotFound
@@ -61,11 +154,42 @@ func (r *PostgresTeacherRepository) GetByID(ctx context.Context, id int64) (*tea
 	return t, nil
 }
 
+func (r *PostgresTeacherRepository) GetByIDs(ctx context.Context, ids []int64) (map[int64]*teacher.Teacher, error) {
+	teachers := make(map[int64]*teacher.Teacher, len(ids))
+	if len(ids) == 0 {
+		return teachers, nil
+	}
+	query := `SELECT ` + teacherColumns + `
+               FROM teachers WHERE id = ANY($1)`
+
+	var rows *sql.Rows
+	err := r.retry.do(ctx, func() error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query, pq.Array(ids))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting teachers by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTeacher(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning teacher: %w", err)
+		}
+		teachers[t.ID] = t
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating teachers: %w", err)
+	}
+	return teachers, nil
+}
+
 func (r *PostgresTeacherRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*teacher.Teacher, error) {
-	query := `SELECT id, telegram_id, first_name, last_name, is_active, created_at, updated_at
+	query := `SELECT ` + teacherColumns + `
                FROM teachers WHERE telegram_id = $1`
-	t := &teacher.Teacher{}
-	err := r.db.QueryRowContext(ctx, query, telegramID).Scan(&t.ID, &t.TelegramID, &t.FirstName, &t.LastName, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+	t, err := scanTeacher(r.db.QueryRowContext(ctx, query, telegramID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrTeacherNotFound
@@ -77,25 +201,34 @@ func (r *PostgresTeacherRepository) GetByTelegramID(ctx context.Context, telegra
 
 func (r *PostgresTeacherRepository) Update(ctx context.Context, t *teacher.Teacher) error {
 	query := `UPDATE teachers
-               SET first_name = $1, last_name = $2, is_active = $3, updated_at = NOW()
-               WHERE id = $4
-               RETURNING updated_at` // updated_at is handled by trigger too, but RETURNING ensures we get the value
+               SET first_name = $1, last_name = $2, is_active = $3, notify_manager_on_complete = $4, work_days = $5, has_started_bot = $6,
+                   reminder_delay_override_seconds = $7, display_name = $8
+               WHERE id = $9
+               RETURNING updated_at` // updated_at is owned by the trigger; RETURNING reports the value it wrote
 
-	err := r.db.QueryRowContext(ctx, query, t.FirstName, t.LastName, t.IsActive, t.ID).Scan(&t.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, t.FirstName, t.LastName, t.IsActive, t.NotifyManagerOnComplete, t.WorkDays, t.HasStartedBot, t.ReminderDelayOverride, t.DisplayName, t.ID).Scan(&t.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows { // Should not happen if ID is valid, but good check
 			return ErrTeacherNotFound
 		}
+		r.teacherLogger("Update", t).WithError(err).Error("Failed to update teacher")
 		return fmt.Errorf("error updating teacher: %w", err)
 	}
 	return nil
 }
 
+// ListActive returns active teachers ordered by name. The id tie-breaker keeps the order stable
+// for identical names, which also makes the cycle send order reproducible.
 func (r *PostgresTeacherRepository) ListActive(ctx context.Context) ([]*teacher.Teacher, error) {
-	query := `SELECT id, telegram_id, first_name, last_name, is_active, created_at, updated_at
-               FROM teachers WHERE is_active = TRUE ORDER BY first_name, last_name`
+	query := `SELECT ` + teacherColumns + `
+               FROM teachers WHERE is_active = TRUE ORDER BY first_name, last_name, id`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	var rows *sql.Rows
+	err := r.retry.do(ctx, func() error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error listing active teachers: %w", err)
 	}
@@ -103,8 +236,8 @@ func (r *PostgresTeacherRepository) ListActive(ctx context.Context) ([]*teacher.
 
 	teachers := make([]*teacher.Teacher, 0)
 	for rows.Next() {
-		t := &teacher.Teacher{}
-		if err := rows.Scan(&t.ID, &t.TelegramID, &t.FirstName, &t.LastName, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanTeacher(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning active teacher: %w", err)
 		}
 		teachers = append(teachers, t)
@@ -116,7 +249,7 @@ func (r *PostgresTeacherRepository) ListActive(ctx context.Context) ([]*teacher.
 }
 
 func (r *PostgresTeacherRepository) ListAll(ctx context.Context) ([]*teacher.Teacher, error) {
-	query := `SELECT id, telegram_id, first_name, last_name, is_active, created_at, updated_at
+	query := `SELECT ` + teacherColumns + `
                FROM teachers ORDER BY id`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -127,8 +260,8 @@ func (r *PostgresTeacherRepository) ListAll(ctx context.Context) ([]*teacher.Tea
 
 	teachers := make([]*teacher.Teacher, 0)
 	for rows.Next() {
-		t := &teacher.Teacher{}
-		if err := rows.Scan(&t.ID, &t.TelegramID, &t.FirstName, &t.LastName, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanTeacher(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning teacher from all list: %w", err)
 		}
 		teachers = append(teachers, t)
@@ -138,3 +271,48 @@ func (r *PostgresTeacherRepository) ListAll(ctx context.Context) ([]*teacher.Tea
 	}
 	return teachers, nil
 }
+
+func (r *PostgresTeacherRepository) CountActive(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM teachers WHERE is_active = TRUE`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting active teachers: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresTeacherRepository) CountActiveWithNameKey(ctx context.Context, nameKey string, excludeID int64) (int, error) {
+	// Mirrors teacher.NameKey on the stored first and last name.
+	query := `SELECT COUNT(*) FROM teachers
+               WHERE is_active = TRUE AND id <> $1
+                 AND lower(btrim(regexp_replace(first_name || ' ' || COALESCE(last_name, ''), '\s+', ' ', 'g'))) = $2`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, excludeID, nameKey).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting active teachers by name: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresTeacherRepository) FindDuplicateTelegramIDs(ctx context.Context) ([]int64, error) {
+	// Counts all rows, not only active ones: any duplicate makes GetByTelegramID ambiguous.
+	query := `SELECT telegram_id FROM teachers GROUP BY telegram_id HAVING COUNT(*) > 1 ORDER BY telegram_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error finding duplicate telegram IDs: %w", err)
+	}
+	defer rows.Close()
+
+	telegramIDs := make([]int64, 0)
+	for rows.Next() {
+		var telegramID int64
+		if err := rows.Scan(&telegramID); err != nil {
+			return nil, fmt.Errorf("error scanning duplicate telegram ID: %w", err)
+		}
+		telegramIDs = append(telegramIDs, telegramID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duplicate telegram IDs: %w", err)
+	}
+	return telegramIDs, nil
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"teacher_notification_bot/internal/domain/teacher"
+	"testing"
+	"time"
+)
+
+// teacherInserts accepts inserts of teachers except the one with failingTelegramID.
+func teacherInserts(failingTelegramID int64) func(string, []driver.Value) (*fakeRows, error) {
+	nextID := int64(0)
+	return func(query string, args []driver.Value) (*fakeRows, error) {
+		if !strings.Contains(query, "INSERT INTO teachers") {
+			return nil, nil
+		}
+		if args[0] == failingTelegramID {
+			return nil, errBroken
+		}
+		nextID++
+		now := time.Now()
+		return &fakeRows{columns: []string{"id", "created_at", "updated_at"}, values: [][]driver.Value{{nextID, now, now}}}, nil
+	}
+}
+
+func TestTeacherWithTx(t *testing.T) {
+	const failingTelegramID = 102
+	tests := []struct {
+		name        string
+		telegramIDs []int64
+		wantErr     bool
+		want        []string
+	}{
+		{name: "every row saved", telegramIDs: []int64{101, 103}, want: []string{"BEGIN", "INSERT", "INSERT", "COMMIT"}},
+		{name: "failing row rolls back", telegramIDs: []int64{101, failingTelegramID, 103}, wantErr: true, want: []string{"BEGIN", "INSERT", "INSERT", "ROLLBACK"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, teacherInserts(failingTelegramID))
+			repo := NewPostgresTeacherRepository(db, discardLogger())
+
+			err := repo.WithTx(context.Background(), func(txRepo teacher.Repository) error {
+				for _, tgID := range tt.telegramIDs {
+					if err := txRepo.Create(context.Background(), &teacher.Teacher{TelegramID: tgID, FirstName: "Анна", IsActive: true}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithTx error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errBroken) {
+				t.Errorf("WithTx error = %v, want the failing row's error", err)
+			}
+			var got []string
+			for _, stmt := range fake.statements() {
+				verb, _, _ := strings.Cut(stmt, " ")
+				got = append(got, verb)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statements = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeacherWithTxJoinsOuterTransaction(t *testing.T) {
+	db, fake := newFakeDB(t, teacherInserts(0))
+	repo := NewPostgresTeacherRepository(db, discardLogger())
+
+	err := repo.WithTx(context.Background(), func(txRepo teacher.Repository) error {
+		return txRepo.WithTx(context.Background(), func(inner teacher.Repository) error {
+			return inner.Create(context.Background(), &teacher.Teacher{TelegramID: 101, FirstName: "Анна", IsActive: true})
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	var begins, commits int
+	for _, stmt := range fake.statements() {
+		switch stmt {
+		case "BEGIN":
+			begins++
+		case "COMMIT":
+			commits++
+		}
+	}
+	if begins != 1 || commits != 1 {
+		t.Errorf("%d transactions begun and %d committed, want one of each", begins, commits)
+	}
+}
+
+func TestListActiveOrdersByNameThenID(t *testing.T) {
+	var query string
+	db, _ := newFakeDB(t, func(q string, _ []driver.Value) (*fakeRows, error) {
+		query = q
+		return nil, nil
+	})
+	repo := NewPostgresTeacherRepository(db, discardLogger())
+
+	if _, err := repo.ListActive(context.Background()); err != nil {
+		t.Fatalf("ListActive: %v", err)
+	}
+	// Namesakes, including ones without a last name, must come back in the same order every run.
+	if !strings.HasSuffix(strings.TrimSpace(query), "ORDER BY first_name, last_name, id") {
+		t.Errorf("ListActive query does not end with the stable ordering:\n%s", query)
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RetryPolicy retries repository calls that failed because the database was unreachable
+// (e.g. Postgres restarting). Logical errors such as ErrTeacherNotFound are never retried.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; values below 2 disable retrying
+	BaseDelay   time.Duration // Delay before the first retry; doubled for every further retry
+}
+
+// do runs fn, retrying transient connection errors with exponential backoff.
+// Only use it for statements that are safe to repeat when the connection failed before they ran.
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	delay := p.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !isTransientConnError(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// IsConnectionError reports whether err means the database could not be reached or dropped the connection,
+// as opposed to a logical error (not found, constraint violation). Callers use it to tell outages from bugs.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isTransientConnError(err) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08 is "connection exception"; 57P01-57P03 mean the server is shutting down or not yet accepting.
+		return pqErr.Code.Class() == "08" || pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03"
+	}
+	return false
+}
+
+// isTransientConnError reports whether err means the statement never reached a working connection.
+// A reset connection is not one of them: the server may have run the statement before the reset.
+func isTransientConnError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
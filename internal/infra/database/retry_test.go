@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIsTransientConnError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "bad conn", err: driver.ErrBadConn, want: true},
+		{name: "wrapped bad conn", err: fmt.Errorf("query: %w", driver.ErrBadConn), want: true},
+		{name: "refused", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, want: true},
+		{name: "dial failure", err: &net.OpError{Op: "dial", Err: errors.New("no such host")}, want: true},
+		{name: "reset mid-statement", err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: false},
+		{name: "plain reset", err: syscall.ECONNRESET, want: false},
+		{name: "not found", err: ErrCycleNotFound, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientConnError(tt.err); got != tt.want {
+				t.Errorf("isTransientConnError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "success", errs: []error{nil}, wantCalls: 1},
+		{name: "recovers", errs: []error{driver.ErrBadConn, nil}, wantCalls: 2},
+		{name: "gives up", errs: []error{driver.ErrBadConn, driver.ErrBadConn, driver.ErrBadConn}, wantCalls: 3, wantErr: driver.ErrBadConn},
+		{name: "logical error is not retried", errs: []error{ErrCycleNotFound}, wantCalls: 1, wantErr: ErrCycleNotFound},
+		{name: "reset is not retried", errs: []error{syscall.ECONNRESET}, wantCalls: 1, wantErr: syscall.ECONNRESET},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := policy.do(context.Background(), func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if err != tt.wantErr {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "bad conn", err: driver.ErrBadConn, want: true},
+		{name: "conn done", err: fmt.Errorf("tx: %w", sql.ErrConnDone), want: true},
+		{name: "reset", err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: true},
+		{name: "connection exception", err: &pq.Error{Code: "08006"}, want: true},
+		{name: "admin shutdown", err: &pq.Error{Code: "57P01"}, want: true},
+		{name: "cannot connect now", err: &pq.Error{Code: "57P03"}, want: true},
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, want: false},
+		{name: "no rows", err: sql.ErrNoRows, want: false},
+		{name: "not found", err: ErrTeacherNotFound, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnectionError(tt.err); got != tt.want {
+				t.Errorf("IsConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// internal/infra/httpserver/server.go
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/version"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// statsRequestTimeout bounds the DB work of a single /stats request.
+const statsRequestTimeout = 5 * time.Second
+
+// staleHeartbeatAfter is how old the latest scheduler heartbeat may be before /readyz reports the bot as not ready.
+const staleHeartbeatAfter = 3 * time.Minute
+
+// HeartbeatSource reports when the scheduler's heartbeat job last ran (zero if it has not run yet).
+type HeartbeatSource interface {
+	LastHeartbeat() time.Time
+}
+
+// StatsSource provides the snapshot served by /stats.
+type StatsSource interface {
+	GetOperationalStats(ctx context.Context) (*app.OperationalStats, error)
+}
+
+// Server is a small read-only HTTP server for health and readiness checks and a JSON stats snapshot.
+type Server struct {
+	httpServer *http.Server
+	stats      StatsSource
+	heartbeat  HeartbeatSource // Optional; see WithHeartbeat
+	log        *logrus.Entry
+}
+
+func NewServer(addr string, stats StatsSource, baseLogger *logrus.Entry) *Server {
+	s := &Server{stats: stats, log: baseLogger}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	mux.HandleFunc("/stats", s.handleStats)
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// WithHeartbeat makes /readyz report the scheduler's latest heartbeat and fail when it is stale.
+func (s *Server) WithHeartbeat(source HeartbeatSource) *Server {
+	s.heartbeat = source
+	return s
+}
+
+// Start serves in the background. Listen errors are logged, not fatal: the bot keeps working without the endpoint.
+func (s *Server) Start() {
+	go func() {
+		s.log.WithField("addr", s.httpServer.Addr).Info("Starting HTTP server")
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.WithError(err).Error("HTTP server stopped")
+		}
+	}()
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// healthResponse is the /healthz body: a fixed status plus what build is running and since when.
+type healthResponse struct {
+	Status string `json:"status"`
+	version.Info
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(healthResponse{Status: "ok", Info: version.Current()}); err != nil {
+		s.log.WithError(err).Warn("Failed to write health response")
+	}
+}
+
+// readyResponse is the /readyz body.
+type readyResponse struct {
+	Status        string     `json:"status"` // "ok", "starting" (no heartbeat yet) or "stale"
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
+}
+
+// handleReady answers 200 while the scheduler heartbeat is fresh and 503 otherwise, so an orchestrator can
+// restart a bot whose scheduler stalled. Without a heartbeat source it always reports ready.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	resp := readyResponse{Status: "ok"}
+	code := http.StatusOK
+	if s.heartbeat != nil {
+		last := s.heartbeat.LastHeartbeat()
+		switch {
+		case last.IsZero():
+			resp.Status = "starting"
+			code = http.StatusServiceUnavailable
+		case time.Since(last) > staleHeartbeatAfter:
+			resp.Status = "stale"
+			resp.LastHeartbeat = &last
+			code = http.StatusServiceUnavailable
+		default:
+			resp.LastHeartbeat = &last
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log.WithError(err).Warn("Failed to write readiness response")
+	}
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), statsRequestTimeout)
+	defer cancel()
+
+	stats, err := s.stats.GetOperationalStats(ctx)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to collect operational stats")
+		http.Error(w, "failed to collect stats", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		s.log.WithError(err).Warn("Failed to write stats response")
+	}
+}
@@ -2,9 +2,12 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"teacher_notification_bot/internal/infra/config"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -12,6 +15,12 @@ import (
 // Log is the global logger instance
 var Log = logrus.New()
 
+var (
+	levelMu         sync.Mutex
+	configuredLevel = logrus.InfoLevel // Level chosen by Init; runtime overrides revert to it
+	revertTimer     *time.Timer
+)
+
 // Init initializes the global logger based on application configuration.
 func Init(cfg *config.AppConfig) {
 	Log.SetOutput(os.Stdout) // Default output
@@ -24,6 +33,7 @@ func Init(cfg *config.AppConfig) {
 	} else {
 		Log.SetLevel(level)
 	}
+	configuredLevel = Log.GetLevel()
 
 	// Set Log Formatter
 	if strings.ToLower(cfg.Environment) == "production" || strings.ToLower(cfg.Environment) == "staging" {
@@ -48,3 +58,34 @@ func Init(cfg *config.AppConfig) {
 func Get() *logrus.Logger {
 	return Log
 }
+
+// SetLevel changes the log level at runtime. When revertAfter is positive the configured
+// level is restored after that long, so a temporary debug session can't be forgotten.
+// A new call cancels any pending revert.
+func SetLevel(raw string, revertAfter time.Duration) (logrus.Level, error) {
+	level, err := logrus.ParseLevel(strings.ToLower(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid log level '%s': %w", raw, err)
+	}
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	if revertTimer != nil {
+		revertTimer.Stop()
+		revertTimer = nil
+	}
+	Log.SetLevel(level)
+	Log.Infof("Log level changed at runtime to: %s", level.String())
+
+	if revertAfter > 0 && level != configuredLevel {
+		revertTimer = time.AfterFunc(revertAfter, func() {
+			levelMu.Lock()
+			defer levelMu.Unlock()
+			Log.SetLevel(configuredLevel)
+			revertTimer = nil
+			Log.Infof("Log level reverted to configured level: %s", configuredLevel.String())
+		})
+	}
+	return level, nil
+}
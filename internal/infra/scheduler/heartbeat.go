@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// heartbeatSpec fires the heartbeat job every minute.
+const heartbeatSpec = "* * * * *"
+
+// maxHeartbeatGap is the longest plausible time between two heartbeats. A longer gap means the scheduler
+// stalled or the clock jumped forward; a negative one means the clock went backwards. Either way, other
+// cron jobs may have fired at the wrong time or not at all.
+const maxHeartbeatGap = 90 * time.Second
+
+// heartbeat remembers when the heartbeat job last ran.
+type heartbeat struct {
+	mu     sync.Mutex
+	last   time.Time
+	paused bool // Set by pause; the next beat measures no gap
+}
+
+// beat records now as the latest heartbeat and returns the gap since the previous one.
+// ok is false when there is nothing to compare with (first beat, or the first after pause).
+func (h *heartbeat) beat(now time.Time) (gap time.Duration, ok bool) {
+	// Drop the monotonic clock reading: Sub would use it and never show the wall clock jumping.
+	now = now.Round(0)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	prev, paused := h.last, h.paused
+	h.last = now
+	h.paused = false
+	if prev.IsZero() || paused {
+		return 0, false
+	}
+	return now.Sub(prev), true
+}
+
+// pause keeps the latest heartbeat but makes the next beat measure no gap, so the time the scheduler was
+// stopped (e.g. during a reload) is not reported as a stall.
+func (h *heartbeat) pause() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.paused = true
+}
+
+func (h *heartbeat) lastBeat() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last
+}
+
+// implausibleHeartbeatGap reports whether the gap between two heartbeats points at clock skew or a stall.
+func implausibleHeartbeatGap(gap time.Duration) bool {
+	return gap < 0 || gap > maxHeartbeatGap
+}
+
+// runHeartbeat is the heartbeat job: it logs the current time and warns when the gap since the previous
+// heartbeat is implausible.
+func (s *NotificationScheduler) runHeartbeat() {
+	now := time.Now()
+	jobLog := s.log.WithFields(logrus.Fields{
+		"job_name": "heartbeat",
+		"now":      now.Format(time.RFC3339),
+	})
+	gap, ok := s.heartbeat.beat(now)
+	if !ok {
+		jobLog.Debug("Scheduler heartbeat")
+		return
+	}
+	jobLog = jobLog.WithField("gap", gap.String())
+	if implausibleHeartbeatGap(gap) {
+		jobLog.WithField("max_gap", maxHeartbeatGap.String()).Warn("Implausible gap between scheduler heartbeats: the clock may be skewed or the scheduler stalled. Cron jobs may have misfired.")
+		return
+	}
+	jobLog.Debug("Scheduler heartbeat")
+}
+
+// LastHeartbeat returns when the heartbeat job last ran; zero before the first run. A stop or reload keeps
+// the value, so readiness does not drop while the scheduler restarts.
+func (s *NotificationScheduler) LastHeartbeat() time.Time {
+	return s.heartbeat.lastBeat()
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatBeat(t *testing.T) {
+	start := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	var h heartbeat
+
+	if _, ok := h.beat(start); ok {
+		t.Fatal("first beat reported a gap")
+	}
+	if gap, ok := h.beat(start.Add(time.Minute)); !ok || gap != time.Minute {
+		t.Fatalf("second beat = %v, %v; want 1m, true", gap, ok)
+	}
+	if gap, ok := h.beat(start); !ok || gap != -time.Minute {
+		t.Fatalf("beat after the clock went back = %v, %v; want -1m, true", gap, ok)
+	}
+	if got := h.lastBeat(); !got.Equal(start) {
+		t.Fatalf("lastBeat() = %v, want %v", got, start)
+	}
+
+	h.pause()
+	if got := h.lastBeat(); !got.Equal(start) {
+		t.Fatalf("lastBeat() after pause = %v, want %v", got, start)
+	}
+	if _, ok := h.beat(start.Add(time.Hour)); ok {
+		t.Fatal("first beat after pause reported a gap")
+	}
+	if gap, ok := h.beat(start.Add(time.Hour + time.Minute)); !ok || gap != time.Minute {
+		t.Fatalf("beat after the paused one = %v, %v; want 1m, true", gap, ok)
+	}
+}
+
+func TestHeartbeatComparesWallClock(t *testing.T) {
+	var h heartbeat
+	now := time.Now() // Carries a monotonic reading, like the heartbeat job's timestamps
+	h.beat(now)
+	if last := h.lastBeat(); strings.Contains(last.String(), "m=") {
+		t.Fatalf("stored heartbeat %v keeps the monotonic reading", last)
+	}
+
+	// The wall clock was set back an hour since the previous beat.
+	gap, ok := h.beat(time.Unix(now.Unix()-3600, 0))
+	if !ok || !implausibleHeartbeatGap(gap) || gap > -59*time.Minute {
+		t.Fatalf("gap after the clock went back an hour = %v, %v; want about -1h", gap, ok)
+	}
+}
+
+func TestImplausibleHeartbeatGap(t *testing.T) {
+	tests := []struct {
+		gap  time.Duration
+		want bool
+	}{
+		{gap: time.Minute, want: false},
+		{gap: 0, want: false},
+		{gap: maxHeartbeatGap, want: false},
+		{gap: maxHeartbeatGap + time.Second, want: true},
+		{gap: time.Hour, want: true},
+		{gap: -time.Second, want: true},
+	}
+	for _, tt := range tests {
+		if got := implausibleHeartbeatGap(tt.gap); got != tt.want {
+			t.Errorf("implausibleHeartbeatGap(%v) = %v, want %v", tt.gap, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"teacher_notification_bot/internal/domain/notification"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxLastDayChecks bounds the search for each last-day run; a daily spec reaches a month end within ~31 fires.
+const maxLastDayChecks = 400
+
+// CycleStart is a scheduled initiation of a notification cycle.
+type CycleStart struct {
+	At   time.Time
+	Type notification.CycleType
+}
+
+// NextCycleStart returns when the next notification cycle will be initiated, given the scheduler's cron specs.
+func NextCycleStart(cronSpec15th, cronSpecDailyCheckForLastDay string, now time.Time) (time.Time, notification.CycleType, error) {
+	starts, err := UpcomingCycleStarts(cronSpec15th, cronSpecDailyCheckForLastDay, now, 1)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return starts[0].At, starts[0].Type, nil
+}
+
+// UpcomingCycleStarts returns the next count cycle initiations after now, earliest first.
+// The end-of-month spec is a daily check that only starts a cycle on the last day of the month,
+// so its fires are walked and only those landing on a month end are kept.
+func UpcomingCycleStarts(cronSpec15th, cronSpecDailyCheckForLastDay string, now time.Time, count int) ([]CycleStart, error) {
+	midMonthSchedule, err := cron.ParseStandard(cronSpec15th)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 15th cron spec %q: %w", cronSpec15th, err)
+	}
+	lastDaySchedule, err := cron.ParseStandard(cronSpecDailyCheckForLastDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid last day cron spec %q: %w", cronSpecDailyCheckForLastDay, err)
+	}
+
+	var midMonth []time.Time
+	for t := midMonthSchedule.Next(now); !t.IsZero() && len(midMonth) < count; t = midMonthSchedule.Next(t) {
+		midMonth = append(midMonth, t)
+	}
+	var endMonth []time.Time
+	for t, i := lastDaySchedule.Next(now), 0; !t.IsZero() && len(endMonth) < count && i < maxLastDayChecks*count; t, i = lastDaySchedule.Next(t), i+1 {
+		if isLastDayOfMonth(t) {
+			endMonth = append(endMonth, t)
+		}
+	}
+
+	// Merge both sorted lists.
+	starts := make([]CycleStart, 0, count)
+	for len(starts) < count && (len(midMonth) > 0 || len(endMonth) > 0) {
+		if len(endMonth) == 0 || (len(midMonth) > 0 && midMonth[0].Before(endMonth[0])) {
+			starts = append(starts, CycleStart{At: midMonth[0], Type: notification.CycleTypeMidMonth})
+			midMonth = midMonth[1:]
+		} else {
+			starts = append(starts, CycleStart{At: endMonth[0], Type: notification.CycleTypeEndMonth})
+			endMonth = endMonth[1:]
+		}
+	}
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("no upcoming cycle start found")
+	}
+	return starts, nil
+}
+
+// cycleTypeOn returns the cycle the scheduler's cron specs start on the calendar day of t, if any.
+// When both start that day, the earlier one wins.
+func cycleTypeOn(cronSpec15th, cronSpecDailyCheckForLastDay string, t time.Time) (notification.CycleType, bool, error) {
+	dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	starts, err := UpcomingCycleStarts(cronSpec15th, cronSpecDailyCheckForLastDay, dayStart.Add(-time.Nanosecond), 1)
+	if err != nil {
+		return "", false, err
+	}
+	if !starts[0].At.Before(dayStart.AddDate(0, 0, 1)) {
+		return "", false, nil
+	}
+	return starts[0].Type, true, nil
+}
+
+// isLastDayOfMonth reports whether t falls on the last day of its month.
+func isLastDayOfMonth(t time.Time) bool {
+	// Calculate the first day of the next month, then subtract one day to get the last day of the current month.
+	firstOfNextMonth := time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	return t.Day() == firstOfNextMonth.AddDate(0, 0, -1).Day()
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"teacher_notification_bot/internal/domain/notification"
+)
+
+const (
+	testSpec15th   = "0 10 15 * *"
+	testSpecDaily  = "0 10 * * *"
+	testSpecBroken = "not a spec"
+)
+
+func TestIsLastDayOfMonth(t *testing.T) {
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{date: "2024-01-31", want: true},
+		{date: "2024-01-30", want: false},
+		{date: "2024-02-29", want: true},
+		{date: "2023-02-28", want: true},
+		{date: "2024-02-28", want: false},
+		{date: "2024-04-30", want: true},
+		{date: "2024-12-31", want: true},
+		{date: "2024-05-15", want: false},
+	}
+	for _, tt := range tests {
+		day, err := time.Parse("2006-01-02", tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := isLastDayOfMonth(day); got != tt.want {
+			t.Errorf("isLastDayOfMonth(%s) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestUpcomingCycleStarts(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want []CycleStart
+	}{
+		{
+			name: "before mid-month",
+			now:  at(time.February, 1, 12),
+			want: []CycleStart{
+				{At: at(time.February, 15, 10), Type: notification.CycleTypeMidMonth},
+				{At: at(time.February, 29, 10), Type: notification.CycleTypeEndMonth},
+				{At: at(time.March, 15, 10), Type: notification.CycleTypeMidMonth},
+			},
+		},
+		{
+			name: "after mid-month run",
+			now:  at(time.April, 15, 11),
+			want: []CycleStart{
+				{At: at(time.April, 30, 10), Type: notification.CycleTypeEndMonth},
+				{At: at(time.May, 15, 10), Type: notification.CycleTypeMidMonth},
+				{At: at(time.May, 31, 10), Type: notification.CycleTypeEndMonth},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpcomingCycleStarts(testSpec15th, testSpecDaily, tt.now, len(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpcomingCycleStarts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := UpcomingCycleStarts(testSpecBroken, testSpecDaily, at(time.May, 1, 0), 1); err == nil {
+		t.Error("expected an error for an invalid mid-month spec")
+	}
+	if _, err := UpcomingCycleStarts(testSpec15th, testSpecBroken, at(time.May, 1, 0), 1); err == nil {
+		t.Error("expected an error for an invalid last-day spec")
+	}
+}
+
+func TestCycleTypeOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec15th string
+		day      time.Time
+		want     notification.CycleType
+		wantOK   bool
+	}{
+		{name: "15th", spec15th: testSpec15th, day: time.Date(2024, 5, 15, 18, 0, 0, 0, time.Local), want: notification.CycleTypeMidMonth, wantOK: true},
+		{name: "month end", spec15th: testSpec15th, day: time.Date(2024, 2, 29, 18, 0, 0, 0, time.Local), want: notification.CycleTypeEndMonth, wantOK: true},
+		{name: "ordinary day", spec15th: testSpec15th, day: time.Date(2024, 5, 16, 18, 0, 0, 0, time.Local), wantOK: false},
+		{name: "28th is not a month end", spec15th: testSpec15th, day: time.Date(2024, 2, 28, 9, 0, 0, 0, time.Local), wantOK: false},
+		{name: "mid-month spec moved to the 14th", spec15th: "0 10 14 * *", day: time.Date(2024, 5, 14, 0, 0, 0, 0, time.Local), want: notification.CycleTypeMidMonth, wantOK: true},
+		{name: "15th without a run", spec15th: "0 10 14 * *", day: time.Date(2024, 5, 15, 0, 0, 0, 0, time.Local), wantOK: false},
+		{name: "run at midnight counts", spec15th: "0 0 15 * *", day: time.Date(2024, 5, 15, 23, 0, 0, 0, time.Local), want: notification.CycleTypeMidMonth, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := cycleTypeOn(tt.spec15th, testSpecDaily, tt.day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("cycleTypeOn(%s) = %q, %v; want %q, %v", tt.day.Format("2006-01-02"), got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, _, err := cycleTypeOn(testSpecBroken, testSpecDaily, time.Now()); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+}
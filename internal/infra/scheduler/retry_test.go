@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+	"testing"
+	"time"
+
+	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"gopkg.in/telebot.v3"
+)
+
+// noCycleRepo reports that no cycle exists yet.
+type noCycleRepo struct {
+	notification.Repository
+}
+
+func (noCycleRepo) GetCycleByDateAndType(context.Context, time.Time, notification.CycleType, int) (*notification.Cycle, error) {
+	return nil, idb.ErrCycleNotFound
+}
+
+// outageService fails the first initiation with a connection error and records every call on calls.
+type outageService struct {
+	app.NotificationService
+	mu    sync.Mutex
+	count int
+	calls chan int
+}
+
+func (s *outageService) InitiateNotificationProcess(context.Context, notification.CycleType, time.Time, int) (*app.InitiationResult, error) {
+	s.mu.Lock()
+	s.count++
+	n := s.count
+	s.mu.Unlock()
+	s.calls <- n
+	if n == 1 {
+		return nil, driver.ErrBadConn
+	}
+	return &app.InitiationResult{}, nil
+}
+
+type discardClient struct{}
+
+func (discardClient) SendMessage(int64, string, *telebot.SendOptions) error     { return nil }
+func (discardClient) SendLongMessage(int64, string, *telebot.SendOptions) error { return nil }
+func (discardClient) SendDocument(int64, string, string, string) error          { return nil }
+
+const testRetryDelay = 50 * time.Millisecond
+
+var testCycleDay = time.Date(2024, 5, 15, 0, 0, 0, 0, time.Local)
+
+func newOutageScheduler() (*NotificationScheduler, *outageService) {
+	svc := &outageService{calls: make(chan int, 4)}
+	s := newQuietScheduler()
+	s.notifService = svc
+	s.notifRepo = noCycleRepo{}
+	s.WithFailureAlerts(discardClient{}, 1, testRetryDelay, 3)
+	return s, svc
+}
+
+func TestPendingRetrySurvivesReload(t *testing.T) {
+	s, svc := newOutageScheduler()
+	s.Start()
+	defer s.Stop()
+
+	s.runNotificationProcess(s.log, notification.CycleTypeMidMonth, testCycleDay, 0)
+	<-svc.calls // The failed first attempt scheduled a retry
+	if err := s.Reload(quietConfig()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	select {
+	case n := <-svc.calls:
+		if n != 2 {
+			t.Fatalf("call %d after Reload, want the retry (call 2)", n)
+		}
+	case <-time.After(20 * testRetryDelay):
+		t.Fatal("the pending retry was dropped by Reload")
+	}
+}
+
+func TestStopDropsPendingRetry(t *testing.T) {
+	s, svc := newOutageScheduler()
+	s.Start()
+
+	s.runNotificationProcess(s.log, notification.CycleTypeMidMonth, testCycleDay, 0)
+	<-svc.calls
+	s.Stop()
+
+	select {
+	case <-svc.calls:
+		t.Fatal("a retry ran after Stop")
+	case <-time.After(5 * testRetryDelay):
+	}
+}
@@ -2,13 +2,18 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"teacher_notification_bot/internal/app" // For NotificationService interface
 	"teacher_notification_bot/internal/domain/notification"
-	idb "teacher_notification_bot/internal/infra/database" // For ErrCycleNotFound
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+	"teacher_notification_bot/internal/infra/config"
+	idb "teacher_notification_bot/internal/infra/database" // For ErrCycleNotFound and IsConnectionError
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
 )
 
 type NotificationScheduler struct {
@@ -20,6 +25,23 @@ type NotificationScheduler struct {
 	cronSpecLastDay       string // This will run daily, logic inside checks if it's the last day
 	cronSpecReminderCheck string
 	cronSpecNextDayCheck  string
+	cronSpecPrenotify     string // Daily check for the eve-of-cycle notice; empty disables it (see WithPrenotify)
+	// Separate next-day check for end-of-month cycles; empty sweeps every cycle type at cronSpecNextDayCheck
+	// (see WithEndMonthNextDayCheck)
+	cronSpecNextDayCheckEndMonth string
+
+	// Failure alerts (see WithFailureAlerts); alertClient is nil when disabled
+	alertClient     domainTelegram.Client
+	adminTelegramID int64
+	retryDelay      time.Duration
+	maxRetries      int
+	stopCh          chan struct{} // Closed by Stop so pending retries are dropped; Reload leaves it open
+	stopMu          sync.Mutex    // Guards stopCh; separate from mu because running jobs read it while Stop waits for them
+
+	mu      sync.Mutex // Guards started, cronEngine and the cron specs across Start/Stop/Reload
+	started bool
+
+	heartbeat heartbeat // Latest run of the every-minute heartbeat job (see runHeartbeat)
 }
 
 func NewNotificationScheduler(
@@ -32,7 +54,6 @@ func NewNotificationScheduler(
 	cronSpecNextDayCheck string, // e.g., "0 11 * * *" (11:00 AM daily)
 ) *NotificationScheduler {
 	return &NotificationScheduler{
-		cronEngine:            cron.New(cron.WithLocation(time.Local)), // Use server's local time for cron
 		notifService:          notifService,
 		notifRepo:             notifRepo,
 		log:                   baseLogger,
@@ -43,8 +64,87 @@ func NewNotificationScheduler(
 	}
 }
 
+// WithFailureAlerts makes cycle initiation jobs that fail because the database is unreachable message the admin
+// (Telegram doesn't need the database) and re-attempt the job after retryDelay, up to maxRetries times.
+func (s *NotificationScheduler) WithFailureAlerts(client domainTelegram.Client, adminTelegramID int64, retryDelay time.Duration, maxRetries int) *NotificationScheduler {
+	s.alertClient = client
+	s.adminTelegramID = adminTelegramID
+	s.retryDelay = retryDelay
+	s.maxRetries = maxRetries
+	return s
+}
+
+// WithEndMonthNextDayCheck gives end-of-month cycles, whose reports are time-critical, their own next-day reminder
+// sweep (usually earlier in the morning); the regular sweep then only covers mid-month cycles. An empty spec
+// keeps a single sweep for both.
+func (s *NotificationScheduler) WithEndMonthNextDayCheck(cronSpec string) *NotificationScheduler {
+	s.cronSpecNextDayCheckEndMonth = cronSpec
+	return s
+}
+
+// WithPrenotify adds a daily job that, when tomorrow is a cycle day, tells teachers the cycle is coming.
+// An empty spec disables it.
+func (s *NotificationScheduler) WithPrenotify(cronSpec string) *NotificationScheduler {
+	s.cronSpecPrenotify = cronSpec
+	return s
+}
+
+// Start registers the cron jobs and starts the engine. Calling it on a running scheduler is a no-op,
+// so jobs are never registered twice.
 func (s *NotificationScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		s.log.Warn("Notification scheduler is already running. Ignoring Start.")
+		return
+	}
+	if err := s.start(); err != nil {
+		s.log.WithError(err).Fatal("Could not start notification scheduler")
+	}
+}
+
+// Reload stops the scheduler and starts it again with the cron specs from cfg. The specs are validated
+// first, so an invalid spec leaves the scheduler running on the old ones. Initiation retries pending after a
+// database outage survive the reload. Like Stop, it waits for running jobs, so a reload issued while a cycle
+// is being initiated returns only once that initiation has finished.
+func (s *NotificationScheduler) Reload(cfg *config.AppConfig) error {
+	prenotifySpec := ""
+	if cfg.PrenotifyEnabled {
+		prenotifySpec = cfg.CronSpecPrenotify
+	}
+	for _, spec := range []string{cfg.CronSpec15th, cfg.CronSpecDailyCheckForLastDay, cfg.CronSpecReminderCheck, cfg.CronSpecNextDayCheck, cfg.CronSpecNextDayCheckEndMonth, prenotifySpec} {
+		if spec == "" {
+			continue
+		}
+		if _, err := cron.ParseStandard(spec); err != nil {
+			return fmt.Errorf("invalid cron spec %q: %w", spec, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log.Info("Reloading notification scheduler...")
+	if s.started {
+		s.stopEngine()
+	}
+	s.cronSpec15th = cfg.CronSpec15th
+	s.cronSpecLastDay = cfg.CronSpecDailyCheckForLastDay
+	s.cronSpecReminderCheck = cfg.CronSpecReminderCheck
+	s.cronSpecNextDayCheck = cfg.CronSpecNextDayCheck
+	s.cronSpecNextDayCheckEndMonth = cfg.CronSpecNextDayCheckEndMonth
+	s.cronSpecPrenotify = prenotifySpec
+	return s.start()
+}
+
+// start builds a fresh cron engine with all jobs and starts it. The caller must hold s.mu.
+func (s *NotificationScheduler) start() error {
 	s.log.Info("Starting notification scheduler...")
+	s.cronEngine = cron.New(cron.WithLocation(time.Local)) // Use server's local time for cron
+	s.stopMu.Lock()
+	if s.stopCh == nil {
+		s.stopCh = make(chan struct{})
+	}
+	s.stopMu.Unlock()
 
 	// Job for the 15th of the month
 	_, err := s.cronEngine.AddFunc(s.cronSpec15th, func() {
@@ -53,7 +153,7 @@ func (s *NotificationScheduler) Start() {
 		s.executeNotificationProcess(jobLog, notification.CycleTypeMidMonth)
 	})
 	if err != nil {
-		s.log.WithError(err).Fatal("Could not add 15th of month cron job")
+		return fmt.Errorf("could not add 15th of month cron job: %w", err)
 	}
 
 	// Job that runs daily but only proceeds if it's the last day of the month
@@ -61,19 +161,15 @@ func (s *NotificationScheduler) Start() {
 		jobLog := s.log.WithField("job_name", "last_day_of_month_check")
 		jobLog.Info("Daily cron job triggered for last day check")
 		now := time.Now()
-		// Calculate the first day of the next month, then subtract one day to get the last day of the current month.
-		firstOfNextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
-		lastDayOfCurrentMonth := firstOfNextMonth.AddDate(0, 0, -1)
-
-		if now.Day() == lastDayOfCurrentMonth.Day() {
+		if isLastDayOfMonth(now) {
 			jobLog.Info("Today is the last day of the month. Executing end-of-month notification process.")
 			s.executeNotificationProcess(jobLog, notification.CycleTypeEndMonth)
 		} else {
-			jobLog.WithFields(logrus.Fields{"current_day": now.Day(), "last_day_of_month": lastDayOfCurrentMonth.Day()}).Info("Today is not the last day of the month. Skipping end-of-month process.")
+			jobLog.WithField("current_day", now.Day()).Info("Today is not the last day of the month. Skipping end-of-month process.")
 		}
 	})
 	if err != nil {
-		s.log.WithError(err).Fatal("Could not add last day of month cron job")
+		return fmt.Errorf("could not add last day of month cron job: %w", err)
 	}
 
 	// Job for processing 1-hour reminders
@@ -87,38 +183,93 @@ func (s *NotificationScheduler) Start() {
 		}
 	})
 	if err != nil {
-		s.log.WithError(err).Fatal("Could not add 1-hour reminder processing cron job")
+		return fmt.Errorf("could not add 1-hour reminder processing cron job: %w", err)
 	}
 
-	// Job for processing next-day reminders
-	_, err = s.cronEngine.AddFunc(s.cronSpecNextDayCheck, func() {
-		jobLog := s.log.WithField("job_name", "next_day_reminder_processing")
-		jobLog.Info("Cron job triggered")
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // Longer timeout for potentially more items
-		defer cancel()
-		if err := s.notifService.ProcessNextDayReminders(ctx); err != nil {
-			jobLog.WithError(err).Error("Error during next-day reminder processing")
+	// Job for processing next-day reminders; end-of-month cycles may have their own, earlier one
+	var nextDayCycleTypes []notification.CycleType
+	if s.cronSpecNextDayCheckEndMonth != "" {
+		nextDayCycleTypes = []notification.CycleType{notification.CycleTypeMidMonth}
+		_, err = s.cronEngine.AddFunc(s.cronSpecNextDayCheckEndMonth, func() {
+			s.runNextDayReminders("next_day_reminder_processing_end_month", notification.CycleTypeEndMonth)
+		})
+		if err != nil {
+			return fmt.Errorf("could not add end-of-month next-day reminder processing cron job: %w", err)
 		}
+	}
+	_, err = s.cronEngine.AddFunc(s.cronSpecNextDayCheck, func() {
+		s.runNextDayReminders("next_day_reminder_processing", nextDayCycleTypes...)
 	})
 	if err != nil {
-		s.log.WithError(err).Fatal("Could not add next-day reminder processing cron job")
+		return fmt.Errorf("could not add next-day reminder processing cron job: %w", err)
+	}
+
+	// Optional job telling teachers the evening before that a cycle starts tomorrow
+	if s.cronSpecPrenotify != "" {
+		cronSpec15th, cronSpecLastDay := s.cronSpec15th, s.cronSpecLastDay // The job must not read specs a Reload may be replacing
+		_, err = s.cronEngine.AddFunc(s.cronSpecPrenotify, func() {
+			jobLog := s.log.WithField("job_name", "cycle_eve_notice")
+			cycleType, ok, err := cycleTypeOn(cronSpec15th, cronSpecLastDay, time.Now().AddDate(0, 0, 1))
+			if err != nil {
+				jobLog.WithError(err).Error("Could not work out tomorrow's cycle from the cron specs")
+				return
+			}
+			if !ok {
+				jobLog.Debug("Tomorrow is not a cycle day. Skipping eve notice.")
+				return
+			}
+			jobLog = jobLog.WithField("cycle_type", cycleType)
+			jobLog.Info("Tomorrow is a cycle day. Sending eve notice.")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			if _, err := s.notifService.SendCycleEveNotice(ctx, cycleType); err != nil {
+				jobLog.WithError(err).Error("Error during cycle eve notice")
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("could not add cycle eve notice cron job: %w", err)
+		}
+	}
+
+	// Heartbeat to detect clock skew and a stalled scheduler
+	if _, err = s.cronEngine.AddFunc(heartbeatSpec, s.runHeartbeat); err != nil {
+		return fmt.Errorf("could not add heartbeat cron job: %w", err)
 	}
 
 	s.cronEngine.Start()
+	s.started = true
 	s.log.Info("Notification scheduler started with jobs.")
+	return nil
+}
+
+// runNextDayReminders runs the next-day reminder sweep for the given cycle types (all when none are given).
+func (s *NotificationScheduler) runNextDayReminders(jobName string, cycleTypes ...notification.CycleType) {
+	jobLog := s.log.WithFields(logrus.Fields{"job_name": jobName, "cycle_types": cycleTypes})
+	jobLog.Info("Cron job triggered")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // Longer timeout for potentially more items
+	defer cancel()
+	if err := s.notifService.ProcessNextDayReminders(ctx, cycleTypes...); err != nil {
+		jobLog.WithError(err).Error("Error during next-day reminder processing")
+	}
 }
 
 // executeNotificationProcess is a helper to handle the common logic for both job types
 func (s *NotificationScheduler) executeNotificationProcess(jobLog *logrus.Entry, cycleType notification.CycleType) {
-	ctx := context.Background() // Or a more specific context if available
 	today := time.Now()
 	// Normalize to just the date part for cycleDate consistency
 	cycleDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
-	logCtx := jobLog.WithFields(logrus.Fields{"cycle_type": cycleType, "cycle_date": cycleDate.Format("2006-01-02")})
+	s.runNotificationProcess(jobLog, cycleType, cycleDate, 0)
+}
 
-	existingCycle, err := s.notifRepo.GetCycleByDateAndType(ctx, cycleDate, cycleType)
+// runNotificationProcess initiates the cycle. attempt counts retries after database outages (0 for the cron run).
+func (s *NotificationScheduler) runNotificationProcess(jobLog *logrus.Entry, cycleType notification.CycleType, cycleDate time.Time, attempt int) {
+	ctx := context.Background() // Or a more specific context if available
+	logCtx := jobLog.WithFields(logrus.Fields{"cycle_type": cycleType, "cycle_date": cycleDate.Format("2006-01-02"), "attempt": attempt})
+
+	existingCycle, err := s.notifRepo.GetCycleByDateAndType(ctx, cycleDate, cycleType, notification.FirstRound)
 	if err != nil && err != idb.ErrCycleNotFound {
 		logCtx.WithError(err).Error("Failed to check for existing cycle before initiating process")
+		s.handleInitiationFailure(logCtx, jobLog, cycleType, cycleDate, attempt, err)
 		return
 	}
 	if existingCycle != nil {
@@ -127,16 +278,94 @@ func (s *NotificationScheduler) executeNotificationProcess(jobLog *logrus.Entry,
 		logCtx.Info("No existing cycle found. A new cycle will be created by InitiateNotificationProcess.")
 	}
 
-	if err := s.notifService.InitiateNotificationProcess(ctx, cycleType, cycleDate); err != nil {
+	result, err := s.notifService.InitiateNotificationProcess(ctx, cycleType, cycleDate, notification.FirstRound)
+	if err == app.ErrInitiationInProgress {
+		logCtx.Info("Another initiation of this cycle is already sending. Skipping.")
+		return
+	}
+	if err != nil {
 		logCtx.WithError(err).Error("Error during notification process initiation")
-	} else {
-		logCtx.Info("Notification process initiated successfully.")
+		s.handleInitiationFailure(logCtx, jobLog, cycleType, cycleDate, attempt, err)
+		return
 	}
+
+	logCtx = logCtx.WithFields(logrus.Fields{
+		"cycle_id":     result.CycleID,
+		"sent_count":   len(result.Sent),
+		"failed_count": len(result.Failed),
+	})
+	if len(result.Failed) > 0 {
+		// Failed sends stay undelivered in the DB and can be re-sent with /retry_failed <cycleID>.
+		for _, failure := range result.Failed {
+			logCtx.WithError(failure.Err).WithField("teacher_id", failure.TeacherID).Warn("Initial notification was not delivered")
+		}
+		logCtx.Warn("Notification process initiated with failed sends. Use /retry_failed to re-send.")
+		return
+	}
+	if attempt > 0 {
+		s.alertAdmin(logCtx, fmt.Sprintf("Рассылка (%s, %s) успешно запущена с попытки %d.", cycleType.DisplayName(), notification.FormatDate(cycleDate), attempt+1))
+	}
+	logCtx.Info("Notification process initiated successfully.")
 }
 
+// handleInitiationFailure alerts the admin and schedules a retry when initiation failed because the database
+// was unreachable. Logical errors are only logged: retrying them would fail the same way.
+func (s *NotificationScheduler) handleInitiationFailure(logCtx, jobLog *logrus.Entry, cycleType notification.CycleType, cycleDate time.Time, attempt int, err error) {
+	if s.alertClient == nil || !idb.IsConnectionError(err) {
+		return
+	}
+	cycleLabel := fmt.Sprintf("%s, %s", cycleType.DisplayName(), notification.FormatDate(cycleDate))
+	if attempt >= s.maxRetries {
+		logCtx.Error("Database still unreachable; giving up on scheduled initiation")
+		s.alertAdmin(logCtx, fmt.Sprintf("Рассылка (%s) не запущена: база данных недоступна (%s). Повторные попытки исчерпаны, запустите её вручную командой /run_cycle.", cycleLabel, err.Error()))
+		return
+	}
+
+	logCtx.WithField("retry_in", s.retryDelay.String()).Warn("Database unreachable; scheduled initiation will be retried")
+	s.alertAdmin(logCtx, fmt.Sprintf("Рассылка (%s) не запущена: база данных недоступна (%s). Повторная попытка через %s.", cycleLabel, err.Error(), s.retryDelay))
+	s.stopMu.Lock()
+	stopCh := s.stopCh
+	s.stopMu.Unlock()
+	time.AfterFunc(s.retryDelay, func() {
+		select {
+		case <-stopCh:
+			return // Shutting down
+		default:
+		}
+		s.runNotificationProcess(jobLog, cycleType, cycleDate, attempt+1)
+	})
+}
+
+// alertAdmin sends an operational alert to the admin. Failures are only logged.
+func (s *NotificationScheduler) alertAdmin(logCtx *logrus.Entry, text string) {
+	if s.alertClient == nil || s.adminTelegramID == 0 {
+		return
+	}
+	if err := s.alertClient.SendMessage(s.adminTelegramID, text, &telebot.SendOptions{}); err != nil {
+		logCtx.WithError(err).Error("Failed to send alert to admin")
+	}
+}
+
+// Stop stops a running scheduler and drops pending initiation retries; it can be started again afterwards.
 func (s *NotificationScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started {
+		return
+	}
+	s.stopMu.Lock()
+	close(s.stopCh) // Drop pending initiation retries
+	s.stopCh = nil
+	s.stopMu.Unlock()
+	s.stopEngine()
+}
+
+// stopEngine halts the cron engine and waits for running jobs. The caller must hold s.mu.
+func (s *NotificationScheduler) stopEngine() {
 	s.log.Info("Stopping notification scheduler...")
 	ctx := s.cronEngine.Stop() // Stops the scheduler from adding new jobs, waits for running jobs.
 	<-ctx.Done()               // Wait for graceful shutdown
+	s.heartbeat.pause()
+	s.started = false
 	s.log.Info("Notification scheduler gracefully stopped.")
 }
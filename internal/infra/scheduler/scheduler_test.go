@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"teacher_notification_bot/internal/infra/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Specs that never come due while a test runs, so no job touches the nil service.
+const (
+	quietSpec15th    = "0 3 15 1 *"
+	quietSpecLastDay = "0 3 31 1 *"
+	quietSpecRemind  = "0 4 31 1 *"
+	quietSpecNextDay = "0 5 31 1 *"
+)
+
+// baseJobCount is the number of jobs without the optional ones: the four above plus the heartbeat.
+const baseJobCount = 5
+
+func newQuietScheduler() *NotificationScheduler {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewNotificationScheduler(nil, nil, logrus.NewEntry(logger), quietSpec15th, quietSpecLastDay, quietSpecRemind, quietSpecNextDay)
+}
+
+func quietConfig() *config.AppConfig {
+	return &config.AppConfig{
+		CronSpec15th:                 quietSpec15th,
+		CronSpecDailyCheckForLastDay: quietSpecLastDay,
+		CronSpecReminderCheck:        quietSpecRemind,
+		CronSpecNextDayCheck:         quietSpecNextDay,
+	}
+}
+
+func TestStartTwiceRegistersJobsOnce(t *testing.T) {
+	s := newQuietScheduler()
+	s.Start()
+	defer s.Stop()
+	engine := s.cronEngine
+
+	s.Start()
+
+	if s.cronEngine != engine {
+		t.Fatal("second Start replaced the running cron engine")
+	}
+	if got := len(s.cronEngine.Entries()); got != baseJobCount {
+		t.Errorf("%d jobs registered, want %d", got, baseJobCount)
+	}
+}
+
+func TestStopThenStartAgain(t *testing.T) {
+	s := newQuietScheduler()
+	s.Start()
+	s.Stop()
+	if s.started {
+		t.Fatal("scheduler still marked as started after Stop")
+	}
+	s.Stop() // A second Stop is a no-op
+
+	s.Start()
+	defer s.Stop()
+	if !s.started {
+		t.Fatal("scheduler did not start again after Stop")
+	}
+	if got := len(s.cronEngine.Entries()); got != baseJobCount {
+		t.Errorf("%d jobs registered after restart, want %d", got, baseJobCount)
+	}
+}
+
+func TestReload(t *testing.T) {
+	s := newQuietScheduler()
+	s.Start()
+	defer s.Stop()
+
+	cfg := quietConfig()
+	cfg.CronSpecNextDayCheckEndMonth = "0 6 31 1 *"
+	cfg.PrenotifyEnabled = true
+	cfg.CronSpecPrenotify = "0 7 31 1 *"
+	if err := s.Reload(cfg); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if !s.started {
+		t.Fatal("scheduler not running after Reload")
+	}
+	if got := len(s.cronEngine.Entries()); got != baseJobCount+2 {
+		t.Errorf("%d jobs registered after Reload, want %d", got, baseJobCount+2)
+	}
+
+	engine := s.cronEngine
+	broken := quietConfig()
+	broken.CronSpecReminderCheck = "every five minutes"
+	if err := s.Reload(broken); err == nil {
+		t.Fatal("Reload accepted an invalid spec")
+	}
+	if s.cronEngine != engine || !s.started {
+		t.Error("a rejected Reload stopped or replaced the running scheduler")
+	}
+	if s.cronSpecReminderCheck != quietSpecRemind {
+		t.Errorf("a rejected Reload changed the reminder spec to %q", s.cronSpecReminderCheck)
+	}
+}
+
+func TestReloadStartsStoppedScheduler(t *testing.T) {
+	s := newQuietScheduler()
+	if err := s.Reload(quietConfig()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	defer s.Stop()
+	if !s.started {
+		t.Fatal("Reload did not start the scheduler")
+	}
+}
+
+func TestReloadKeepsLastHeartbeat(t *testing.T) {
+	s := newQuietScheduler()
+	s.Start()
+	defer s.Stop()
+	beatAt := time.Now()
+	s.heartbeat.beat(beatAt)
+
+	if err := s.Reload(quietConfig()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := s.LastHeartbeat(); !got.Equal(beatAt) {
+		t.Errorf("LastHeartbeat() after Reload = %v, want %v", got, beatAt)
+	}
+	if _, ok := s.heartbeat.beat(beatAt.Add(time.Hour)); ok {
+		t.Error("the first beat after Reload measured the pause as a gap")
+	}
+}
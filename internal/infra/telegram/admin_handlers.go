@@ -8,6 +8,7 @@ import (
 	"teacher_notification_bot/internal/app"
 	teacher "teacher_notification_bot/internal/domain/teacher"
 	idb "teacher_notification_bot/internal/infra/database"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/telebot.v3"
@@ -15,7 +16,8 @@ import (
 
 // RegisterAdminHandlers registers handlers for admin commands.
 // It requires the bot instance, admin service, and the configured admin Telegram ID.
-func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *app.AdminService, adminTelegramID int64, baseLogger *logrus.Entry) {
+// With confirmDestructive, /remove_teacher only asks for confirmation (see RegisterRemoveTeacherConfirmation).
+func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *app.AdminService, adminTelegramID int64, confirmDestructive bool, baseLogger *logrus.Entry) {
 	b.Handle("/add_teacher", func(c telebot.Context) error {
 		handlerLogger := baseLogger.WithFields(logrus.Fields{
 			"handler":   "/add_teacher",
@@ -57,6 +59,11 @@ func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *ap
 		})
 
 		newTeacher, err := adminService.AddTeacher(ctx, c.Sender().ID, teacherTelegramID, firstName, lastName)
+		welcomeNotDelivered := err == app.ErrWelcomeMessageNotDelivered
+		if welcomeNotDelivered {
+			handlerLogger.WithError(err).Warn("Teacher added, but welcome message was not delivered")
+			err = nil
+		}
 		if err != nil {
 			logWithError := handlerLogger.WithError(err)
 			switch err {
@@ -66,6 +73,12 @@ func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *ap
 			case app.ErrTeacherAlreadyExists:
 				logWithError.Warn("Teacher already exists")
 				return c.Send(fmt.Sprintf("Ошибка: Преподаватель с Telegram ID %d уже существует.", teacherTelegramID))
+			case app.ErrFirstNameEmpty:
+				logWithError.Warn("Empty first name")
+				return c.Send("Ошибка: Имя не может быть пустым.")
+			case app.ErrNameTooLong:
+				logWithError.Warn("Teacher name is too long")
+				return c.Send("Ошибка: Имя или фамилия слишком длинные.")
 			default:
 				logWithError.Error("Failed to add teacher")
 				return c.Send(fmt.Sprintf("Произошла ошибка при добавлении преподавателя: %s", err.Error()))
@@ -80,6 +93,9 @@ func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *ap
 		if newTeacher.LastName.Valid {
 			successMsg = fmt.Sprintf("Преподаватель %s %s (ID: %d) успешно добавлен.", newTeacher.FirstName, newTeacher.LastName.String, newTeacher.TelegramID)
 		}
+		if welcomeNotDelivered {
+			successMsg += "\nВнимание: не удалось отправить приветственное сообщение. Преподаватель должен сначала открыть бота и нажать /start, иначе он не получит уведомления."
+		}
 		return c.Send(successMsg)
 	})
 
@@ -108,39 +124,12 @@ func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *ap
 		}
 		handlerLogger = handlerLogger.WithField("teacher_telegram_id", teacherTelegramID)
 
-		removedTeacher, err := adminService.RemoveTeacher(ctx, c.Sender().ID, teacherTelegramID)
-		if err != nil {
-			logWithError := handlerLogger.WithError(err)
-			switch err {
-			case app.ErrAdminNotAuthorized: // Redundant here
-				logWithError.Warn("Admin not authorized (service level)")
-				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
-			case idb.ErrTeacherNotFound:
-				logWithError.Warn("Teacher to remove not found")
-				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
-			case app.ErrTeacherAlreadyInactive:
-				logWithError.Warn("Teacher already inactive")
-				if removedTeacher != nil {
-					return c.Send(fmt.Sprintf("Преподаватель %s %s (ID: %d) уже был деактивирован.", removedTeacher.FirstName, removedTeacher.LastName.String, removedTeacher.TelegramID))
-				}
-				return c.Send(fmt.Sprintf("Преподаватель с Telegram ID %d уже был деактивирован.", teacherTelegramID))
-			default:
-				logWithError.Error("Failed to remove teacher")
-				return c.Send(fmt.Sprintf("Произошла ошибка при удалении преподавателя: %s", err.Error()))
-			}
-		}
-
-		handlerLogger.WithFields(logrus.Fields{
-			"removed_teacher_id": removedTeacher.ID,
-		}).Info("Teacher removed (deactivated) successfully")
-
-		var teacherName strings.Builder
-		teacherName.WriteString(removedTeacher.FirstName)
-		if removedTeacher.LastName.Valid && removedTeacher.LastName.String != "" {
-			teacherName.WriteString(" ")
-			teacherName.WriteString(removedTeacher.LastName.String)
+		if confirmDestructive {
+			handlerLogger.Info("Asking for confirmation before removing teacher")
+			return c.Send(fmt.Sprintf("Деактивировать преподавателя с Telegram ID %d?", teacherTelegramID),
+				&telebot.SendOptions{ReplyMarkup: removeConfirmationMarkup(teacherTelegramID)})
 		}
-		return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d) успешно деактивирован.", teacherName.String(), removedTeacher.TelegramID))
+		return c.Send(removeTeacher(ctx, adminService, c.Sender().ID, teacherTelegramID, handlerLogger))
 	})
 
 	b.Handle("/list_teachers", func(c telebot.Context) error {
@@ -161,21 +150,17 @@ func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *ap
 		}
 		handlerLogger = handlerLogger.WithField("list_type", listType)
 
-		var teachersList []*teacher.Teacher
-		var err error
-		var title string
-
+		var list string
 		switch listType {
 		case "active":
-			title = "Активные преподаватели"
-			teachersList, err = adminService.ListActiveTeachers(ctx, c.Sender().ID)
+			list = pagedListActiveTeachers
 		case "all":
-			title = "Все преподаватели"
-			teachersList, err = adminService.ListAllTeachers(ctx, c.Sender().ID)
+			list = pagedListAllTeachers
 		default:
 			handlerLogger.Warn("Invalid list type argument")
 			return c.Send("Неверный аргумент. Используйте 'active' или 'all', или оставьте пустым для отображения активных преподавателей.")
 		}
+		teachersList, err := loadTeacherList(ctx, adminService, c.Sender().ID, list)
 
 		if err != nil {
 			logWithError := handlerLogger.WithError(err)
@@ -197,20 +182,407 @@ func RegisterAdminHandlers(ctx context.Context, b *telebot.Bot, adminService *ap
 
 		handlerLogger.WithField("teachers_count", len(teachersList)).Info("Successfully retrieved teacher list")
 
+		// Long lists are paged; the buttons are handled by RegisterTeacherListPaging.
+		text, markup := renderTeacherListPage(list, teachersList, 1)
+		return c.Send(text, &telebot.SendOptions{ReplyMarkup: markup})
+	})
+
+	b.Handle("/set_manager_notify", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/set_manager_notify",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /set_manager_notify <TelegramID> <on|off>
+		if len(args) != 2 {
+			return c.Send("Неверный формат команды. Используйте: /set_manager_notify <TelegramID> <on|off>")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+
+		var enabled bool
+		switch strings.ToLower(args[1]) {
+		case "on":
+			enabled = true
+		case "off":
+			enabled = false
+		default:
+			return c.Send("Ошибка: второй аргумент должен быть 'on' или 'off'.")
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{
+			"teacher_telegram_id": teacherTelegramID,
+			"notify_manager":      enabled,
+		})
+
+		updatedTeacher, err := adminService.SetManagerNotify(ctx, c.Sender().ID, teacherTelegramID, enabled)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to change manager notification flag")
+				return c.Send(fmt.Sprintf("Произошла ошибка при изменении настройки: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Manager notification flag changed successfully")
+		if updatedTeacher.NotifyManagerOnComplete {
+			return c.Send(fmt.Sprintf("Менеджер будет получать подтверждения по преподавателю %s (ID: %d).", updatedTeacher.FirstName, updatedTeacher.TelegramID))
+		}
+		return c.Send(fmt.Sprintf("Менеджер больше не будет получать подтверждения по преподавателю %s (ID: %d).", updatedTeacher.FirstName, updatedTeacher.TelegramID))
+	})
+
+	b.Handle("/set_workdays", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/set_workdays",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /set_workdays <TelegramID> <Mon,Wed,Fri|all>
+		if len(args) != 2 {
+			return c.Send("Неверный формат команды. Используйте: /set_workdays <TelegramID> <Mon,Wed,Fri|all>")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		workDays, err := teacher.ParseWorkDays(args[1])
+		if err != nil {
+			handlerLogger.WithError(err).WithField("arg", args[1]).Warn("Invalid work days format")
+			return c.Send("Ошибка: укажите дни через запятую, например Mon,Wed,Fri (или пн,ср,пт), либо 'all' для всех дней.")
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{
+			"teacher_telegram_id": teacherTelegramID,
+			"work_days":           workDays.String(),
+		})
+
+		updatedTeacher, err := adminService.SetWorkDays(ctx, c.Sender().ID, teacherTelegramID, workDays)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to change work days")
+				return c.Send(fmt.Sprintf("Произошла ошибка при изменении рабочих дней: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Work days changed successfully")
+		return c.Send(fmt.Sprintf("Рабочие дни преподавателя %s (ID: %d): %s.", updatedTeacher.FirstName, updatedTeacher.TelegramID, updatedTeacher.WorkDays.String()))
+	})
+
+	b.Handle("/set_reminder_delay", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/set_reminder_delay",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /set_reminder_delay <TelegramID> <duration>
+		if len(args) != 2 {
+			return c.Send("Неверный формат команды. Используйте: /set_reminder_delay <TelegramID> <длительность>")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		delay, err := time.ParseDuration(args[1])
+		if err != nil {
+			handlerLogger.WithField("arg", args[1]).Warn("Invalid duration format")
+			return c.Send("Ошибка: неверный формат длительности. Примеры: 30m, 3h, 24h.")
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"teacher_telegram_id": teacherTelegramID, "delay": delay.String()})
+
+		updatedTeacher, err := adminService.SetReminderDelay(ctx, c.Sender().ID, teacherTelegramID, &delay)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case app.ErrInvalidReminderDelay:
+				logWithError.Warn("Invalid reminder delay")
+				return c.Send("Ошибка: задержка должна быть не меньше минуты и не больше 7 дней.")
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to change reminder delay")
+				return c.Send(fmt.Sprintf("Произошла ошибка при изменении задержки напоминания: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Reminder delay changed successfully")
+		return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d) получит напоминание через %s после ответа 'Нет'.", updatedTeacher.FirstName, updatedTeacher.TelegramID, delay.String()))
+	})
+
+	b.Handle("/set_display_name", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/set_display_name",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /set_display_name <TelegramID> <name...>; "-" clears the display name
+		if len(args) < 2 {
+			return c.Send("Неверный формат команды. Используйте: /set_display_name <TelegramID> <Имя для обращения|->")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		displayName := strings.Join(args[1:], " ")
+		if displayName == "-" {
+			displayName = ""
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"teacher_telegram_id": teacherTelegramID, "display_name": displayName})
+
+		updatedTeacher, err := adminService.SetDisplayName(ctx, c.Sender().ID, teacherTelegramID, displayName)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case app.ErrNameTooLong:
+				logWithError.Warn("Display name too long")
+				return c.Send("Ошибка: имя для обращения слишком длинное.")
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to change display name")
+				return c.Send(fmt.Sprintf("Произошла ошибка при изменении имени для обращения: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Display name changed successfully")
+		if !updatedTeacher.DisplayName.Valid {
+			return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d): имя для обращения сброшено, используется имя из записи.", updatedTeacher.FullName(), updatedTeacher.TelegramID))
+		}
+		return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d) теперь получает сообщения с обращением «%s».", updatedTeacher.FullName(), updatedTeacher.TelegramID, updatedTeacher.DisplayName.String))
+	})
+
+	b.Handle("/clear_reminder_delay", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/clear_reminder_delay",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /clear_reminder_delay <TelegramID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /clear_reminder_delay <TelegramID>")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("teacher_telegram_id", teacherTelegramID)
+
+		updatedTeacher, err := adminService.SetReminderDelay(ctx, c.Sender().ID, teacherTelegramID, nil)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to clear reminder delay")
+				return c.Send(fmt.Sprintf("Произошла ошибка при изменении задержки напоминания: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Reminder delay cleared successfully")
+		return c.Send(fmt.Sprintf("Для преподавателя %s (ID: %d) снова действует общая задержка напоминания.", updatedTeacher.FirstName, updatedTeacher.TelegramID))
+	})
+
+	b.Handle("/export_teachers", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/export_teachers",
+			"sender_id": c.Sender().ID,
+		})
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Optional argument: 'active' or 'all'
+		listType := "active"
+		if len(args) > 0 {
+			listType = strings.ToLower(args[0])
+		}
+		handlerLogger = handlerLogger.WithField("list_type", listType)
+
+		var includeInactive bool
+		switch listType {
+		case "active":
+			includeInactive = false
+		case "all":
+			includeInactive = true
+		default:
+			handlerLogger.Warn("Invalid list type argument")
+			return c.Send("Неверный аргумент. Используйте 'active' или 'all', или оставьте пустым для экспорта активных преподавателей.")
+		}
+
+		count, err := adminService.ExportTeachers(ctx, c.Sender().ID, includeInactive)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case app.ErrNoTeachersToExport:
+				logWithError.Info("No teachers to export")
+				return c.Send("Нет преподавателей для экспорта.")
+			default:
+				logWithError.Error("Failed to export teachers")
+				return c.Send(fmt.Sprintf("Произошла ошибка при экспорте преподавателей: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.WithField("teachers_count", count).Info("Teachers exported successfully")
+		return nil
+	})
+
+	b.Handle("/import_teachers", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/import_teachers",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		// Expected format: /import_teachers [atomic] on the first line, then one "<TelegramID> <Имя> [Фамилия]" per line
+		const usage = "Неверный формат команды. Используйте: /import_teachers [atomic], а со следующей строки - по одному преподавателю: <TelegramID> <Имя> [Фамилия]"
+		lines := strings.Split(c.Message().Text, "\n")
+		commandArgs := strings.Fields(lines[0])[1:]
+		atomic := false
+		if len(commandArgs) == 1 && commandArgs[0] == "atomic" {
+			atomic = true
+		} else if len(commandArgs) != 0 {
+			return c.Send(usage)
+		}
+
+		var rows []app.TeacherImportRow
+		for i, line := range lines[1:] {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) < 2 || len(fields) > 3 {
+				return c.Send(fmt.Sprintf("Ошибка в строке %d: ожидается <TelegramID> <Имя> [Фамилия].", i+1))
+			}
+			telegramID, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return c.Send(fmt.Sprintf("Ошибка в строке %d: Telegram ID должен быть числом.", i+1))
+			}
+			row := app.TeacherImportRow{TelegramID: telegramID, FirstName: fields[1]}
+			if len(fields) == 3 {
+				row.LastName = fields[2]
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == 0 {
+			return c.Send(usage)
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"rows_count": len(rows), "atomic": atomic})
+
+		result, err := adminService.ImportTeachers(ctx, c.Sender().ID, rows, atomic)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case app.ErrTooManyImportRows:
+				logWithError.Warn("Too many rows to import")
+				return c.Send(fmt.Sprintf("Ошибка: за один раз можно импортировать не более %d преподавателей.", app.MaxTeacherImportRows))
+			default:
+				logWithError.Error("Failed to import teachers")
+				return c.Send(fmt.Sprintf("Произошла ошибка при импорте преподавателей: %s", err.Error()))
+			}
+		}
+
+		var created, updated int
 		var response strings.Builder
-		response.WriteString(fmt.Sprintf("---	%s	---\n", title))
-		for _, t := range teachersList {
-			status := "Деактивирован"
-			if t.IsActive {
-				status = "Активен"
+		for _, rowResult := range result.Rows {
+			switch {
+			case rowResult.Err != nil:
+				response.WriteString(fmt.Sprintf("Ошибка для %d (%s): %s\n", rowResult.Row.TelegramID, rowResult.Row.FirstName, rowResult.Err.Error()))
+			case rowResult.Created:
+				created++
+			default:
+				updated++
 			}
-			response.WriteString(fmt.Sprintf("ID: %d, Telegram ID: %d, Имя: %s, Фамилия: %s, Статус: %s\n",
-				t.ID,
-				t.TelegramID,
-				t.FirstName,
-				t.LastName.String,
-				status))
-		}
-		return c.Send(response.String())
+		}
+		if result.RolledBack {
+			handlerLogger.Warn("Teacher import rolled back")
+			return sendLong(c, "Импорт отменён, ничего не сохранено:\n"+response.String())
+		}
+		handlerLogger.WithFields(logrus.Fields{"created": created, "updated": updated}).Info("Teachers imported")
+		return sendLong(c, fmt.Sprintf("Импорт завершён. Добавлено: %d, обновлено: %d, ошибок: %d.\n%s", created, updated, len(result.Rows)-created-updated, response.String()))
 	})
 }
@@ -0,0 +1,23 @@
+// internal/infra/telegram/admin_time.go
+package telegram
+
+import "time"
+
+// adminTimeLayout is a human-readable layout for timestamps shown to the admin.
+const adminTimeLayout = "02.01.2006 15:04 MST"
+
+// adminLocation is the zone admin-facing timestamps are rendered in. Server local time until SetAdminLocation is called.
+var adminLocation = time.Local
+
+// SetAdminLocation sets the zone admin-facing timestamps are rendered in (ADMIN_TIMEZONE). nil keeps server local time.
+// Call it once at startup, before handlers are registered.
+func SetAdminLocation(loc *time.Location) {
+	if loc != nil {
+		adminLocation = loc
+	}
+}
+
+// formatForAdmin renders a timestamp in the admin's zone, e.g. "05.03.2025 14:30 MSK".
+func formatForAdmin(t time.Time) string {
+	return t.In(adminLocation).Format(adminTimeLayout)
+}
@@ -3,8 +3,11 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"teacher_notification_bot/internal/app"
 	"teacher_notification_bot/internal/domain/teacher"
 	"teacher_notification_bot/internal/infra/config"
 	idb "teacher_notification_bot/internal/infra/database" // For ErrTeacherNotFound
@@ -18,6 +21,7 @@ func RegisterBotCommands(
 	b *telebot.Bot,
 	cfg *config.AppConfig, // For AdminTelegramID
 	teacherRepo teacher.Repository,
+	managers app.ManagerIDSource, // Current manager; can change at runtime
 	baseLogger *logrus.Entry, // For contextual logging
 ) {
 	startHelpLogger := baseLogger.WithField("handler_group", "start_help")
@@ -36,9 +40,18 @@ func RegisterBotCommands(
 		// Check if Teacher
 		userAsTeacher, err := teacherRepo.GetByTelegramID(ctx, senderID)
 		if err == nil { // Teacher found
+			if !userAsTeacher.HasStartedBot {
+				// From now on Telegram lets the bot message this teacher.
+				userAsTeacher.HasStartedBot = true
+				if errUpdate := teacherRepo.Update(ctx, userAsTeacher); errUpdate != nil {
+					logCtx.WithError(errUpdate).WithField("teacher_id", userAsTeacher.ID).Error("Failed to mark teacher as having started the bot")
+				} else {
+					logCtx.WithField("teacher_id", userAsTeacher.ID).Info("Teacher marked as having started the bot")
+				}
+			}
 			if userAsTeacher.IsActive {
 				logCtx.WithField("teacher_id", userAsTeacher.ID).Info("User identified as Active Teacher")
-				return c.Send(fmt.Sprintf("Привет, %s! Я бот для напоминаний о заполнении таблиц. Я сообщу вам, когда придет время.", userAsTeacher.FirstName))
+				return c.Send(teacherStartText(userAsTeacher.GreetingName()))
 			}
 			logCtx.WithField("teacher_id", userAsTeacher.ID).Info("User identified as Inactive Teacher")
 			return c.Send("Ваш аккаунт преподавателя неактивен. Пожалуйста, свяжитесь с администратором.")
@@ -65,6 +78,61 @@ func RegisterBotCommands(
 			helpText.WriteString("`/add_teacher <TelegramID> <Имя> [Фамилия]`\n - Добавить нового преподавателя в систему.\n\n")
 			helpText.WriteString("`/remove_teacher <TelegramID>`\n - Деактивировать преподавателя (он перестанет получать уведомления).\n\n")
 			helpText.WriteString("`/list_teachers [active|all]`\n - Показать список преподавателей. По умолчанию показывает активных. 'all' - для всех.\n\n")
+			helpText.WriteString("`/export_teachers [active|all]`\n - Выгрузить список преподавателей в CSV-файл. По умолчанию экспортирует активных.\n\n")
+			helpText.WriteString("`/import_teachers [atomic]`\n - Добавить или обновить преподавателей списком: со следующей строки по одному `<TelegramID> <Имя> [Фамилия]`. С 'atomic' при любой ошибке ничего не сохраняется.\n\n")
+			helpText.WriteString("`/set_manager_notify <TelegramID> <on|off>`\n - Включить или отключить уведомление менеджера о подтверждениях преподавателя.\n\n")
+			helpText.WriteString("`/set_workdays <TelegramID> <Mon,Wed,Fri|all>`\n - Задать дни, в которые преподавателю можно отправлять напоминания.\n\n")
+			helpText.WriteString("`/set_reminder_delay <TelegramID> <длительность>`\n - Задать преподавателю свою задержку напоминания после ответа 'Нет' (например, 30m).\n\n")
+			helpText.WriteString("`/clear_reminder_delay <TelegramID>`\n - Вернуть преподавателю общую задержку напоминания.\n\n")
+			helpText.WriteString("`/set_display_name <TelegramID> <Имя|->`\n - Задать имя, по которому бот обращается к преподавателю ('-' - использовать имя из записи).\n\n")
+			helpText.WriteString("`/remind <TelegramID>`\n - Сразу напомнить преподавателю о текущей неподтверждённой таблице (считается напоминанием).\n\n")
+			helpText.WriteString("`/reliability <TelegramID> [ГГГГ]`\n - Показать долю таблиц, подтверждённых преподавателем без напоминаний.\n\n")
+			helpText.WriteString("`/bulk_confirm <CycleID> <TelegramID,...>`\n - Подтвердить все таблицы цикла за перечисленных преподавателей (например, по итогам собрания).\n\n")
+			helpText.WriteString("`/send_help <TelegramID>`\n - Повторно отправить преподавателю справку (например, если он удалил чат).\n\n")
+			helpText.WriteString("`/as_teacher`\n - Показать, что видит преподаватель в ответ на /start и /help.\n\n")
+			helpText.WriteString("`/set_manager <TelegramID>`\n - Сменить менеджера, получающего уведомления (без перезапуска).\n\n")
+			helpText.WriteString("`/set_critical_reports [<ТАБЛИЦА,...>|all]`\n - Задать таблицы, после подтверждения которых менеджер получает уведомление (без аргументов - показать текущие).\n\n")
+			helpText.WriteString("`/replay <TelegramID>`\n - Повторно отправить преподавателю текущий неподтверждённый вопрос.\n\n")
+			helpText.WriteString("`/check_integrity`\n - Проверить данные на дубликаты и потерянные статусы.\n\n")
+			helpText.WriteString("`/check_cycle <CycleID> [backfill]`\n - Найти преподавателей без статусов по части таблиц цикла; 'backfill' создаёт их и задаёт вопросы.\n\n")
+			helpText.WriteString("`/cycle_gaps <CycleID> [backfill]`\n - Найти активных преподавателей, не попавших в цикл (например, добавленных позже); 'backfill' добавляет их и задаёт первый вопрос.\n\n")
+			helpText.WriteString("`/retry_failed <CycleID>`\n - Повторно отправить первый вопрос тем, кому он не был доставлен.\n\n")
+			helpText.WriteString("`/stats [ГГГГ-ММ]`\n - Показать статистику ответов за месяц. По умолчанию - текущий месяц.\n\n")
+			helpText.WriteString("`/snooze_report <ID статуса> <длительность>`\n - Перенести напоминание по конкретной таблице (например, 3h).\n\n")
+			helpText.WriteString("`/pending_reminders [длительность]`\n - Показать напоминания, которые будут отправлены в ближайшее время (по умолчанию 3h).\n\n")
+			helpText.WriteString("`/failures [длительность]`\n - Показать недоставленные сообщения и причины ошибок (по умолчанию за 24h).\n\n")
+			helpText.WriteString("`/close_cycle <CycleID>`\n - Закрыть цикл: напоминания по нему прекратятся.\n\n")
+			helpText.WriteString("`/reopen_cycle <CycleID>`\n - Снова открыть закрытый цикл.\n\n")
+			helpText.WriteString("`/create_cycle <ГГГГ-ММ-ДД> <mid|end> [--force]`\n - Создать цикл за указанную дату без отправки уведомлений (для загрузки истории).\n\n")
+			helpText.WriteString("`/reconcile_cycle <CycleID>`\n - Отправить недостающие итоговые подтверждения тем, кто подтвердил все таблицы.\n\n")
+			helpText.WriteString("`/renotify <CycleID> <ТАБЛИЦА> confirm`\n - Сбросить подтверждения таблицы в цикле и спросить преподавателей заново.\n\n")
+			helpText.WriteString("`/run_cycle <mid|end> [--round N]`\n - Запустить рассылку за сегодня вручную. Раунд 2 и далее - повторная рассылка в тот же день. Если цикл уже запущен, досылаются только неотправленные вопросы.\n\n")
+			helpText.WriteString("`/simulate <TelegramID>`\n - Запустить тестовый цикл только для одного преподавателя (не влияет на статистику).\n\n")
+			helpText.WriteString("`/runs [CycleID]`\n - Показать историю запусков рассылки (последние 10).\n\n")
+			helpText.WriteString("`/trends [N]`\n - Показать метрики последних N закрытых циклов (по умолчанию 5).\n\n")
+			helpText.WriteString("`/exclude <TelegramID> <CycleID>`\n - Исключить преподавателя из цикла (например, на время отпуска).\n\n")
+			helpText.WriteString("`/include <TelegramID> <CycleID>`\n - Вернуть преподавателя в цикл.\n\n")
+			helpText.WriteString("`/loglevel <debug|info|warn|error>`\n - Временно изменить уровень логирования без перезапуска.\n\n")
+			helpText.WriteString("`/config`\n - Показать действующую конфигурацию (без секретов).\n\n")
+			helpText.WriteString("`/version`\n - Показать версию, коммит, время сборки и время работы бота.\n\n")
+			helpText.WriteString("`/botinfo`\n - Проверить токен: имя и ID бота, доступность Telegram API.\n\n")
+			helpText.WriteString("`/reload_config`\n - Перечитать переменные окружения и .env и применить то, что можно изменить без перезапуска.\n\n")
+			helpText.WriteString("`/help`\n - Показать это справочное сообщение.")
+			return sendLong(c, helpText.String(), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		}
+
+		// Manager Help
+		managerID, err := managers.CurrentManagerID(ctx)
+		if err != nil {
+			logCtx.WithError(err).Error("Failed to resolve manager for /help")
+		}
+		if managerID != 0 && senderID == managerID {
+			logCtx.Info("User identified as Manager, sending manager help.")
+			var helpText strings.Builder
+			helpText.WriteString("Доступные команды Менеджера:\n\n")
+			helpText.WriteString("`/remind <TelegramID>`\n - Сразу напомнить преподавателю о текущей неподтверждённой таблице.\n\n")
+			helpText.WriteString("`/reliability <TelegramID> [ГГГГ]`\n - Показать, какую долю таблиц преподаватель подтверждает без напоминаний (по умолчанию - текущий год).\n\n")
+			helpText.WriteString("`/bulk_confirm <CycleID> <TelegramID,...>`\n - Подтвердить все таблицы цикла за перечисленных преподавателей (например, по итогам собрания).\n\n")
 			helpText.WriteString("`/help`\n - Показать это справочное сообщение.")
 			return c.Send(helpText.String(), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 		}
@@ -74,7 +142,7 @@ func RegisterBotCommands(
 		if err == nil {
 			if userAsTeacher.IsActive {
 				logCtx.WithField("teacher_id", userAsTeacher.ID).Info("User identified as Active Teacher, sending teacher help.")
-				return c.Send("Я буду присылать вам напоминания и вопросы о заполнении таблиц дважды в месяц (15-го числа и в последний день месяца). Пожалуйста, отвечайте на них с помощью кнопок 'Да' или 'Нет', которые появятся под сообщениями.\n\nЕсли вы случайно ответили 'Нет', я напомню вам через час. Если вы не ответите, я напомню на следующий день.\n\n`/help` - Показать это сообщение.")
+				return c.Send(teacherHelpText())
 			}
 			logCtx.WithField("teacher_id", userAsTeacher.ID).Info("User identified as Inactive Teacher, sending restricted help.")
 			return c.Send("Ваш аккаунт преподавателя неактивен. Для получения помощи или активации обратитесь к администратору.")
@@ -87,4 +155,88 @@ func RegisterBotCommands(
 		logCtx.Info("User is unknown, sending restricted help.")
 		return c.Send("Доступных команд для вас нет. Если вы преподаватель и ожидаете уведомлений, пожалуйста, обратитесь к администратору для добавления вас в систему.")
 	})
+	b.Handle("/send_help", func(c telebot.Context) error {
+		senderID := c.Sender().ID
+		logCtx := startHelpLogger.WithField("command", "/send_help").WithField("sender_id", senderID)
+		logCtx.Info("Processing /send_help command")
+
+		if senderID != cfg.AdminTelegramID {
+			logCtx.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /send_help <TelegramID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /send_help <TelegramID>")
+		}
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			logCtx.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		logCtx = logCtx.WithField("teacher_telegram_id", teacherTelegramID)
+
+		targetTeacher, err := teacherRepo.GetByTelegramID(ctx, teacherTelegramID)
+		if err != nil {
+			if err == idb.ErrTeacherNotFound {
+				logCtx.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			}
+			logCtx.WithError(err).Error("Failed to get teacher for /send_help")
+			return c.Send(fmt.Sprintf("Произошла ошибка при поиске преподавателя: %s", err.Error()))
+		}
+		if !targetTeacher.IsActive {
+			logCtx.WithField("teacher_id", targetTeacher.ID).Info("Teacher is inactive; help not sent")
+			return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d) неактивен, справка не отправлена.", targetTeacher.FirstName, teacherTelegramID))
+		}
+
+		// The stored HasStartedBot flag may be stale, so always try and let Telegram decide.
+		if _, err := b.Send(&telebot.User{ID: teacherTelegramID}, teacherHelpText()); err != nil {
+			if errors.Is(err, telebot.ErrChatNotFound) {
+				logCtx.WithError(err).Warn("Teacher has not started the bot; help not delivered")
+				return c.Send(fmt.Sprintf("Не удалось отправить справку: преподаватель %s ещё не запустил бота. Попросите его открыть бота и нажать /start.", targetTeacher.FirstName))
+			}
+			logCtx.WithError(err).Error("Failed to send help to teacher")
+			return c.Send(fmt.Sprintf("Произошла ошибка при отправке справки: %s", err.Error()))
+		}
+		if !targetTeacher.HasStartedBot {
+			// A delivered message proves the chat exists.
+			targetTeacher.HasStartedBot = true
+			if errUpdate := teacherRepo.Update(ctx, targetTeacher); errUpdate != nil {
+				logCtx.WithError(errUpdate).WithField("teacher_id", targetTeacher.ID).Error("Failed to mark teacher as having started the bot")
+			}
+		}
+
+		logCtx.WithField("teacher_id", targetTeacher.ID).Info("Help sent to teacher")
+		return c.Send(fmt.Sprintf("Справка отправлена преподавателю %s (ID: %d).", targetTeacher.FirstName, teacherTelegramID))
+	})
+
+	b.Handle("/as_teacher", func(c telebot.Context) error {
+		senderID := c.Sender().ID
+		logCtx := startHelpLogger.WithField("command", "/as_teacher").WithField("sender_id", senderID)
+		logCtx.Info("Processing /as_teacher command")
+
+		if senderID != cfg.AdminTelegramID {
+			logCtx.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		// The same texts an active teacher gets, addressed by the admin's own first name.
+		if err := c.Send("Так активный преподаватель видит /start:\n\n" + teacherStartText(c.Sender().FirstName)); err != nil {
+			return err
+		}
+		return c.Send("Так активный преподаватель видит /help:\n\n" + teacherHelpText())
+	})
+}
+
+// teacherStartText is the /start greeting of an active teacher. Admins preview it with /as_teacher.
+func teacherStartText(greetingName string) string {
+	return fmt.Sprintf("Привет, %s! Я бот для напоминаний о заполнении таблиц. Я сообщу вам, когда придет время.", greetingName)
+}
+
+// teacherHelpText is the help shown to active teachers, both on /help and when an admin re-sends it with /send_help
+// or previews it with /as_teacher.
+func teacherHelpText() string {
+	return "Я буду присылать вам напоминания и вопросы о заполнении таблиц дважды в месяц (15-го числа и в последний день месяца). Пожалуйста, отвечайте на них с помощью кнопок 'Да' или 'Нет', которые появятся под сообщениями.\n\nЕсли вы случайно ответили 'Нет', я напомню вам через час. Если вы не ответите, я напомню на следующий день.\n\n`/mysummary` - Показать неподтверждённые таблицы и дату следующего опроса.\n`/schedule` - Показать даты ближайших опросов.\n`/help` - Показать это сообщение."
 }
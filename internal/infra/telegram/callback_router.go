@@ -0,0 +1,146 @@
+// internal/infra/telegram/callback_router.go
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// UnknownCallbackAction decides what the router does with callback data no registered prefix matches.
+type UnknownCallbackAction string
+
+const (
+	UnknownCallbackRespond UnknownCallbackAction = "respond" // Log a warning and tell the user the action is unknown
+	UnknownCallbackLogOnly UnknownCallbackAction = "log"     // Log a warning and silently dismiss the button spinner
+	UnknownCallbackIgnore  UnknownCallbackAction = "ignore"  // Silently dismiss the button spinner
+)
+
+// ParseUnknownCallbackAction validates a configured UnknownCallbackAction.
+func ParseUnknownCallbackAction(raw string) (UnknownCallbackAction, error) {
+	switch action := UnknownCallbackAction(strings.ToLower(strings.TrimSpace(raw))); action {
+	case UnknownCallbackRespond, UnknownCallbackLogOnly, UnknownCallbackIgnore:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown callback action %q (expected respond, log or ignore)", raw)
+	}
+}
+
+// maxCallbackDataLength is Telegram's limit on button callback data. Anything longer did not come from our buttons.
+const maxCallbackDataLength = 64
+
+// errInvalidCallbackID is returned by parseCallbackID for payloads that are not a positive ID.
+var errInvalidCallbackID = fmt.Errorf("callback payload is not a positive ID")
+
+// parseCallbackID parses a payload holding a database ID. Only plain positive decimal numbers within bitSize
+// are accepted, so crafted callbacks (signs, extra segments, zero) never reach the database.
+func parseCallbackID(payload string, bitSize int) (int64, error) {
+	for _, r := range payload {
+		if r < '0' || r > '9' {
+			return 0, errInvalidCallbackID
+		}
+	}
+	id, err := strconv.ParseInt(payload, 10, bitSize)
+	if err != nil || id <= 0 {
+		return 0, errInvalidCallbackID
+	}
+	return id, nil
+}
+
+// CallbackHandlerFunc handles a callback whose data starts with the registered prefix.
+// payload is the callback data with the prefix removed (e.g. "123" for "ans_yes_123").
+type CallbackHandlerFunc func(c telebot.Context, payload string, logger *logrus.Entry) error
+
+type callbackRoute struct {
+	prefix  string
+	handler CallbackHandlerFunc
+}
+
+// CallbackRouter dispatches inline-keyboard callbacks to handlers by data prefix.
+// Telebot supports only one OnCallback handler, so every feature registers its prefixes here instead.
+type CallbackRouter struct {
+	routes        []callbackRoute
+	unknownAction UnknownCallbackAction
+	log           *logrus.Entry
+}
+
+func NewCallbackRouter(unknownAction UnknownCallbackAction, baseLogger *logrus.Entry) *CallbackRouter {
+	return &CallbackRouter{
+		unknownAction: unknownAction,
+		log:           baseLogger,
+	}
+}
+
+// Handle registers a handler for callback data starting with prefix. When several prefixes match,
+// the longest one wins. Registering the same prefix twice is a programming error and panics.
+func (r *CallbackRouter) Handle(prefix string, handler CallbackHandlerFunc) {
+	for _, route := range r.routes {
+		if route.prefix == prefix {
+			panic(fmt.Sprintf("callback prefix %q registered twice", prefix))
+		}
+	}
+	r.routes = append(r.routes, callbackRoute{prefix: prefix, handler: handler})
+}
+
+// Register installs the router as the bot's OnCallback handler. Call it once all prefixes are registered.
+func (r *CallbackRouter) Register(b *telebot.Bot) {
+	b.Handle(telebot.OnCallback, r.dispatch)
+}
+
+func (r *CallbackRouter) dispatch(c telebot.Context) error {
+	callback := c.Callback()
+	if callback == nil {
+		r.log.Error("Callback object is nil in OnCallback handler")
+		return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: Некорректный запрос."})
+	}
+	data := strings.TrimSpace(callback.Data) // Telebot prefixes button data with "\f"
+	if len(data) > maxCallbackDataLength {
+		r.log.WithFields(logrus.Fields{
+			"sender_id":   c.Sender().ID,
+			"data_length": len(data),
+		}).Warn("Rejected oversized callback data")
+		return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: Некорректный запрос."})
+	}
+
+	logger := r.log.WithFields(logrus.Fields{
+		"sender_id":     c.Sender().ID,
+		"callback_data": data,
+	})
+	logger.Info("Callback received")
+
+	route, ok := r.match(data)
+	if !ok {
+		return r.handleUnknown(c, logger)
+	}
+	return route.handler(c, strings.TrimPrefix(data, route.prefix), logger.WithField("callback_prefix", route.prefix))
+}
+
+// match finds the route with the longest prefix of data.
+func (r *CallbackRouter) match(data string) (callbackRoute, bool) {
+	var best callbackRoute
+	found := false
+	for _, route := range r.routes {
+		if strings.HasPrefix(data, route.prefix) && (!found || len(route.prefix) > len(best.prefix)) {
+			best = route
+			found = true
+		}
+	}
+	return best, found
+}
+
+func (r *CallbackRouter) handleUnknown(c telebot.Context, logger *logrus.Entry) error {
+	switch r.unknownAction {
+	case UnknownCallbackIgnore:
+		logger.Debug("Ignoring unhandled callback")
+		return c.Respond(&telebot.CallbackResponse{})
+	case UnknownCallbackLogOnly:
+		logger.Warn("Unhandled callback data")
+		return c.Respond(&telebot.CallbackResponse{})
+	default:
+		logger.Warn("Unhandled callback data")
+		return c.Respond(&telebot.CallbackResponse{Text: "Неизвестное действие."})
+	}
+}
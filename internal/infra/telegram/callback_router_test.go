@@ -0,0 +1,61 @@
+package telegram
+
+import "testing"
+
+func TestParseUnknownCallbackAction(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    UnknownCallbackAction
+		wantErr bool
+	}{
+		{raw: "respond", want: UnknownCallbackRespond},
+		{raw: "log", want: UnknownCallbackLogOnly},
+		{raw: "ignore", want: UnknownCallbackIgnore},
+		{raw: "  Ignore ", want: UnknownCallbackIgnore},
+		{raw: "LOG", want: UnknownCallbackLogOnly},
+		{raw: "", wantErr: true},
+		{raw: "drop", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnknownCallbackAction(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseUnknownCallbackAction(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseUnknownCallbackAction(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseCallbackID(t *testing.T) {
+	tests := []struct {
+		payload string
+		bitSize int
+		want    int64
+		wantErr bool
+	}{
+		{payload: "123", bitSize: 64, want: 123},
+		{payload: "2147483647", bitSize: 32, want: 2147483647},
+		{payload: "2147483648", bitSize: 32, wantErr: true},
+		{payload: "0", bitSize: 64, wantErr: true},
+		{payload: "", bitSize: 64, wantErr: true},
+		{payload: "-5", bitSize: 64, wantErr: true},
+		{payload: "+5", bitSize: 64, wantErr: true},
+		{payload: "12_3", bitSize: 64, wantErr: true},
+		{payload: "1 ", bitSize: 64, wantErr: true},
+		{payload: "99999999999999999999", bitSize: 64, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseCallbackID(tt.payload, tt.bitSize)
+		if tt.wantErr {
+			if err != errInvalidCallbackID {
+				t.Errorf("parseCallbackID(%q, %d) error = %v, want errInvalidCallbackID", tt.payload, tt.bitSize, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseCallbackID(%q, %d) = %d, %v; want %d", tt.payload, tt.bitSize, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// internal/infra/telegram/chat_allowlist.go
+package telegram
+
+import (
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// ChatAllowlist returns a middleware that drops updates from chats the bot should not respond in.
+// Private chats are always served (who may do what there is up to the handlers); other chats,
+// such as groups the bot was added to, only when their ID is in allowedChatIDs.
+// Install it with bot.Use before any handler is registered.
+func ChatAllowlist(allowedChatIDs []int64, baseLogger *logrus.Entry) telebot.MiddlewareFunc {
+	allowed := make(map[int64]bool, len(allowedChatIDs))
+	for _, id := range allowedChatIDs {
+		allowed[id] = true
+	}
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			chat := c.Chat()
+			if chat != nil && (chat.Type == telebot.ChatPrivate || allowed[chat.ID]) {
+				return next(c)
+			}
+			fields := logrus.Fields{}
+			if chat != nil {
+				fields["chat_id"] = chat.ID
+				fields["chat_type"] = chat.Type
+			}
+			if sender := c.Sender(); sender != nil {
+				fields["sender_id"] = sender.ID
+			}
+			baseLogger.WithFields(fields).Debug("Ignoring update from a chat that is not allowed")
+			if c.Callback() != nil {
+				return c.Respond() // Stop the button spinner without revealing anything
+			}
+			return nil
+		}
+	}
+}
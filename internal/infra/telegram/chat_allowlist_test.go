@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gopkg.in/telebot.v3"
+)
+
+func TestChatAllowlist(t *testing.T) {
+	const allowedGroupID, otherGroupID = -1001, -1002
+	tests := []struct {
+		name        string
+		chat        *telebot.Chat
+		callback    bool
+		wantHandled bool
+	}{
+		{name: "private chat", chat: &telebot.Chat{ID: 42, Type: telebot.ChatPrivate}, wantHandled: true},
+		{name: "allowed group", chat: &telebot.Chat{ID: allowedGroupID, Type: telebot.ChatGroup}, wantHandled: true},
+		{name: "allowed supergroup", chat: &telebot.Chat{ID: allowedGroupID, Type: telebot.ChatSuperGroup}, wantHandled: true},
+		{name: "other group", chat: &telebot.Chat{ID: otherGroupID, Type: telebot.ChatGroup}},
+		{name: "button in other group", chat: &telebot.Chat{ID: otherGroupID, Type: telebot.ChatGroup}, callback: true},
+		{name: "no chat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			handled := false
+			handler := ChatAllowlist([]int64{allowedGroupID}, logrus.NewEntry(logger))(func(telebot.Context) error {
+				handled = true
+				return nil
+			})
+			c := &fakeContext{chat: tt.chat, sender: &telebot.User{ID: 42}}
+			if tt.callback {
+				c.callback = &telebot.Callback{Data: "ans_yes_1"}
+			}
+
+			if err := handler(c); err != nil {
+				t.Fatalf("handler returned %v", err)
+			}
+			if handled != tt.wantHandled {
+				t.Errorf("handled = %v, want %v", handled, tt.wantHandled)
+			}
+			if len(c.sent) != 0 {
+				t.Errorf("sent %v, want no reply", c.sent)
+			}
+			if tt.callback && len(c.responses) != 1 {
+				t.Errorf("%d callback answers, want 1 to stop the spinner", len(c.responses))
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// internal/infra/telegram/circuit_breaker.go
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+
+	"gopkg.in/telebot.v3"
+)
+
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // Sends go through; consecutive outage failures are counted
+	breakerOpen                         // Sends fail fast with ErrSendCircuitOpen until the cooldown passes
+	breakerHalfOpen                     // One probe send is in flight; everyone else still fails fast
+)
+
+// sendBreaker is a circuit breaker for Telegram sends. After threshold consecutive outage failures
+// it opens for cooldown, then lets a single probe through: success closes it, failure re-opens it.
+type sendBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+func newSendBreaker(threshold int, cooldown time.Duration) *sendBreaker {
+	return &sendBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow reports whether a send may be attempted now.
+func (b *sendBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return domainTelegram.ErrSendCircuitOpen
+		}
+		b.state = breakerHalfOpen // This caller is the probe
+		return nil
+	case breakerHalfOpen:
+		return domainTelegram.ErrSendCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of an allowed send.
+func (b *sendBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !isOutageError(err) {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isOutageError reports whether err suggests Telegram itself is unreachable: a transport failure (connection
+// errors, timeouts) or a server-side API error. Everything else, including API errors telebot does not
+// recognise and returns as plain errors, is a per-message rejection (bad markup, chat not found, blocked by
+// user, flood limits) that says nothing about an outage.
+func isOutageError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *telebot.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= 500
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+
+	"gopkg.in/telebot.v3"
+)
+
+func TestIsOutageError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "server error", err: &telebot.Error{Code: 502, Description: "Bad Gateway"}, want: true},
+		{name: "wrapped server error", err: fmt.Errorf("send: %w", &telebot.Error{Code: 500}), want: true},
+		{name: "chat not found", err: telebot.ErrChatNotFound, want: false},
+		{name: "blocked by user", err: telebot.ErrBlockedByUser, want: false},
+		{name: "flood limit", err: &telebot.Error{Code: 429, Description: "Too Many Requests"}, want: false},
+		{name: "unrecognised API error", err: fmt.Errorf("telegram: Bad Request: can't parse entities (400)"), want: false},
+		{name: "deadline exceeded", err: fmt.Errorf("send: %w", context.DeadlineExceeded), want: true},
+		{name: "url error", err: &url.Error{Op: "Post", URL: "https://api.telegram.org", Err: errors.New("EOF")}, want: true},
+		{name: "net error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: true},
+		{name: "plain error", err: errors.New("boom"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOutageError(tt.err); got != tt.want {
+				t.Errorf("isOutageError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendBreaker(t *testing.T) {
+	outage := &telebot.Error{Code: 503}
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	b := newSendBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	expectAllow := func(step string, want error) {
+		t.Helper()
+		if err := b.allow(); err != want {
+			t.Fatalf("%s: allow() = %v, want %v", step, err, want)
+		}
+	}
+
+	expectAllow("closed", nil)
+	b.record(outage)
+	expectAllow("one failure below threshold", nil)
+	b.record(telebot.ErrChatNotFound)
+	expectAllow("rejection resets the count", nil)
+	b.record(outage)
+	expectAllow("count restarted", nil)
+	b.record(outage)
+	expectAllow("threshold reached", domainTelegram.ErrSendCircuitOpen)
+
+	now = now.Add(30 * time.Second)
+	expectAllow("still cooling down", domainTelegram.ErrSendCircuitOpen)
+
+	now = now.Add(31 * time.Second)
+	expectAllow("probe after cooldown", nil)
+	expectAllow("second caller during probe", domainTelegram.ErrSendCircuitOpen)
+	b.record(outage)
+	expectAllow("failed probe re-opens", domainTelegram.ErrSendCircuitOpen)
+
+	now = now.Add(time.Minute)
+	expectAllow("next probe", nil)
+	b.record(nil)
+	expectAllow("successful probe closes", nil)
+	b.record(outage)
+	expectAllow("closed again below threshold", nil)
+}
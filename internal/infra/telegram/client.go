@@ -2,16 +2,53 @@
 package telegram
 
 import (
+	"encoding/json"
+	"fmt"
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+	"time"
+
 	"gopkg.in/telebot.v3"
 )
 
+// sandboxPrefixReserve is an upper bound for the "[SANDBOX → id]" prefix length.
+const sandboxPrefixReserve = 40
+
 // TelebotAdapter implements the Client interface using the gopkg.in/telebot.v3 library.
 type TelebotAdapter struct {
 	bot *telebot.Bot
+	// sandboxRecipientID, when non-zero, receives every outgoing message instead of the real recipient.
+	sandboxRecipientID int64
+	// breaker, when set, fails sends fast during Telegram outages (see WithCircuitBreaker).
+	breaker *sendBreaker
+}
+
+// NewTelebotAdapter creates the adapter. Pass a non-zero sandboxRecipientID (normally the admin)
+// to enable sandbox mode, in which no message reaches anyone else.
+func NewTelebotAdapter(b *telebot.Bot, sandboxRecipientID int64) *TelebotAdapter {
+	return &TelebotAdapter{bot: b, sandboxRecipientID: sandboxRecipientID}
 }
 
-func NewTelebotAdapter(b *telebot.Bot) *TelebotAdapter {
-	return &TelebotAdapter{bot: b}
+// WithCircuitBreaker makes sends fail fast with ErrSendCircuitOpen for cooldown after threshold consecutive
+// outage failures; a threshold of 0 or less disables the breaker.
+func (tba *TelebotAdapter) WithCircuitBreaker(threshold int, cooldown time.Duration) *TelebotAdapter {
+	if threshold > 0 {
+		tba.breaker = newSendBreaker(threshold, cooldown)
+	}
+	return tba
+}
+
+// send performs one Telegram API call through the circuit breaker, if any.
+func (tba *TelebotAdapter) send(to telebot.Recipient, what interface{}, opts ...interface{}) error {
+	if tba.breaker != nil {
+		if err := tba.breaker.allow(); err != nil {
+			return err
+		}
+	}
+	_, err := tba.bot.Send(to, what, opts...)
+	if tba.breaker != nil {
+		tba.breaker.record(err)
+	}
+	return err
 }
 
 // SendMessage sends a text message to the specified recipient.
@@ -20,7 +57,73 @@ func (tba *TelebotAdapter) SendMessage(recipientChatID int64, text string, optio
 		options = &telebot.SendOptions{}
 	}
 
+	if tba.sandboxRecipientID != 0 && recipientChatID != tba.sandboxRecipientID {
+		text = fmt.Sprintf("[SANDBOX → %d]\n%s", recipientChatID, text)
+		recipientChatID = tba.sandboxRecipientID
+	}
+
 	recipient := &telebot.User{ID: recipientChatID} // For teachers, it's a direct user chat
-	_, err := tba.bot.Send(recipient, text, options)
-	return err
+	return tba.send(recipient, text, options)
+}
+
+// SendLongMessage sends text that may exceed Telegram's limit as several messages.
+// Sending stops at the first failed chunk. Reply markup, if any, is attached to the last chunk only.
+func (tba *TelebotAdapter) SendLongMessage(recipientChatID int64, text string, options *telebot.SendOptions) error {
+	limit := domainTelegram.MaxMessageLength
+	if tba.sandboxRecipientID != 0 && recipientChatID != tba.sandboxRecipientID {
+		limit -= sandboxPrefixReserve // Leave room for the prefix SendMessage adds in sandbox mode
+	}
+	chunks := domainTelegram.SplitMessage(text, limit)
+	for i, chunk := range chunks {
+		chunkOptions := options
+		if options != nil && i < len(chunks)-1 {
+			copied := *options
+			copied.ReplyMarkup = nil
+			chunkOptions = &copied
+		}
+		if err := tba.SendMessage(recipientChatID, chunk, chunkOptions); err != nil {
+			return fmt.Errorf("failed to send chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+	}
+	return nil
+}
+
+// SendDocument sends a file from disk to the specified recipient as a document.
+func (tba *TelebotAdapter) SendDocument(recipientChatID int64, filePath string, fileName string, caption string) error {
+	if tba.sandboxRecipientID != 0 && recipientChatID != tba.sandboxRecipientID {
+		caption = fmt.Sprintf("[SANDBOX → %d]\n%s", recipientChatID, caption)
+		recipientChatID = tba.sandboxRecipientID
+	}
+
+	doc := &telebot.Document{
+		File:     telebot.FromDisk(filePath),
+		FileName: fileName,
+		Caption:  caption,
+	}
+	return tba.send(&telebot.User{ID: recipientChatID}, doc)
+}
+
+// Identity asks Telegram which bot the token belongs to. Unlike the bot.Me cached at startup, this makes a live
+// getMe call, so an error means the API is unreachable or the token was revoked; the cached identity is
+// returned alongside it when available.
+func (tba *TelebotAdapter) Identity() (domainTelegram.BotIdentity, error) {
+	var cached domainTelegram.BotIdentity
+	if tba.bot.Me != nil {
+		cached = domainTelegram.BotIdentity{ID: tba.bot.Me.ID, Username: tba.bot.Me.Username}
+	}
+
+	data, err := tba.bot.Raw("getMe", nil)
+	if err != nil {
+		return cached, err
+	}
+	var resp struct {
+		Result *telebot.User `json:"result"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return cached, fmt.Errorf("failed to decode getMe response: %w", err)
+	}
+	if resp.Result == nil {
+		return cached, fmt.Errorf("getMe response has no result")
+	}
+	return domainTelegram.BotIdentity{ID: resp.Result.ID, Username: resp.Result.Username}, nil
 }
@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/telebot.v3"
+)
+
+// apiMessage is a sendMessage call received by the fake Bot API.
+type apiMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// newFakeBotAPI starts a Bot API stand-in that accepts every sendMessage call and returns the bot and the
+// calls it received.
+func newFakeBotAPI(t *testing.T) (*telebot.Bot, func() []apiMessage) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []apiMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			http.NotFound(w, r)
+			return
+		}
+		var msg apiMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, msg)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	bot, err := telebot.NewBot(telebot.Settings{URL: srv.URL, Token: "test-token", Offline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bot, func() []apiMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]apiMessage(nil), received...)
+	}
+}
+
+func TestSendMessageSandbox(t *testing.T) {
+	const adminID, teacherID = 1000, 2000
+	tests := []struct {
+		name      string
+		sandboxID int64
+		to        int64
+		want      apiMessage
+	}{
+		{name: "sandbox off", to: teacherID, want: apiMessage{ChatID: "2000", Text: "Привет"}},
+		{name: "sandbox on", sandboxID: adminID, to: teacherID, want: apiMessage{ChatID: "1000", Text: "[SANDBOX → 2000]\nПривет"}},
+		{name: "sandbox on, message to the admin", sandboxID: adminID, to: adminID, want: apiMessage{ChatID: "1000", Text: "Привет"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, received := newFakeBotAPI(t)
+			client := NewTelebotAdapter(bot, tt.sandboxID)
+
+			if err := client.SendMessage(tt.to, "Привет", nil); err != nil {
+				t.Fatalf("SendMessage: %v", err)
+			}
+			got := received()
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Bot API received %+v, want [%+v]", got, tt.want)
+			}
+		})
+	}
+}
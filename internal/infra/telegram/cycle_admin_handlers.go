@@ -0,0 +1,977 @@
+// internal/infra/telegram/cycle_admin_handlers.go
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/domain/notification"
+	"teacher_notification_bot/internal/domain/teacher"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// pendingRemindersDefaultWindow is how far ahead /pending_reminders looks when no duration is given.
+const pendingRemindersDefaultWindow = 3 * time.Hour
+
+// failuresDefaultWindow is how far back /failures looks when no duration is given.
+const failuresDefaultWindow = 24 * time.Hour
+
+// RegisterCycleAdminHandlers registers admin commands that operate on notification cycles and report statuses.
+func RegisterCycleAdminHandlers(ctx context.Context, b *telebot.Bot, notificationService app.NotificationService, adminTelegramID int64, baseLogger *logrus.Entry) {
+	b.Handle("/replay", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/replay",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /replay <TelegramID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /replay <TelegramID>")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("teacher_telegram_id", teacherTelegramID)
+
+		reportKey, err := notificationService.ReplayLastQuestion(ctx, teacherTelegramID)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			case app.ErrNoOutstandingReports:
+				logWithError.Info("Nothing to replay")
+				return c.Send(fmt.Sprintf("У преподавателя с Telegram ID %d нет неподтверждённых таблиц в последнем цикле.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to replay question")
+				return c.Send(fmt.Sprintf("Произошла ошибка при повторной отправке вопроса: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.WithField("report_key", reportKey).Info("Question replayed successfully")
+		return c.Send(fmt.Sprintf("Вопрос по %s повторно отправлен преподавателю (Telegram ID: %d).", reportKey, teacherTelegramID))
+	})
+
+	b.Handle("/check_integrity", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/check_integrity",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		report, err := notificationService.CheckIntegrity(ctx)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to run integrity check")
+			return c.Send(fmt.Sprintf("Произошла ошибка при проверке целостности данных: %s", err.Error()))
+		}
+
+		if len(report.DuplicateTelegramIDs) == 0 && len(report.OrphanedStatuses) == 0 {
+			return c.Send("Проверка целостности завершена: проблем не найдено.")
+		}
+
+		var response strings.Builder
+		response.WriteString("--- Проверка целостности ---\n")
+		if len(report.DuplicateTelegramIDs) > 0 {
+			response.WriteString(fmt.Sprintf("\nДублирующиеся Telegram ID (%d):\n", len(report.DuplicateTelegramIDs)))
+			for _, telegramID := range report.DuplicateTelegramIDs {
+				response.WriteString(fmt.Sprintf("- %d\n", telegramID))
+			}
+		}
+		if len(report.OrphanedStatuses) > 0 {
+			response.WriteString(fmt.Sprintf("\nСтатусы без преподавателя или цикла (%d):\n", len(report.OrphanedStatuses)))
+			for _, rs := range report.OrphanedStatuses {
+				response.WriteString(fmt.Sprintf("- ID статуса: %d, ID преподавателя: %d, ID цикла: %d, Таблица: %s\n", rs.ID, rs.TeacherID, rs.CycleID, rs.ReportKey))
+			}
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/check_cycle", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/check_cycle",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /check_cycle <CycleID> [backfill]
+		if (len(args) != 1 && len(args) != 2) || (len(args) == 2 && args[1] != "backfill") {
+			return c.Send("Неверный формат команды. Используйте: /check_cycle <CycleID> [backfill]")
+		}
+		cycleID, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+			return c.Send("Ошибка: ID цикла должен быть числом.")
+		}
+		backfill := len(args) == 2
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"cycle_id": cycleID, "backfill": backfill})
+
+		result, err := notificationService.CheckCycleStatuses(ctx, int32(cycleID), backfill)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			if err == idb.ErrCycleNotFound {
+				logWithError.Warn("Cycle not found")
+				return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+			}
+			logWithError.Error("Failed to check cycle statuses")
+			return c.Send(fmt.Sprintf("Произошла ошибка при проверке цикла: %s", err.Error()))
+		}
+
+		if len(result.Missing) == 0 {
+			return c.Send(fmt.Sprintf("Проверка цикла %d завершена: у всех преподавателей есть статусы по всем таблицам.", cycleID))
+		}
+		teacherIDs := make([]int64, 0, len(result.Missing))
+		for teacherID := range result.Missing {
+			teacherIDs = append(teacherIDs, teacherID)
+		}
+		sort.Slice(teacherIDs, func(i, j int) bool { return teacherIDs[i] < teacherIDs[j] })
+
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("--- Цикл %d: недостающие статусы ---\n", cycleID))
+		for _, teacherID := range teacherIDs {
+			keys := make([]string, len(result.Missing[teacherID]))
+			for i, key := range result.Missing[teacherID] {
+				keys[i] = string(key)
+			}
+			response.WriteString(fmt.Sprintf("- ID преподавателя: %d, Таблицы: %s\n", teacherID, strings.Join(keys, ", ")))
+		}
+		if backfill {
+			response.WriteString(fmt.Sprintf("\nСоздано статусов: %d. Отправлено вопросов: %d, ошибок: %d.", result.Backfilled, len(result.Sent), len(result.Failed)))
+		} else {
+			response.WriteString(fmt.Sprintf("\nЧтобы создать недостающие статусы и задать вопросы, используйте: /check_cycle %d backfill", cycleID))
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/cycle_gaps", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/cycle_gaps",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /cycle_gaps <CycleID> [backfill]
+		if (len(args) != 1 && len(args) != 2) || (len(args) == 2 && args[1] != "backfill") {
+			return c.Send("Неверный формат команды. Используйте: /cycle_gaps <CycleID> [backfill]")
+		}
+		cycleID, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+			return c.Send("Ошибка: ID цикла должен быть числом.")
+		}
+		backfill := len(args) == 2
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"cycle_id": cycleID, "backfill": backfill})
+
+		result, err := notificationService.FindCycleGaps(ctx, int32(cycleID), backfill)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			if err == idb.ErrCycleNotFound {
+				logWithError.Warn("Cycle not found")
+				return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+			}
+			logWithError.Error("Failed to find cycle gaps")
+			return c.Send(fmt.Sprintf("Произошла ошибка при проверке цикла: %s", err.Error()))
+		}
+
+		if len(result.Missing) == 0 {
+			return c.Send(fmt.Sprintf("Проверка цикла %d завершена: все активные преподаватели участвуют в цикле.", cycleID))
+		}
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("--- Цикл %d: активные преподаватели вне цикла ---\n", cycleID))
+		for _, t := range result.Missing {
+			response.WriteString(fmt.Sprintf("- %s (Telegram ID: %d)\n", t.FullName(), t.TelegramID))
+		}
+		if backfill {
+			response.WriteString(fmt.Sprintf("\nСоздано статусов: %d. Отправлено вопросов: %d, ошибок: %d.", result.Backfilled, len(result.Sent), len(result.Failed)))
+		} else {
+			response.WriteString(fmt.Sprintf("\nЧтобы добавить их в цикл и задать вопросы, используйте: /cycle_gaps %d backfill", cycleID))
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/retry_failed", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/retry_failed",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /retry_failed <CycleID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /retry_failed <CycleID>")
+		}
+
+		cycleID, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+			return c.Send("Ошибка: ID цикла должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("cycle_id", cycleID)
+
+		result, err := notificationService.RetryFailedSends(ctx, int32(cycleID))
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			if err == idb.ErrCycleNotFound {
+				logWithError.Warn("Cycle not found")
+				return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+			}
+			logWithError.Error("Failed to retry failed sends")
+			return c.Send(fmt.Sprintf("Произошла ошибка при повторной отправке: %s", err.Error()))
+		}
+
+		handlerLogger.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Retry finished")
+		if len(result.Sent) == 0 && len(result.Failed) == 0 {
+			return c.Send(fmt.Sprintf("В цикле %d нет неотправленных уведомлений.", cycleID))
+		}
+		return c.Send(fmt.Sprintf("Повторная отправка для цикла %d завершена. Отправлено: %d, ошибок: %d.", cycleID, len(result.Sent), len(result.Failed)))
+	})
+
+	b.Handle("/stats", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/stats",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /stats [YYYY-MM], defaults to the current month
+		if len(args) > 1 {
+			return c.Send("Неверный формат команды. Используйте: /stats [ГГГГ-ММ]")
+		}
+
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		if len(args) == 1 {
+			month, err := time.ParseInLocation("2006-01", args[0], now.Location())
+			if err != nil {
+				handlerLogger.WithField("arg", args[0]).Warn("Invalid month format")
+				return c.Send("Ошибка: месяц должен быть в формате ГГГГ-ММ, например 2025-05.")
+			}
+			from = month
+		}
+		to := from.AddDate(0, 1, 0)
+		handlerLogger = handlerLogger.WithField("month", from.Format("2006-01"))
+
+		stats, err := notificationService.GetResponseRateStats(ctx, from, to)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to get stats")
+			return c.Send(fmt.Sprintf("Произошла ошибка при получении статистики: %s", err.Error()))
+		}
+
+		if stats.TotalStatuses == 0 {
+			return c.Send(fmt.Sprintf("За %s нет данных по циклам.", from.Format("2006-01")))
+		}
+
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("--- Статистика за %s ---\n", from.Format("2006-01")))
+		response.WriteString(fmt.Sprintf("Подтверждено таблиц: %d из %d (%.1f%%)\n", stats.ConfirmedStatuses, stats.TotalStatuses, stats.ConfirmationRate()))
+		response.WriteString(fmt.Sprintf("Подтверждено с первого раза: %.1f%%\n", stats.FirstAskRate()))
+		response.WriteString(fmt.Sprintf("Преподавателей: %d\n", stats.TeachersCount))
+		response.WriteString(fmt.Sprintf("Среднее число напоминаний на преподавателя: %.2f\n", stats.AverageRemindersPerTeacher()))
+		return c.Send(response.String())
+	})
+
+	b.Handle("/snooze_report", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/snooze_report",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /snooze_report <ReportStatusID> <duration>, e.g. 3h or 90m
+		if len(args) != 2 {
+			return c.Send("Неверный формат команды. Используйте: /snooze_report <ID статуса> <длительность, например 3h или 90m>")
+		}
+
+		reportStatusID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid report status ID format")
+			return c.Send("Ошибка: ID статуса должен быть числом.")
+		}
+		delay, err := time.ParseDuration(args[1])
+		if err != nil {
+			handlerLogger.WithField("arg", args[1]).Warn("Invalid duration format")
+			return c.Send("Ошибка: неверный формат длительности. Примеры: 30m, 3h, 24h.")
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"report_status_id": reportStatusID, "delay": delay.String()})
+
+		reportStatus, err := notificationService.SnoozeReport(ctx, reportStatusID, delay)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrInvalidSnoozeDuration:
+				logWithError.Warn("Invalid snooze duration")
+				return c.Send("Ошибка: длительность должна быть положительной и не больше 7 дней.")
+			case idb.ErrReportStatusNotFound:
+				logWithError.Warn("Report status not found")
+				return c.Send(fmt.Sprintf("Статус с ID %d не найден.", reportStatusID))
+			case app.ErrReportAlreadyConfirmed:
+				logWithError.Warn("Report status already confirmed")
+				return c.Send(fmt.Sprintf("Таблица по статусу %d уже подтверждена.", reportStatusID))
+			default:
+				logWithError.Error("Failed to snooze report")
+				return c.Send(fmt.Sprintf("Произошла ошибка при переносе напоминания: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Report snoozed successfully")
+		return c.Send(fmt.Sprintf("Напоминание по статусу %d (%s) перенесено на %s.", reportStatus.ID, reportStatus.ReportKey, formatForAdmin(reportStatus.RemindAt.Time)))
+	})
+
+	b.Handle("/exclude", func(c telebot.Context) error {
+		return handleCycleExclusion(ctx, c, "/exclude", notificationService.ExcludeTeacherFromCycle, adminTelegramID, baseLogger)
+	})
+
+	b.Handle("/include", func(c telebot.Context) error {
+		return handleCycleExclusion(ctx, c, "/include", notificationService.IncludeTeacherInCycle, adminTelegramID, baseLogger)
+	})
+
+	b.Handle("/runs", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/runs",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /runs [CycleID]
+		if len(args) > 1 {
+			return c.Send("Неверный формат команды. Используйте: /runs [CycleID]")
+		}
+		var cycleID int64
+		if len(args) == 1 {
+			var err error
+			cycleID, err = strconv.ParseInt(args[0], 10, 32)
+			if err != nil {
+				handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+				return c.Send("Ошибка: ID цикла должен быть числом.")
+			}
+		}
+		handlerLogger = handlerLogger.WithField("cycle_id", cycleID)
+
+		runs, err := notificationService.ListRuns(ctx, int32(cycleID))
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to list runs")
+			return c.Send(fmt.Sprintf("Произошла ошибка при получении истории запусков: %s", err.Error()))
+		}
+		if len(runs) == 0 {
+			return c.Send("Запусков рассылки не найдено.")
+		}
+
+		var response strings.Builder
+		response.WriteString("--- Запуски рассылки ---\n")
+		for _, run := range runs {
+			response.WriteString(fmt.Sprintf("Цикл %d, %s (%s): преподавателей %d, статусов создано %d, отправлено %d, ошибок %d\n",
+				run.CycleID,
+				formatForAdmin(run.StartedAt),
+				run.Duration().Round(time.Second),
+				run.TeachersTargeted,
+				run.StatusesCreated,
+				run.MessagesSent,
+				run.MessagesFailed))
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/trends", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/trends",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /trends [N], defaults to the last 5 cycles
+		if len(args) > 1 {
+			return c.Send("Неверный формат команды. Используйте: /trends [N]")
+		}
+		limit := 5
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle count")
+				return c.Send("Ошибка: N должно быть положительным числом.")
+			}
+			limit = n
+		}
+		handlerLogger = handlerLogger.WithField("limit", limit)
+
+		metrics, err := notificationService.ListCycleTrends(ctx, limit)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to list cycle trends")
+			return c.Send(fmt.Sprintf("Произошла ошибка при получении метрик циклов: %s", err.Error()))
+		}
+		if len(metrics) == 0 {
+			return c.Send("Метрик пока нет: они сохраняются при закрытии цикла.")
+		}
+
+		var response strings.Builder
+		response.WriteString("--- Метрики закрытых циклов ---\n")
+		for _, m := range metrics {
+			response.WriteString(fmt.Sprintf("Цикл %d, %s (%s): преподавателей %d, подтверждено %d из %d (%.1f%%), напоминаний %d, среднее время ответа %s, закрыт через %s\n",
+				m.CycleID,
+				notification.FormatDate(m.CycleDate),
+				m.CycleType.DisplayName(),
+				m.TeachersNotified,
+				m.Confirmations,
+				m.TotalStatuses,
+				m.ConfirmationRate(),
+				m.RemindersSent,
+				m.AverageLatency.Round(time.Minute),
+				m.CompletionTime.Round(time.Minute)))
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/close_cycle", func(c telebot.Context) error {
+		return handleCycleStatusChange(ctx, c, "/close_cycle", notification.CycleStatusClosed, notificationService, adminTelegramID, baseLogger)
+	})
+
+	b.Handle("/reopen_cycle", func(c telebot.Context) error {
+		return handleCycleStatusChange(ctx, c, "/reopen_cycle", notification.CycleStatusOpen, notificationService, adminTelegramID, baseLogger)
+	})
+
+	b.Handle("/pending_reminders", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/pending_reminders",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /pending_reminders [duration]
+		if len(args) > 1 {
+			return c.Send("Неверный формат команды. Используйте: /pending_reminders [длительность, например 3h]")
+		}
+		within := pendingRemindersDefaultWindow
+		if len(args) == 1 {
+			var err error
+			within, err = time.ParseDuration(args[0])
+			if err != nil || within <= 0 {
+				handlerLogger.WithField("arg", args[0]).Warn("Invalid duration format")
+				return c.Send("Ошибка: неверный формат длительности. Примеры: 30m, 3h, 24h.")
+			}
+		}
+		handlerLogger = handlerLogger.WithField("within", within.String())
+
+		reminders, err := notificationService.ListUpcomingReminders(ctx, within)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to list upcoming reminders")
+			return c.Send(fmt.Sprintf("Произошла ошибка при получении запланированных напоминаний: %s", err.Error()))
+		}
+		if len(reminders) == 0 {
+			return c.Send(fmt.Sprintf("В ближайшие %s напоминаний не запланировано.", within))
+		}
+
+		now := time.Now()
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("--- Напоминания в ближайшие %s ---\n", within))
+		for _, r := range reminders {
+			minutesLeft := int(r.Status.RemindAt.Time.Sub(now).Minutes())
+			if minutesLeft < 0 {
+				minutesLeft = 0
+			}
+			response.WriteString(fmt.Sprintf("%s: %s (статус %d) - через %d мин. (%s)\n",
+				r.TeacherName,
+				r.Status.ReportKey,
+				r.Status.ID,
+				minutesLeft,
+				formatForAdmin(r.Status.RemindAt.Time)))
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/failures", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/failures",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /failures [duration]
+		if len(args) > 1 {
+			return c.Send("Неверный формат команды. Используйте: /failures [длительность, например 24h]")
+		}
+		within := failuresDefaultWindow
+		if len(args) == 1 {
+			var err error
+			within, err = time.ParseDuration(args[0])
+			if err != nil || within <= 0 {
+				handlerLogger.WithField("arg", args[0]).Warn("Invalid duration format")
+				return c.Send("Ошибка: неверный формат длительности. Примеры: 30m, 3h, 24h.")
+			}
+		}
+		handlerLogger = handlerLogger.WithField("within", within.String())
+
+		failures, err := notificationService.ListRecentSendFailures(ctx, time.Now().Add(-within))
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to list recent send failures")
+			return c.Send(fmt.Sprintf("Произошла ошибка при получении ошибок отправки: %s", err.Error()))
+		}
+		if len(failures) == 0 {
+			return c.Send(fmt.Sprintf("За последние %s ошибок отправки не было.", within))
+		}
+
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("--- Ошибки отправки за последние %s ---\n", within))
+		for _, f := range failures {
+			response.WriteString(fmt.Sprintf("%s: %s (статус %d, цикл %d) - ошибок: %d, последняя %s: %s\n",
+				f.TeacherName,
+				f.Failure.ReportKey,
+				f.Failure.ReportStatusID,
+				f.Failure.CycleID,
+				f.Failure.FailureCount,
+				formatForAdmin(f.Failure.LastFailedAt),
+				f.Failure.LastError))
+		}
+		return sendLong(c, response.String())
+	})
+
+	b.Handle("/simulate", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/simulate",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /simulate <TelegramID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /simulate <TelegramID>")
+		}
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("teacher_telegram_id", teacherTelegramID)
+
+		cycle, err := notificationService.SimulateCycle(ctx, teacherTelegramID)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с Telegram ID %d не найден.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to simulate cycle")
+				return c.Send(fmt.Sprintf("Произошла ошибка при запуске тестового цикла: %s", err.Error()))
+			}
+		}
+		handlerLogger.WithField("cycle_id", cycle.ID).Info("Simulation cycle started")
+		return c.Send(fmt.Sprintf("Тестовый цикл %d запущен: первый вопрос отправлен преподавателю %d. Напоминания придут по обычному расписанию; закрыть цикл можно командой /close_cycle %d.", cycle.ID, teacherTelegramID, cycle.ID))
+	})
+
+	b.Handle("/run_cycle", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/run_cycle",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /run_cycle <mid|end> [--round N]
+		if len(args) != 1 && !(len(args) == 3 && args[1] == "--round") {
+			return c.Send("Неверный формат команды. Используйте: /run_cycle <mid|end> [--round N]")
+		}
+		cycleType, ok := parseCycleTypeArg(args[0])
+		if !ok {
+			return c.Send("Ошибка: тип цикла должен быть 'mid' или 'end'.")
+		}
+		round := notification.FirstRound
+		if len(args) == 3 {
+			parsed, err := strconv.Atoi(args[2])
+			if err != nil || parsed < notification.FirstRound {
+				handlerLogger.WithField("arg", args[2]).Warn("Invalid round")
+				return c.Send("Ошибка: номер раунда должен быть положительным числом.")
+			}
+			round = parsed
+		}
+		now := time.Now()
+		cycleDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"cycle_type": cycleType, "cycle_round": round})
+
+		result, err := notificationService.RunCycleManually(ctx, cycleType, cycleDate, round)
+		if err == app.ErrInitiationInProgress {
+			handlerLogger.Info("Cycle is already being sent")
+			return c.Send("Рассылка этого цикла уже идёт (например, по расписанию). Дождитесь её окончания.")
+		}
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to run cycle")
+			return c.Send(fmt.Sprintf("Произошла ошибка при запуске цикла: %s", err.Error()))
+		}
+		handlerLogger.WithFields(logrus.Fields{"cycle_id": result.CycleID, "sent_count": len(result.Sent), "failed_count": len(result.Failed), "repeat_run": result.ExistingCycle != nil}).Info("Cycle run finished")
+		if result.ExistingCycle != nil {
+			return c.Send(fmt.Sprintf("Цикл уже был запущен сегодня (ID %d, создан в %s). Повторно уведомлены только неотправленные. Отправлено: %d, ошибок: %d.",
+				result.ExistingCycle.ID, result.ExistingCycle.CreatedAt.Local().Format("15:04"), len(result.Sent), len(result.Failed)))
+		}
+		return c.Send(fmt.Sprintf("Цикл %d (раунд %d) запущен. Отправлено: %d, ошибок: %d.", result.CycleID, round, len(result.Sent), len(result.Failed)))
+	})
+
+	b.Handle("/reconcile_cycle", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/reconcile_cycle",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /reconcile_cycle <CycleID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /reconcile_cycle <CycleID>")
+		}
+
+		cycleID, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+			return c.Send("Ошибка: ID цикла должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("cycle_id", cycleID)
+
+		result, err := notificationService.ReconcileCycle(ctx, int32(cycleID))
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			if err == idb.ErrCycleNotFound {
+				logWithError.Warn("Cycle not found")
+				return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+			}
+			logWithError.Error("Failed to reconcile cycle")
+			return c.Send(fmt.Sprintf("Произошла ошибка при сверке цикла: %s", err.Error()))
+		}
+
+		handlerLogger.WithFields(logrus.Fields{"reconciled_count": len(result.Reconciled), "failed_count": len(result.Failed)}).Info("Reconciliation finished")
+		return c.Send(fmt.Sprintf("Сверка цикла %d завершена. Проверено преподавателей: %d, отправлено недостающих подтверждений: %d, ошибок: %d.",
+			cycleID, result.TeachersChecked, len(result.Reconciled), len(result.Failed)))
+	})
+
+	b.Handle("/renotify", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/renotify",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /renotify <CycleID> <ReportKey> confirm
+		if len(args) != 2 && len(args) != 3 {
+			return c.Send("Неверный формат команды. Используйте: /renotify <CycleID> <ТАБЛИЦА> confirm")
+		}
+		cycleID, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+			return c.Send("Ошибка: ID цикла должен быть числом.")
+		}
+		reportKey, err := notification.ParseReportKey(args[1])
+		if err != nil {
+			handlerLogger.WithError(err).WithField("arg", args[1]).Warn("Invalid report key")
+			return c.Send(fmt.Sprintf("Ошибка: неизвестная таблица. Допустимые значения: %s.", app.FormatCriticalReportKeys(notification.AllReportKeys())))
+		}
+		// Resetting confirmations cannot be undone, so the command only runs with an explicit confirmation.
+		if len(args) != 3 || args[2] != "confirm" {
+			return c.Send(fmt.Sprintf("Все подтверждения таблицы %s в цикле %d будут сброшены, и преподаватели получат вопрос заново. Для запуска повторите команду: /renotify %d %s confirm",
+				reportKey, cycleID, cycleID, reportKey))
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"cycle_id": cycleID, "report_key": reportKey})
+
+		result, err := notificationService.RenotifyReportKey(ctx, int32(cycleID), reportKey)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case idb.ErrCycleNotFound:
+				logWithError.Warn("Cycle not found")
+				return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+			case app.ErrReportKeyNotInCycle:
+				logWithError.Warn("Report key is not part of the cycle")
+				return c.Send(fmt.Sprintf("Таблица %s не входит в цикл %d.", reportKey, cycleID))
+			default:
+				logWithError.Error("Failed to re-notify report key")
+				return c.Send(fmt.Sprintf("Произошла ошибка при повторном опросе: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.WithFields(logrus.Fields{"sent_count": len(result.Sent), "failed_count": len(result.Failed)}).Info("Re-notification finished")
+		if len(result.Sent) == 0 && len(result.Failed) == 0 {
+			return c.Send(fmt.Sprintf("В цикле %d нет подтверждений таблицы %s для повторного опроса.", cycleID, reportKey))
+		}
+		return c.Send(fmt.Sprintf("Подтверждения таблицы %s в цикле %d сброшены. Отправлено: %d, ошибок: %d.", reportKey, cycleID, len(result.Sent), len(result.Failed)))
+	})
+
+	b.Handle("/create_cycle", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/create_cycle",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /create_cycle <YYYY-MM-DD> <mid|end> [--force]
+		if len(args) != 2 && !(len(args) == 3 && args[2] == "--force") {
+			return c.Send("Неверный формат команды. Используйте: /create_cycle <ГГГГ-ММ-ДД> <mid|end> [--force]")
+		}
+		cycleDate, err := time.ParseInLocation("2006-01-02", args[0], time.Local)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid date format")
+			return c.Send("Ошибка: неверный формат даты. Используйте ГГГГ-ММ-ДД, например 2025-01-15.")
+		}
+		cycleType, ok := parseCycleTypeArg(args[1])
+		if !ok {
+			return c.Send("Ошибка: тип цикла должен быть 'mid' или 'end'.")
+		}
+		force := len(args) == 3
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"cycle_date": args[0], "cycle_type": cycleType, "force": force})
+
+		cycle, err := notificationService.CreateCycle(ctx, cycleDate, cycleType, force)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrCycleDateInFuture:
+				logWithError.Warn("Cycle date is in the future")
+				return c.Send("Ошибка: дата цикла в будущем. Добавьте --force, если это сделано намеренно.")
+			case app.ErrCycleAlreadyExists:
+				logWithError.Warn("Cycle already exists")
+				return c.Send(fmt.Sprintf("Цикл за %s (%s) уже существует: ID %d.", args[0], cycleType.DisplayName(), cycle.ID))
+			default:
+				logWithError.Error("Failed to create cycle")
+				return c.Send(fmt.Sprintf("Произошла ошибка при создании цикла: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.WithField("cycle_id", cycle.ID).Info("Cycle created")
+		return c.Send(fmt.Sprintf("Цикл %d за %s (%s) создан. Уведомления не отправлялись.", cycle.ID, args[0], cycleType.DisplayName()))
+	})
+}
+
+// handleCycleExclusion implements /exclude and /include, which share arguments and error handling.
+func handleCycleExclusion(
+	ctx context.Context,
+	c telebot.Context,
+	command string,
+	apply func(ctx context.Context, teacherTelegramID int64, cycleID int32) (*teacher.Teacher, error),
+	adminTelegramID int64,
+	baseLogger *logrus.Entry,
+) error {
+	handlerLogger := baseLogger.WithFields(logrus.Fields{
+		"handler":   command,
+		"sender_id": c.Sender().ID,
+	})
+	handlerLogger.Info("Command received")
+
+	if c.Sender().ID != adminTelegramID {
+		handlerLogger.Warn("Unauthorized access attempt")
+		return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+	}
+
+	args := c.Args()
+	// Expected format: /exclude <TelegramID> <CycleID> (same for /include)
+	if len(args) != 2 {
+		return c.Send(fmt.Sprintf("Неверный формат команды. Используйте: %s <TelegramID> <CycleID>", command))
+	}
+
+	teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+		return c.Send("Ошибка: Telegram ID должен быть числом.")
+	}
+	cycleID, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil {
+		handlerLogger.WithField("arg", args[1]).Warn("Invalid cycle ID format")
+		return c.Send("Ошибка: ID цикла должен быть числом.")
+	}
+	handlerLogger = handlerLogger.WithFields(logrus.Fields{
+		"teacher_telegram_id": teacherTelegramID,
+		"cycle_id":            cycleID,
+	})
+
+	t, err := apply(ctx, teacherTelegramID, int32(cycleID))
+	if err != nil {
+		logWithError := handlerLogger.WithError(err)
+		switch err {
+		case idb.ErrTeacherNotFound:
+			logWithError.Warn("Teacher not found")
+			return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+		case idb.ErrCycleNotFound:
+			logWithError.Warn("Cycle not found")
+			return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+		case idb.ErrCycleExclusionNotFound:
+			logWithError.Info("Teacher was not excluded")
+			return c.Send(fmt.Sprintf("Преподаватель %d не исключён из цикла %d.", teacherTelegramID, cycleID))
+		default:
+			logWithError.Error("Failed to change cycle exclusion")
+			return c.Send(fmt.Sprintf("Произошла ошибка при изменении исключений цикла: %s", err.Error()))
+		}
+	}
+
+	handlerLogger.Info("Cycle exclusion changed successfully")
+	if command == "/exclude" {
+		return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d) исключён из цикла %d: вопросы и напоминания отправляться не будут.", t.FirstName, t.TelegramID, cycleID))
+	}
+	return c.Send(fmt.Sprintf("Преподаватель %s (ID: %d) снова участвует в цикле %d.", t.FirstName, t.TelegramID, cycleID))
+}
+
+// handleCycleStatusChange implements /close_cycle and /reopen_cycle.
+func handleCycleStatusChange(
+	ctx context.Context,
+	c telebot.Context,
+	command string,
+	status notification.CycleStatus,
+	notificationService app.NotificationService,
+	adminTelegramID int64,
+	baseLogger *logrus.Entry,
+) error {
+	handlerLogger := baseLogger.WithFields(logrus.Fields{
+		"handler":   command,
+		"sender_id": c.Sender().ID,
+	})
+	handlerLogger.Info("Command received")
+
+	if c.Sender().ID != adminTelegramID {
+		handlerLogger.Warn("Unauthorized access attempt")
+		return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+	}
+
+	args := c.Args()
+	// Expected format: /close_cycle <CycleID> (same for /reopen_cycle)
+	if len(args) != 1 {
+		return c.Send(fmt.Sprintf("Неверный формат команды. Используйте: %s <CycleID>", command))
+	}
+	cycleID, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil {
+		handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+		return c.Send("Ошибка: ID цикла должен быть числом.")
+	}
+	handlerLogger = handlerLogger.WithField("cycle_id", cycleID)
+
+	_, err = notificationService.SetCycleStatus(ctx, int32(cycleID), status)
+	if err != nil {
+		logWithError := handlerLogger.WithError(err)
+		switch err {
+		case idb.ErrCycleNotFound:
+			logWithError.Warn("Cycle not found")
+			return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+		case app.ErrCycleStatusUnchanged:
+			logWithError.Info("Cycle status unchanged")
+			if status == notification.CycleStatusClosed {
+				return c.Send(fmt.Sprintf("Цикл %d уже закрыт.", cycleID))
+			}
+			return c.Send(fmt.Sprintf("Цикл %d уже открыт.", cycleID))
+		default:
+			logWithError.Error("Failed to change cycle status")
+			return c.Send(fmt.Sprintf("Произошла ошибка при изменении статуса цикла: %s", err.Error()))
+		}
+	}
+
+	handlerLogger.Info("Cycle status changed successfully")
+	if status == notification.CycleStatusClosed {
+		return c.Send(fmt.Sprintf("Цикл %d закрыт. Напоминания по нему больше не отправляются.", cycleID))
+	}
+	return c.Send(fmt.Sprintf("Цикл %d снова открыт. Напоминания возобновятся со следующей проверки.", cycleID))
+}
+
+// parseCycleTypeArg maps the "mid"/"end" command argument to a cycle type.
+func parseCycleTypeArg(arg string) (notification.CycleType, bool) {
+	switch strings.ToLower(arg) {
+	case "mid":
+		return notification.CycleTypeMidMonth, true
+	case "end":
+		return notification.CycleTypeEndMonth, true
+	default:
+		return "", false
+	}
+}
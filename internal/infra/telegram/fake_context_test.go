@@ -0,0 +1,34 @@
+package telegram
+
+import "gopkg.in/telebot.v3"
+
+// fakeContext is a telebot.Context for middleware tests. It serves the chat, sender, callback and text it was
+// built with and records replies; any other method panics through the nil embedded interface.
+type fakeContext struct {
+	telebot.Context
+
+	chat      *telebot.Chat
+	sender    *telebot.User
+	callback  *telebot.Callback
+	text      string
+	sent      []any
+	responses []*telebot.CallbackResponse
+}
+
+func (c *fakeContext) Chat() *telebot.Chat         { return c.chat }
+func (c *fakeContext) Sender() *telebot.User       { return c.sender }
+func (c *fakeContext) Callback() *telebot.Callback { return c.callback }
+func (c *fakeContext) Text() string                { return c.text }
+
+func (c *fakeContext) Send(what any, _ ...any) error {
+	c.sent = append(c.sent, what)
+	return nil
+}
+
+func (c *fakeContext) Respond(resp ...*telebot.CallbackResponse) error {
+	if len(resp) == 0 {
+		resp = []*telebot.CallbackResponse{{}}
+	}
+	c.responses = append(c.responses, resp...)
+	return nil
+}
@@ -0,0 +1,17 @@
+package telegram
+
+import (
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+
+	"gopkg.in/telebot.v3"
+)
+
+// sendLong replies with text that may exceed Telegram's message limit, splitting it on line boundaries.
+func sendLong(c telebot.Context, text string, opts ...interface{}) error {
+	for _, chunk := range domainTelegram.SplitMessage(text, domainTelegram.MaxMessageLength) {
+		if err := c.Send(chunk, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// internal/infra/telegram/manager_handlers.go
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/domain/notification"
+	idb "teacher_notification_bot/internal/infra/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// isManagerOrAdmin reports whether the sender may use manager commands.
+// The admin is always allowed; a manager ID of 0 means no manager is configured.
+func isManagerOrAdmin(ctx context.Context, senderID, adminTelegramID int64, managers app.ManagerIDSource) (bool, error) {
+	if senderID == adminTelegramID {
+		return true, nil
+	}
+	managerID, err := managers.CurrentManagerID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return managerID != 0 && senderID == managerID, nil
+}
+
+// RegisterManagerHandlers registers commands available to the manager (and the admin),
+// plus the admin commands that change who the manager is and which reports they are told about.
+func RegisterManagerHandlers(ctx context.Context, b *telebot.Bot, notificationService app.NotificationService, managerSettings *app.ManagerSettingsService, adminTelegramID int64, baseLogger *logrus.Entry) {
+	b.Handle("/remind", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/remind",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		allowed, err := isManagerOrAdmin(ctx, c.Sender().ID, adminTelegramID, managerSettings)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to resolve manager for authorization")
+			return c.Send("Произошла ошибка при проверке прав. Пожалуйста, попробуйте позже.")
+		}
+		if !allowed {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /remind <TelegramID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /remind <TelegramID>")
+		}
+
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("teacher_telegram_id", teacherTelegramID)
+
+		reportKey, err := notificationService.RemindTeacherNow(ctx, teacherTelegramID)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case idb.ErrTeacherNotFound:
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			case app.ErrNoOutstandingReports:
+				logWithError.Info("Nothing to remind about")
+				return c.Send(fmt.Sprintf("У преподавателя с Telegram ID %d нет неподтверждённых таблиц в последнем цикле.", teacherTelegramID))
+			default:
+				logWithError.Error("Failed to send reminder")
+				return c.Send(fmt.Sprintf("Произошла ошибка при отправке напоминания: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.WithField("report_key", reportKey).Info("Reminder sent successfully")
+		return c.Send(fmt.Sprintf("Напоминание по %s отправлено преподавателю (Telegram ID: %d).", reportKey, teacherTelegramID))
+	})
+
+	b.Handle("/reliability", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/reliability",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		allowed, err := isManagerOrAdmin(ctx, c.Sender().ID, adminTelegramID, managerSettings)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to resolve manager for authorization")
+			return c.Send("Произошла ошибка при проверке прав. Пожалуйста, попробуйте позже.")
+		}
+		if !allowed {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /reliability <TelegramID> [YYYY], defaults to the current year
+		if len(args) != 1 && len(args) != 2 {
+			return c.Send("Неверный формат команды. Используйте: /reliability <TelegramID> [ГГГГ]")
+		}
+		teacherTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		now := time.Now()
+		from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		if len(args) == 2 {
+			year, err := time.ParseInLocation("2006", args[1], now.Location())
+			if err != nil {
+				handlerLogger.WithField("arg", args[1]).Warn("Invalid year format")
+				return c.Send("Ошибка: год должен быть в формате ГГГГ, например 2025.")
+			}
+			from = year
+		}
+		to := from.AddDate(1, 0, 0)
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"teacher_telegram_id": teacherTelegramID, "year": from.Year()})
+
+		reliability, err := notificationService.GetTeacherReliability(ctx, teacherTelegramID, from, to)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			if err == idb.ErrTeacherNotFound {
+				logWithError.Warn("Teacher not found")
+				return c.Send(fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID))
+			}
+			logWithError.Error("Failed to get teacher reliability")
+			return c.Send(fmt.Sprintf("Произошла ошибка при получении статистики: %s", err.Error()))
+		}
+
+		if reliability.TotalReports == 0 {
+			return c.Send(fmt.Sprintf("%s: за %d год нет данных по циклам (н/д).", reliability.Teacher.FullName(), from.Year()))
+		}
+		return c.Send(fmt.Sprintf("%s: за %d год подтверждено с первого раза %.1f%% таблиц (всего таблиц: %d).",
+			reliability.Teacher.FullName(), from.Year(), reliability.FirstTryRate, reliability.TotalReports))
+	})
+
+	b.Handle("/bulk_confirm", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/bulk_confirm",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		allowed, err := isManagerOrAdmin(ctx, c.Sender().ID, adminTelegramID, managerSettings)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to resolve manager for authorization")
+			return c.Send("Произошла ошибка при проверке прав. Пожалуйста, попробуйте позже.")
+		}
+		if !allowed {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /bulk_confirm <CycleID> <TelegramID,TelegramID,...>
+		if len(args) != 2 {
+			return c.Send("Неверный формат команды. Используйте: /bulk_confirm <CycleID> <TelegramID,TelegramID,...>")
+		}
+		cycleID, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid cycle ID format")
+			return c.Send("Ошибка: ID цикла должен быть числом.")
+		}
+		var teacherTelegramIDs []int64
+		seen := make(map[int64]bool)
+		for _, part := range strings.Split(args[1], ",") {
+			tgID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				handlerLogger.WithField("arg", part).Warn("Invalid Telegram ID format")
+				return c.Send(fmt.Sprintf("Ошибка: Telegram ID должен быть числом (%q).", part))
+			}
+			if !seen[tgID] {
+				seen[tgID] = true
+				teacherTelegramIDs = append(teacherTelegramIDs, tgID)
+			}
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"cycle_id": cycleID, "teachers_count": len(teacherTelegramIDs)})
+
+		results, err := notificationService.BulkConfirmForTeachers(ctx, c.Sender().ID, int32(cycleID), teacherTelegramIDs)
+		if err != nil {
+			logWithError := handlerLogger.WithError(err)
+			if err == idb.ErrCycleNotFound {
+				logWithError.Warn("Cycle not found")
+				return c.Send(fmt.Sprintf("Цикл с ID %d не найден.", cycleID))
+			}
+			logWithError.Error("Failed to confirm reports on behalf of teachers")
+			return c.Send(fmt.Sprintf("Произошла ошибка при подтверждении: %s", err.Error()))
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Подтверждение за преподавателей, цикл %d:\n", cycleID))
+		for _, r := range results {
+			switch {
+			case r.Err == nil:
+				sb.WriteString(fmt.Sprintf("✅ %s (%d): подтверждено таблиц: %d\n", r.Teacher.FullName(), r.TelegramID, r.ConfirmedCount))
+			case r.Err == idb.ErrTeacherNotFound:
+				sb.WriteString(fmt.Sprintf("❌ %d: преподаватель не найден\n", r.TelegramID))
+			case r.Err == app.ErrNothingToConfirm:
+				sb.WriteString(fmt.Sprintf("➖ %s (%d): всё уже подтверждено\n", r.Teacher.FullName(), r.TelegramID))
+			default:
+				handlerLogger.WithError(r.Err).WithField("teacher_telegram_id", r.TelegramID).Error("Proxy confirmation failed for teacher")
+				sb.WriteString(fmt.Sprintf("⚠️ %d: ошибка: %s\n", r.TelegramID, r.Err.Error()))
+			}
+		}
+		handlerLogger.Info("Proxy confirmation finished")
+		return c.Send(sb.String())
+	})
+
+	b.Handle("/set_manager", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/set_manager",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /set_manager <TelegramID>
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /set_manager <TelegramID>")
+		}
+		managerTelegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid Telegram ID format")
+			return c.Send("Ошибка: Telegram ID должен быть числом.")
+		}
+		handlerLogger = handlerLogger.WithField("manager_telegram_id", managerTelegramID)
+
+		if err := managerSettings.SetManager(ctx, c.Sender().ID, managerTelegramID); err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			case app.ErrInvalidManagerID:
+				logWithError.Warn("Invalid manager ID")
+				return c.Send("Ошибка: Telegram ID менеджера должен быть положительным числом.")
+			case app.ErrManagerUnreachable:
+				logWithError.Warn("New manager is unreachable")
+				return c.Send(fmt.Sprintf("Не удалось отправить сообщение пользователю %d. Попросите его сначала запустить бота (/start).", managerTelegramID))
+			default:
+				logWithError.Error("Failed to change manager")
+				return c.Send(fmt.Sprintf("Произошла ошибка при смене менеджера: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.Info("Manager changed successfully")
+		return c.Send(fmt.Sprintf("Менеджер изменён: уведомления теперь получает пользователь %d.", managerTelegramID))
+	})
+
+	b.Handle("/set_critical_reports", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/set_critical_reports",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /set_critical_reports [<ReportKey,...>|all]; without arguments shows the current value
+		if len(args) == 0 {
+			keys, err := managerSettings.CriticalReportKeys(ctx)
+			if err != nil {
+				handlerLogger.WithError(err).Error("Failed to read manager-critical reports")
+				return c.Send(fmt.Sprintf("Произошла ошибка при чтении настройки: %s", err.Error()))
+			}
+			return c.Send(fmt.Sprintf("Менеджер получает подтверждение, когда подтверждены: %s.", app.FormatCriticalReportKeys(keys)))
+		}
+		if len(args) != 1 {
+			return c.Send("Неверный формат команды. Используйте: /set_critical_reports <TABLE_1_LESSONS,TABLE_2_OTV,...|all>")
+		}
+		keys, err := app.ParseCriticalReportKeys(args[0])
+		if err != nil {
+			handlerLogger.WithError(err).WithField("arg", args[0]).Warn("Invalid report keys")
+			return c.Send(fmt.Sprintf("Ошибка: неизвестная таблица. Допустимые значения: %s или all.", app.FormatCriticalReportKeys(notification.AllReportKeys())))
+		}
+
+		if err := managerSettings.SetCriticalReportKeys(ctx, c.Sender().ID, keys); err != nil {
+			logWithError := handlerLogger.WithError(err)
+			switch err {
+			case app.ErrAdminNotAuthorized:
+				logWithError.Warn("Admin not authorized (service level)")
+				return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+			default:
+				logWithError.Error("Failed to change manager-critical reports")
+				return c.Send(fmt.Sprintf("Произошла ошибка при сохранении настройки: %s", err.Error()))
+			}
+		}
+
+		handlerLogger.WithField("report_keys", app.FormatCriticalReportKeys(keys)).Info("Manager-critical reports changed successfully")
+		return c.Send(fmt.Sprintf("Готово. Менеджер получит подтверждение, когда подтверждены: %s.", app.FormatCriticalReportKeys(keys)))
+	})
+}
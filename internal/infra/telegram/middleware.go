@@ -0,0 +1,89 @@
+// internal/infra/telegram/middleware.go
+package telegram
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// updateFields describes who sent an update and what it was, for middleware logs.
+func updateFields(c telebot.Context) logrus.Fields {
+	fields := logrus.Fields{}
+	if sender := c.Sender(); sender != nil {
+		fields["sender_id"] = sender.ID
+	}
+	if chat := c.Chat(); chat != nil {
+		fields["chat_id"] = chat.ID
+	}
+	if cb := c.Callback(); cb != nil {
+		fields["callback_data"] = cb.Data
+	} else if text := c.Text(); text != "" {
+		fields["context_text"] = text
+	}
+	return fields
+}
+
+// Recover returns a middleware that turns a panic in any handler into a logged error with its stack trace
+// and a generic reply, so one bad update never takes the poller goroutine down.
+func Recover(baseLogger *logrus.Entry) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) (err error) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				baseLogger.WithFields(updateFields(c)).
+					WithField("panic", fmt.Sprint(recovered)).
+					WithField("stack", string(debug.Stack())).
+					Error("Recovered from panic in handler")
+				if c.Callback() != nil {
+					err = c.Respond(&telebot.CallbackResponse{Text: "Произошла ошибка."})
+					return
+				}
+				err = c.Send("Произошла внутренняя ошибка. Пожалуйста, попробуйте позже.")
+			}()
+			return next(c)
+		}
+	}
+}
+
+// RequireSender returns a middleware that drops updates without a sender, such as channel posts. Every handler
+// identifies the user by c.Sender().ID, so they can rely on it being set. Callbacks are answered so the button
+// spinner stops; other updates are only logged, since replying in a channel would be noise at best.
+func RequireSender(baseLogger *logrus.Entry) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			if c.Sender() != nil {
+				return next(c)
+			}
+			baseLogger.WithFields(updateFields(c)).Warn("Ignoring update without a sender")
+			if c.Callback() != nil {
+				return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: Некорректный запрос."})
+			}
+			return nil
+		}
+	}
+}
+
+// LogRequests returns a middleware that logs how long each handled update took and whether it failed.
+// Handlers log their own details; this adds one uniform line per update at debug level.
+func LogRequests(baseLogger *logrus.Entry) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			startedAt := time.Now()
+			err := next(c)
+			entry := baseLogger.WithFields(updateFields(c)).WithField("duration", time.Since(startedAt).String())
+			if err != nil {
+				entry.WithError(err).Debug("Update handled with error")
+			} else {
+				entry.Debug("Update handled")
+			}
+			return err
+		}
+	}
+}
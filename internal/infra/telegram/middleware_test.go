@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gopkg.in/telebot.v3"
+)
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name         string
+		callback     bool
+		wantSent     int
+		wantResponse int
+	}{
+		{name: "message", wantSent: 1},
+		{name: "callback", callback: true, wantResponse: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			handler := Recover(logrus.NewEntry(logger))(func(telebot.Context) error {
+				panic("boom")
+			})
+			c := &fakeContext{chat: &telebot.Chat{ID: 42, Type: telebot.ChatPrivate}, sender: &telebot.User{ID: 42}, text: "/mysummary"}
+			if tt.callback {
+				c.callback = &telebot.Callback{Data: "ans_yes_1"}
+			}
+
+			if err := handler(c); err != nil {
+				t.Fatalf("handler returned %v", err)
+			}
+			if len(c.sent) != tt.wantSent || len(c.responses) != tt.wantResponse {
+				t.Errorf("%d messages and %d callback answers, want %d and %d", len(c.sent), len(c.responses), tt.wantSent, tt.wantResponse)
+			}
+			entry := hook.LastEntry()
+			if entry == nil || entry.Level != logrus.ErrorLevel {
+				t.Fatalf("last log entry = %v, want the recovered panic at error level", entry)
+			}
+			if entry.Data["panic"] != "boom" || entry.Data["sender_id"] != int64(42) {
+				t.Errorf("log fields = %v, want the panic value and the sender", entry.Data)
+			}
+			if stack, _ := entry.Data["stack"].(string); !strings.Contains(stack, "TestRecover") {
+				t.Errorf("logged stack does not reach the panicking handler:\n%s", stack)
+			}
+		})
+	}
+}
+
+func TestRequireSender(t *testing.T) {
+	tests := []struct {
+		name         string
+		sender       *telebot.User
+		callback     bool
+		wantHandled  bool
+		wantResponse int
+	}{
+		{name: "with sender", sender: &telebot.User{ID: 42}, wantHandled: true},
+		{name: "channel post"},
+		{name: "callback without sender", callback: true, wantResponse: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			handled := false
+			handler := RequireSender(logrus.NewEntry(logger))(func(telebot.Context) error {
+				handled = true
+				return nil
+			})
+			c := &fakeContext{chat: &telebot.Chat{ID: -1001, Type: telebot.ChatChannel}, sender: tt.sender}
+			if tt.callback {
+				c.callback = &telebot.Callback{Data: "ans_yes_1"}
+			}
+
+			if err := handler(c); err != nil {
+				t.Fatalf("handler returned %v", err)
+			}
+			if handled != tt.wantHandled {
+				t.Errorf("handled = %v, want %v", handled, tt.wantHandled)
+			}
+			if len(c.sent) != 0 || len(c.responses) != tt.wantResponse {
+				t.Errorf("%d messages and %d callback answers, want none and %d", len(c.sent), len(c.responses), tt.wantResponse)
+			}
+			if warned := len(hook.Entries) > 0; warned == tt.wantHandled {
+				t.Errorf("logged %d entries, want a warning only for a dropped update", len(hook.Entries))
+			}
+		})
+	}
+}
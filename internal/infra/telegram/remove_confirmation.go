@@ -0,0 +1,117 @@
+// internal/infra/telegram/remove_confirmation.go
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/app"
+	idb "teacher_notification_bot/internal/infra/database"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// Callback data of the /remove_teacher confirmation buttons: <prefix><TelegramID>.
+const (
+	confirmRemoveCallbackPrefix = "confirm_remove_"
+	cancelRemoveCallbackPrefix  = "cancel_remove_"
+)
+
+// removeConfirmationMarkup is the "Подтвердить"/"Отмена" keyboard sent by /remove_teacher when confirmation is on.
+func removeConfirmationMarkup(teacherTelegramID int64) *telebot.ReplyMarkup {
+	markup := &telebot.ReplyMarkup{}
+	markup.Inline(markup.Row(
+		markup.Data("Подтвердить", fmt.Sprintf("%s%d", confirmRemoveCallbackPrefix, teacherTelegramID)),
+		markup.Data("Отмена", fmt.Sprintf("%s%d", cancelRemoveCallbackPrefix, teacherTelegramID)),
+	))
+	return markup
+}
+
+// removeTeacher deactivates the teacher and returns the reply for the admin, whether it worked or not.
+func removeTeacher(ctx context.Context, adminService *app.AdminService, senderID int64, teacherTelegramID int64, handlerLogger *logrus.Entry) string {
+	removedTeacher, err := adminService.RemoveTeacher(ctx, senderID, teacherTelegramID)
+	noticeNotDelivered := err == app.ErrDeactivationMessageNotDelivered
+	if noticeNotDelivered {
+		handlerLogger.WithError(err).Warn("Teacher deactivated, but deactivation message was not delivered")
+		err = nil
+	}
+	if err != nil {
+		logWithError := handlerLogger.WithError(err)
+		switch err {
+		case app.ErrAdminNotAuthorized:
+			logWithError.Warn("Admin not authorized (service level)")
+			return "Ошибка: У вас нет прав для выполнения этой команды."
+		case idb.ErrTeacherNotFound:
+			logWithError.Warn("Teacher to remove not found")
+			return fmt.Sprintf("Преподаватель с таким Telegram ID %d не найден.", teacherTelegramID)
+		case app.ErrTeacherAlreadyInactive:
+			logWithError.Warn("Teacher already inactive")
+			if removedTeacher != nil {
+				return fmt.Sprintf("Преподаватель %s %s (ID: %d) уже был деактивирован.", removedTeacher.FirstName, removedTeacher.LastName.String, removedTeacher.TelegramID)
+			}
+			return fmt.Sprintf("Преподаватель с Telegram ID %d уже был деактивирован.", teacherTelegramID)
+		default:
+			logWithError.Error("Failed to remove teacher")
+			return fmt.Sprintf("Произошла ошибка при удалении преподавателя: %s", err.Error())
+		}
+	}
+
+	handlerLogger.WithFields(logrus.Fields{
+		"removed_teacher_id": removedTeacher.ID,
+	}).Info("Teacher removed (deactivated) successfully")
+
+	var teacherName strings.Builder
+	teacherName.WriteString(removedTeacher.FirstName)
+	if removedTeacher.LastName.Valid && removedTeacher.LastName.String != "" {
+		teacherName.WriteString(" ")
+		teacherName.WriteString(removedTeacher.LastName.String)
+	}
+	successMsg := fmt.Sprintf("Преподаватель %s (ID: %d) успешно деактивирован.", teacherName.String(), removedTeacher.TelegramID)
+	if noticeNotDelivered {
+		successMsg += "\nВнимание: не удалось отправить преподавателю уведомление о деактивации."
+	}
+	return successMsg
+}
+
+// RegisterRemoveTeacherConfirmation handles the buttons of the /remove_teacher confirmation prompt. The prompt
+// message is edited to show the outcome, so the buttons cannot be pressed twice.
+func RegisterRemoveTeacherConfirmation(ctx context.Context, router *CallbackRouter, adminService *app.AdminService, adminTelegramID int64) {
+	router.Handle(confirmRemoveCallbackPrefix, func(c telebot.Context, payload string, baseLogger *logrus.Entry) error {
+		handlerLogger := baseLogger.WithField("handler", "remove_teacher_confirm_callback")
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized remove confirmation callback")
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: У вас нет прав для выполнения этой команды."})
+		}
+
+		teacherTelegramID, err := parseCallbackID(payload, 64)
+		if err != nil {
+			handlerLogger.WithError(err).Warn("Invalid remove confirmation callback")
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: Некорректный запрос."})
+		}
+		handlerLogger = handlerLogger.WithField("teacher_telegram_id", teacherTelegramID)
+
+		reply := removeTeacher(ctx, adminService, c.Sender().ID, teacherTelegramID, handlerLogger)
+		if _, err := c.Bot().Edit(c.Message(), reply); err != nil {
+			handlerLogger.WithError(err).Error("Failed to edit remove confirmation message")
+			if err := c.Send(reply); err != nil {
+				return err
+			}
+		}
+		return c.Respond(&telebot.CallbackResponse{})
+	})
+
+	router.Handle(cancelRemoveCallbackPrefix, func(c telebot.Context, payload string, baseLogger *logrus.Entry) error {
+		handlerLogger := baseLogger.WithField("handler", "remove_teacher_cancel_callback")
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized remove cancellation callback")
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: У вас нет прав для выполнения этой команды."})
+		}
+
+		handlerLogger.WithField("payload", payload).Info("Teacher removal cancelled")
+		if _, err := c.Bot().Edit(c.Message(), "Удаление отменено."); err != nil {
+			handlerLogger.WithError(err).Error("Failed to edit remove confirmation message")
+		}
+		return c.Respond(&telebot.CallbackResponse{Text: "Отменено."})
+	})
+}
@@ -0,0 +1,187 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/app"
+	domainTelegram "teacher_notification_bot/internal/domain/telegram"
+	"teacher_notification_bot/internal/infra/config"
+	"teacher_notification_bot/internal/infra/logger"
+	"teacher_notification_bot/internal/version"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// ConfigApplyFunc applies the runtime-adjustable settings of a freshly loaded configuration.
+type ConfigApplyFunc func(next *config.AppConfig) error
+
+// IdentitySource reports which bot the configured token belongs to (see TelebotAdapter.Identity).
+type IdentitySource interface {
+	Identity() (domainTelegram.BotIdentity, error)
+}
+
+// RegisterSystemAdminHandlers registers admin commands that operate on the bot process itself.
+func RegisterSystemAdminHandlers(ctx context.Context, b *telebot.Bot, liveCfg *config.Live, managerSettings *app.ManagerSettingsService, identity IdentitySource, applyConfig ConfigApplyFunc, baseLogger *logrus.Entry) {
+	adminTelegramID := liveCfg.Current().AdminTelegramID // Restart-only, so safe to capture
+	logLevelRevertAfter := liveCfg.Current().LogLevelRevertAfter
+
+	b.Handle("/loglevel", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/loglevel",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		args := c.Args()
+		// Expected format: /loglevel <debug|info|warn|error>
+		if len(args) != 1 {
+			return c.Send(fmt.Sprintf("Текущий уровень логирования: %s. Используйте: /loglevel <debug|info|warn|error>", logger.Log.GetLevel().String()))
+		}
+
+		switch args[0] {
+		case "debug", "info", "warn", "error":
+		default:
+			handlerLogger.WithField("arg", args[0]).Warn("Invalid log level argument")
+			return c.Send("Неверный уровень. Используйте: debug, info, warn или error.")
+		}
+
+		level, err := logger.SetLevel(args[0], logLevelRevertAfter)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to change log level")
+			return c.Send(fmt.Sprintf("Произошла ошибка при изменении уровня логирования: %s", err.Error()))
+		}
+
+		handlerLogger.WithField("log_level", level.String()).Info("Log level changed")
+		if logLevelRevertAfter > 0 {
+			return c.Send(fmt.Sprintf("Уровень логирования: %s. Через %s он вернётся к настроенному значению.", level.String(), logLevelRevertAfter))
+		}
+		return c.Send(fmt.Sprintf("Уровень логирования: %s.", level.String()))
+	})
+
+	b.Handle("/config", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/config",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		cfg := liveCfg.Current()
+		var text strings.Builder
+		text.WriteString("Действующая конфигурация (секреты скрыты):\n\n")
+		for _, entry := range cfg.Describe() {
+			text.WriteString(entry.String() + "\n")
+		}
+
+		// Values admins can change without a restart override the ones above.
+		text.WriteString("\nИзменено во время работы:\n")
+		text.WriteString(fmt.Sprintf("Уровень логирования: %s\n", logger.Log.GetLevel().String()))
+		if managerID, err := managerSettings.CurrentManagerID(ctx); err != nil {
+			handlerLogger.WithError(err).Error("Failed to resolve current manager")
+			text.WriteString("Менеджер: ошибка чтения настройки\n")
+		} else {
+			text.WriteString(fmt.Sprintf("Менеджер: %d\n", managerID))
+		}
+		if keys, err := managerSettings.CriticalReportKeys(ctx); err != nil {
+			handlerLogger.WithError(err).Error("Failed to resolve manager-critical reports")
+			text.WriteString("Таблицы для подтверждения менеджеру: ошибка чтения настройки\n")
+		} else {
+			text.WriteString(fmt.Sprintf("Таблицы для подтверждения менеджеру: %s\n", app.FormatCriticalReportKeys(keys)))
+		}
+
+		return sendLong(c, text.String(), &telebot.SendOptions{})
+	})
+
+	b.Handle("/version", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/version",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		info := version.Current()
+		return c.Send(fmt.Sprintf("Версия: %s\nКоммит: %s\nСобрано: %s\nЗапущен: %s\nРаботает: %s",
+			info.Version, info.Commit, info.BuildTime,
+			info.StartedAt.In(liveCfg.Current().AdminLocation).Format("02.01.2006 15:04"), version.FormatUptime(version.Uptime())))
+	})
+
+	b.Handle("/botinfo", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/botinfo",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		bot, err := identity.Identity()
+		if err != nil {
+			handlerLogger.WithError(err).Warn("Telegram API getMe check failed")
+			return c.Send(fmt.Sprintf("Бот: @%s (ID %d, данные при запуске)\nTelegram API: недоступен (%s)", bot.Username, bot.ID, err.Error()))
+		}
+		return c.Send(fmt.Sprintf("Бот: @%s (ID %d)\nTelegram API: доступен, токен действителен", bot.Username, bot.ID))
+	})
+
+	b.Handle("/reload_config", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/reload_config",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized access attempt")
+			return c.Send("Ошибка: У вас нет прав для выполнения этой команды.")
+		}
+
+		next, err := config.Load()
+		if err != nil {
+			handlerLogger.WithError(err).Warn("Reloaded configuration is invalid")
+			return c.Send(fmt.Sprintf("Новая конфигурация некорректна, ничего не изменено: %s", err.Error()))
+		}
+		diff := liveCfg.Current().DiffForReload(next)
+		if diff.Empty() {
+			return c.Send("Конфигурация не изменилась.")
+		}
+
+		var text strings.Builder
+		if len(diff.Applicable) > 0 {
+			if err := applyConfig(next); err != nil {
+				handlerLogger.WithError(err).Error("Failed to apply reloaded configuration")
+				return c.Send(fmt.Sprintf("Не удалось применить конфигурацию, ничего не изменено: %s", err.Error()))
+			}
+			handlerLogger.WithField("applied", diff.Applicable).Info("Configuration reloaded")
+			text.WriteString("Применено без перезапуска:\n")
+			for _, name := range diff.Applicable {
+				text.WriteString(" - " + name + "\n")
+			}
+		}
+		if len(diff.RestartRequired) > 0 {
+			handlerLogger.WithField("restart_required", diff.RestartRequired).Warn("Reloaded configuration changes settings that need a restart")
+			text.WriteString("Не применено, требуется перезапуск бота:\n")
+			for _, name := range diff.RestartRequired {
+				text.WriteString(" - " + name + "\n")
+			}
+		}
+		return c.Send(text.String())
+	})
+}
@@ -0,0 +1,105 @@
+// internal/infra/telegram/teacher_commands_handlers.go
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/domain/teacher"
+	"teacher_notification_bot/internal/infra/config"
+	idb "teacher_notification_bot/internal/infra/database"
+	"teacher_notification_bot/internal/infra/scheduler"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// scheduleListLength is how many upcoming cycle starts /schedule shows.
+const scheduleListLength = 4
+
+// RegisterTeacherCommands registers commands available to teachers themselves.
+func RegisterTeacherCommands(ctx context.Context, b *telebot.Bot, notificationService app.NotificationService, teacherRepo teacher.Repository, liveCfg *config.Live, baseLogger *logrus.Entry) {
+	b.Handle("/mysummary", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/mysummary",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		summary, err := notificationService.GetTeacherSummary(ctx, c.Sender().ID)
+		if err != nil {
+			if err == idb.ErrTeacherNotFound {
+				handlerLogger.Info("User is unknown")
+				return c.Send("Доступных команд для вас нет. Если вы преподаватель и ожидаете уведомлений, пожалуйста, обратитесь к администратору для добавления вас в систему.")
+			}
+			handlerLogger.WithError(err).Error("Failed to build teacher summary")
+			return c.Send("Произошла ошибка при проверке вашего статуса. Пожалуйста, попробуйте позже.")
+		}
+		if !summary.Teacher.IsActive {
+			handlerLogger.WithField("teacher_id", summary.Teacher.ID).Info("User identified as Inactive Teacher")
+			return c.Send("Ваш аккаунт преподавателя неактивен. Пожалуйста, свяжитесь с администратором.")
+		}
+
+		var response strings.Builder
+		switch {
+		case summary.Cycle == nil:
+			response.WriteString("Сейчас нет открытых циклов опроса.\n")
+		case len(summary.Outstanding) == 0:
+			response.WriteString(fmt.Sprintf("Все таблицы за цикл от %s подтверждены. Спасибо!\n", summary.Cycle.CycleDate.Format("02.01.2006")))
+		default:
+			response.WriteString(fmt.Sprintf("Неподтверждённые таблицы за цикл от %s:\n", summary.Cycle.CycleDate.Format("02.01.2006")))
+			for _, key := range summary.Outstanding {
+				response.WriteString(fmt.Sprintf(" - %s\n", app.ReportTitle(key)))
+			}
+		}
+
+		cfg := liveCfg.Current()
+		nextStart, cycleType, err := scheduler.NextCycleStart(cfg.CronSpec15th, cfg.CronSpecDailyCheckForLastDay, time.Now())
+		if err != nil {
+			handlerLogger.WithError(err).Warn("Could not compute next cycle start")
+		} else {
+			response.WriteString(fmt.Sprintf("\nСледующий опрос (%s): %s.", cycleType.DisplayName(), nextStart.Format("02.01.2006 15:04")))
+		}
+		return c.Send(response.String())
+	})
+
+	b.Handle("/schedule", func(c telebot.Context) error {
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":   "/schedule",
+			"sender_id": c.Sender().ID,
+		})
+		handlerLogger.Info("Command received")
+
+		cfg := liveCfg.Current()
+		if c.Sender().ID != cfg.AdminTelegramID {
+			t, err := teacherRepo.GetByTelegramID(ctx, c.Sender().ID)
+			if err != nil {
+				if err == idb.ErrTeacherNotFound {
+					handlerLogger.Info("User is unknown")
+					return c.Send("Доступных команд для вас нет. Если вы преподаватель и ожидаете уведомлений, пожалуйста, обратитесь к администратору для добавления вас в систему.")
+				}
+				handlerLogger.WithError(err).Error("Failed to get teacher by Telegram ID")
+				return c.Send("Произошла ошибка при проверке вашего статуса. Пожалуйста, попробуйте позже.")
+			}
+			if !t.IsActive {
+				handlerLogger.WithField("teacher_id", t.ID).Info("User identified as Inactive Teacher")
+				return c.Send("Ваш аккаунт преподавателя неактивен. Пожалуйста, свяжитесь с администратором.")
+			}
+		}
+
+		starts, err := scheduler.UpcomingCycleStarts(cfg.CronSpec15th, cfg.CronSpecDailyCheckForLastDay, time.Now(), scheduleListLength)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Could not compute upcoming cycle starts")
+			return c.Send("Не удалось рассчитать расписание опросов. Пожалуйста, попробуйте позже.")
+		}
+
+		var response strings.Builder
+		response.WriteString("Ближайшие опросы:\n")
+		for _, start := range starts {
+			response.WriteString(fmt.Sprintf(" - %s: %s\n", start.At.Format("02.01.2006 15:04"), start.Type.DisplayName()))
+		}
+		return c.Send(response.String())
+	})
+}
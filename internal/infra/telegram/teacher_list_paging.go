@@ -0,0 +1,141 @@
+// internal/infra/telegram/teacher_list_paging.go
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"teacher_notification_bot/internal/app"
+	"teacher_notification_bot/internal/domain/teacher"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// teachersPageSize is how many teachers one /list_teachers page shows.
+const teachersPageSize = 20
+
+// pageCallbackPrefix starts the data of "next/previous page" buttons: page_<list>_<page>, pages counted from 1.
+const pageCallbackPrefix = "page_"
+
+// Lists that can be paged; the name is the <list> part of the callback data.
+const (
+	pagedListActiveTeachers = "teachers"
+	pagedListAllTeachers    = "allteachers"
+)
+
+// teacherListTitles are the headers of the pageable teacher lists.
+var teacherListTitles = map[string]string{
+	pagedListActiveTeachers: "Активные преподаватели",
+	pagedListAllTeachers:    "Все преподаватели",
+}
+
+// loadTeacherList fetches the teachers of a pageable list.
+func loadTeacherList(ctx context.Context, adminService *app.AdminService, adminID int64, list string) ([]*teacher.Teacher, error) {
+	if list == pagedListAllTeachers {
+		return adminService.ListAllTeachers(ctx, adminID)
+	}
+	return adminService.ListActiveTeachers(ctx, adminID)
+}
+
+// renderTeacherListPage renders one page of a teacher list with navigation buttons. A page past the end
+// (e.g. the list shrank since the button was sent) shows the last page.
+func renderTeacherListPage(list string, teachersList []*teacher.Teacher, page int) (string, *telebot.ReplyMarkup) {
+	totalPages := (len(teachersList) + teachersPageSize - 1) / teachersPageSize
+	if page > totalPages {
+		page = totalPages
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * teachersPageSize
+	end := start + teachersPageSize
+	if end > len(teachersList) {
+		end = len(teachersList)
+	}
+
+	var response strings.Builder
+	if totalPages > 1 {
+		response.WriteString(fmt.Sprintf("---	%s (стр. %d из %d)	---\n", teacherListTitles[list], page, totalPages))
+	} else {
+		response.WriteString(fmt.Sprintf("---	%s	---\n", teacherListTitles[list]))
+	}
+	for _, t := range teachersList[start:end] {
+		status := "Деактивирован"
+		if t.IsActive {
+			status = "Активен"
+		}
+		response.WriteString(fmt.Sprintf("ID: %d, Telegram ID: %d, Имя: %s, Фамилия: %s, Статус: %s\n",
+			t.ID,
+			t.TelegramID,
+			t.FirstName,
+			t.LastName.String,
+			status))
+	}
+
+	markup := &telebot.ReplyMarkup{}
+	var buttons []telebot.Btn
+	if page > 1 {
+		buttons = append(buttons, markup.Data("◀️ Назад", fmt.Sprintf("%s%s_%d", pageCallbackPrefix, list, page-1)))
+	}
+	if page < totalPages {
+		buttons = append(buttons, markup.Data("Дальше ▶️", fmt.Sprintf("%s%s_%d", pageCallbackPrefix, list, page+1)))
+	}
+	if len(buttons) == 0 {
+		return response.String(), nil
+	}
+	markup.Inline(markup.Row(buttons...))
+	return response.String(), markup
+}
+
+// parsePageCallback splits a page_ callback payload into the list name and the page number.
+func parsePageCallback(payload string) (string, int, error) {
+	sep := strings.LastIndex(payload, "_")
+	if sep < 0 {
+		return "", 0, fmt.Errorf("page callback %q has no page number", payload)
+	}
+	list := payload[:sep]
+	if _, ok := teacherListTitles[list]; !ok {
+		return "", 0, fmt.Errorf("unknown paged list %q", list)
+	}
+	page, err := parseCallbackID(payload[sep+1:], 32)
+	if err != nil {
+		return "", 0, err
+	}
+	return list, int(page), nil
+}
+
+// RegisterTeacherListPaging handles the "next/previous page" buttons of /list_teachers by editing the
+// message in place. The list is fetched again, so a page always shows current data.
+func RegisterTeacherListPaging(ctx context.Context, router *CallbackRouter, adminService *app.AdminService, adminTelegramID int64) {
+	router.Handle(pageCallbackPrefix, func(c telebot.Context, payload string, baseLogger *logrus.Entry) error {
+		handlerLogger := baseLogger.WithField("handler", "list_page_callback")
+		if c.Sender().ID != adminTelegramID {
+			handlerLogger.Warn("Unauthorized page callback")
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: У вас нет прав для выполнения этой команды."})
+		}
+
+		list, page, err := parsePageCallback(payload)
+		if err != nil {
+			handlerLogger.WithError(err).Warn("Invalid page callback")
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: Некорректный запрос."})
+		}
+		handlerLogger = handlerLogger.WithFields(logrus.Fields{"list": list, "page": page})
+
+		teachersList, err := loadTeacherList(ctx, adminService, c.Sender().ID, list)
+		if err != nil {
+			handlerLogger.WithError(err).Error("Failed to get list of teachers")
+			return c.Respond(&telebot.CallbackResponse{Text: "Произошла ошибка."})
+		}
+		if len(teachersList) == 0 {
+			return c.Respond(&telebot.CallbackResponse{Text: "Список преподавателей пуст."})
+		}
+
+		text, markup := renderTeacherListPage(list, teachersList, page)
+		if _, err := c.Bot().Edit(c.Message(), text, &telebot.SendOptions{ReplyMarkup: markup}); err != nil && err != telebot.ErrSameMessageContent {
+			handlerLogger.WithError(err).Error("Failed to edit teacher list page")
+			return c.Respond(&telebot.CallbackResponse{Text: "Произошла ошибка."})
+		}
+		return c.Respond(&telebot.CallbackResponse{})
+	})
+}
@@ -3,7 +3,6 @@ package telegram
 
 import (
 	"context"
-	"strconv"
 	"strings"
 	"teacher_notification_bot/internal/app" // For NotificationService interface
 
@@ -11,71 +10,148 @@ import (
 	"gopkg.in/telebot.v3"
 )
 
-func RegisterTeacherResponseHandlers(ctx context.Context, b *telebot.Bot, notificationService app.NotificationService, baseLogger *logrus.Entry) {
-	b.Handle(telebot.OnCallback, func(c telebot.Context) error {
-		callback := c.Callback()
-		if callback == nil {
-			baseLogger.Error("Callback object is nil in OnCallback handler")
-			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка: Некорректный запрос."})
-		}
-		data := callback.Data
-		data = strings.TrimSpace(data) // Trim leading/trailing whitespace
+// alertCallbackErrors are answered with a popup the user has to dismiss instead of a toast that is easy to miss.
+// Pressing someone else's buttons usually means a forwarded message, which the user should clearly be told about;
+// an expired answer or nothing left to confirm means the press had no effect, which a toast would hide.
+var alertCallbackErrors = map[error]bool{
+	app.ErrCallbackOwnershipMismatch: true,
+	app.ErrAnswerWindowExpired:       true,
+	app.ErrNothingToConfirm:          true,
+}
 
-		handlerLogger := baseLogger.WithFields(logrus.Fields{
-			"handler":       "teacher_response_callback",
-			"sender_id":     c.Sender().ID,
-			"callback_data": data,
-		})
-		handlerLogger.Info("Callback received")
+// callbackErrorResponse answers a callback whose processing failed with err, as an alert for alertCallbackErrors.
+func callbackErrorResponse(err error, text string) *telebot.CallbackResponse {
+	return &telebot.CallbackResponse{Text: text, ShowAlert: alertCallbackErrors[err]}
+}
+
+// RegisterTeacherResponseHandlers registers the "Да"/"Нет" answer buttons (ans_yes_<ID>, ans_no_<ID>)
+// and the "Всё готово" reminder button (ans_all_<CycleID>).
+func RegisterTeacherResponseHandlers(ctx context.Context, router *CallbackRouter, notificationService app.NotificationService) {
+	router.Handle("ans_yes_", func(c telebot.Context, payload string, baseLogger *logrus.Entry) error {
+		handlerLogger := baseLogger.WithField("handler", "teacher_response_callback")
 
-		if strings.HasPrefix(data, "ans_yes_") {
-			parts := strings.Split(data, "_") // ans_yes_123
-			if len(parts) != 3 {
-				handlerLogger.Errorf("Invalid callback data format for 'yes': %s", data)
-				return c.Respond(&telebot.CallbackResponse{Text: "Ошибка обработки ответа."})
+		reportStatusID, err := parseCallbackID(payload, 64)
+		if err != nil {
+			handlerLogger.WithError(err).Errorf("Invalid reportStatusID '%s' in 'yes' callback", payload)
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка ID отчета."})
+		}
+		handlerLogger = handlerLogger.WithField("report_status_id", reportStatusID)
+
+		err = notificationService.ProcessTeacherYesResponse(ctx, reportStatusID, c.Sender().ID)
+		if err != nil {
+			if err == app.ErrCallbackOwnershipMismatch {
+				handlerLogger.WithError(err).Warn("Rejected 'Yes' response from a user who does not own the report status")
+				return c.Respond(callbackErrorResponse(err, "Это не ваш опрос."))
 			}
-			reportStatusIDStr := parts[2]
-			reportStatusID, err := strconv.ParseInt(reportStatusIDStr, 10, 64)
-			if err != nil {
-				handlerLogger.WithError(err).Errorf("Invalid reportStatusID '%s' in 'yes' callback", reportStatusIDStr)
-				return c.Respond(&telebot.CallbackResponse{Text: "Ошибка ID отчета."})
+			if err == app.ErrAnswerWindowExpired {
+				handlerLogger.Info("Rejected 'Yes' response given after the answer window")
+				return c.Respond(callbackErrorResponse(err, "Время для ответа истекло."))
 			}
-			handlerLogger = handlerLogger.WithField("report_status_id", reportStatusID)
+			handlerLogger.WithError(err).Error("Error processing 'Yes' response")
+			return c.Respond(callbackErrorResponse(err, "Произошла ошибка."))
+		}
+		handlerLogger.Info("Successfully processed 'Yes' response")
+		return c.Respond(&telebot.CallbackResponse{Text: "Ответ 'Да' принят!"})
+	})
 
-			err = notificationService.ProcessTeacherYesResponse(ctx, reportStatusID)
-			if err != nil {
-				handlerLogger.WithError(err).Error("Error processing 'Yes' response")
-				return c.Respond(&telebot.CallbackResponse{Text: "Произошла ошибка."})
-			}
-			handlerLogger.Info("Successfully processed 'Yes' response")
-			return c.Respond(&telebot.CallbackResponse{Text: "Ответ 'Да' принят!"})
+	router.Handle("ans_no_", func(c telebot.Context, payload string, baseLogger *logrus.Entry) error {
+		handlerLogger := baseLogger.WithField("handler", "teacher_response_callback")
+
+		reportStatusID, err := parseCallbackID(payload, 64)
+		if err != nil {
+			handlerLogger.WithError(err).Errorf("Invalid reportStatusID '%s' in 'no' callback", payload)
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка ID отчета."})
+		}
+		handlerLogger = handlerLogger.WithField("report_status_id", reportStatusID)
 
-		} else if strings.HasPrefix(data, "ans_no_") {
-			parts := strings.Split(data, "_") // ans_no_123
-			if len(parts) != 3 {
-				handlerLogger.Errorf("Invalid callback data format for 'no': %s", data)
-				return c.Respond(&telebot.CallbackResponse{Text: "Ошибка обработки ответа."})
+		err = notificationService.ProcessTeacherNoResponse(ctx, reportStatusID, c.Sender().ID)
+		if err != nil {
+			if err == app.ErrCallbackOwnershipMismatch {
+				handlerLogger.WithError(err).Warn("Rejected 'No' response from a user who does not own the report status")
+				return c.Respond(callbackErrorResponse(err, "Это не ваш опрос."))
 			}
-			reportStatusIDStr := parts[2]
-			reportStatusID, err := strconv.ParseInt(reportStatusIDStr, 10, 64)
-			if err != nil {
-				handlerLogger.WithError(err).Errorf("Invalid reportStatusID '%s' in 'no' callback", reportStatusIDStr)
-				return c.Respond(&telebot.CallbackResponse{Text: "Ошибка ID отчета."})
+			if err == app.ErrAnswerWindowExpired {
+				handlerLogger.Info("Rejected 'No' response given after the answer window")
+				return c.Respond(callbackErrorResponse(err, "Время для ответа истекло."))
 			}
-			handlerLogger = handlerLogger.WithField("report_status_id", reportStatusID)
+			handlerLogger.WithError(err).Error("Error processing 'No' response")
+			return c.Respond(callbackErrorResponse(err, "Произошла ошибка."))
+		}
+		// The service sends the textual "Понял(а)..." message.
+		handlerLogger.Info("Successfully processed 'No' response")
+		return c.Respond(&telebot.CallbackResponse{Text: ""}) // Respond with empty text to dismiss loading, service sends the actual reply
+	})
 
-			err = notificationService.ProcessTeacherNoResponse(ctx, reportStatusID)
-			if err != nil {
-				handlerLogger.WithError(err).Error("Error processing 'No' response")
-				return c.Respond(&telebot.CallbackResponse{Text: "Произошла ошибка."})
+	router.Handle("ans_all_", func(c telebot.Context, payload string, baseLogger *logrus.Entry) error {
+		handlerLogger := baseLogger.WithField("handler", "teacher_response_callback")
+
+		cycleID, err := parseCallbackID(payload, 32)
+		if err != nil {
+			handlerLogger.WithError(err).Errorf("Invalid cycleID '%s' in 'confirm all' callback", payload)
+			return c.Respond(&telebot.CallbackResponse{Text: "Ошибка ID цикла."})
+		}
+		handlerLogger = handlerLogger.WithField("cycle_id", cycleID)
+
+		err = notificationService.ProcessTeacherConfirmAllResponse(ctx, c.Sender().ID, int32(cycleID))
+		if err != nil {
+			switch err {
+			case app.ErrCallbackOwnershipMismatch:
+				handlerLogger.WithError(err).Warn("Rejected 'confirm all' response from a user who is not a teacher")
+				return c.Respond(callbackErrorResponse(err, "Это не ваш опрос."))
+			case app.ErrNothingToConfirm:
+				return c.Respond(callbackErrorResponse(err, "Все таблицы уже подтверждены."))
+			case app.ErrAnswerWindowExpired:
+				handlerLogger.Info("Rejected 'confirm all' response given after the answer window")
+				return c.Respond(callbackErrorResponse(err, "Время для ответа истекло."))
+			default:
+				handlerLogger.WithError(err).Error("Error processing 'confirm all' response")
+				return c.Respond(callbackErrorResponse(err, "Произошла ошибка."))
 			}
-			// The service sends the textual "Понял(а)..." message.
-			handlerLogger.Info("Successfully processed 'No' response")
-			return c.Respond(&telebot.CallbackResponse{Text: ""}) // Respond with empty text to dismiss loading, service sends the actual reply
 		}
+		// The service sends the final "Спасибо!" message.
+		handlerLogger.Info("Successfully processed 'confirm all' response")
+		return c.Respond(&telebot.CallbackResponse{Text: "Все таблицы отмечены как заполненные."})
+	})
+}
 
-		// Fallback for unhandled callbacks by this specific handler.
-		handlerLogger.Warnf("Unhandled callback data by teacher_response_handler: %s", data)
-		return c.Respond(&telebot.CallbackResponse{Text: "Неизвестное действие."})
+// RegisterTeacherTextAnswerHandler handles "Да"/"Нет" sent from the reply keyboard. Such messages carry no
+// report ID, so the answer applies to the teacher's latest pending question. Other text is ignored.
+func RegisterTeacherTextAnswerHandler(ctx context.Context, b *telebot.Bot, notificationService app.NotificationService, baseLogger *logrus.Entry) {
+	b.Handle(telebot.OnText, func(c telebot.Context) error {
+		var answeredYes bool
+		switch strings.TrimSpace(c.Text()) {
+		case app.AnswerTextYes:
+			answeredYes = true
+		case app.AnswerTextNo:
+			answeredYes = false
+		default:
+			return nil
+		}
+		handlerLogger := baseLogger.WithFields(logrus.Fields{
+			"handler":      "teacher_text_answer",
+			"sender_id":    c.Sender().ID,
+			"answered_yes": answeredYes,
+		})
+
+		err := notificationService.ProcessTeacherTextAnswer(ctx, c.Sender().ID, answeredYes)
+		if err != nil {
+			switch err {
+			case app.ErrNoPendingQuestion:
+				handlerLogger.Info("No pending question for text answer")
+				return c.Send("Сейчас нет вопросов, ожидающих ответа.", &telebot.SendOptions{ReplyMarkup: &telebot.ReplyMarkup{RemoveKeyboard: true}})
+			case app.ErrCallbackOwnershipMismatch:
+				handlerLogger.WithError(err).Warn("Rejected text answer from a user who does not own the report status")
+				return c.Send("Это не ваш опрос.")
+			case app.ErrAnswerWindowExpired:
+				handlerLogger.Info("Rejected text answer given after the answer window")
+				return c.Send("Время для ответа истекло.")
+			default:
+				handlerLogger.WithError(err).Error("Error processing text answer")
+				return c.Send("Произошла ошибка.")
+			}
+		}
+		// The service sends the follow-up question or the final reply itself.
+		handlerLogger.Info("Successfully processed text answer")
+		return nil
 	})
 }
@@ -0,0 +1,28 @@
+package telegram
+
+import (
+	"errors"
+	"teacher_notification_bot/internal/app"
+	"testing"
+)
+
+func TestCallbackErrorResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantAlert bool
+	}{
+		{name: "someone else's question", err: app.ErrCallbackOwnershipMismatch, wantAlert: true},
+		{name: "answer window expired", err: app.ErrAnswerWindowExpired, wantAlert: true},
+		{name: "nothing to confirm", err: app.ErrNothingToConfirm, wantAlert: true},
+		{name: "unexpected error", err: errors.New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := callbackErrorResponse(tt.err, "Ошибка")
+			if resp.ShowAlert != tt.wantAlert || resp.Text != "Ошибка" {
+				t.Errorf("response = {%q, alert %v}, want {%q, alert %v}", resp.Text, resp.ShowAlert, "Ошибка", tt.wantAlert)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package version holds build information and the process start time, for operators checking what is running.
+package version
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Build information, set at build time, e.g.:
+//
+//	go build -ldflags "-X teacher_notification_bot/internal/version.Version=1.4.0 -X teacher_notification_bot/internal/version.Commit=$(git rev-parse --short HEAD) -X teacher_notification_bot/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+var (
+	startMu   sync.RWMutex
+	startTime time.Time
+)
+
+// MarkStarted records the process start time; main calls it first thing.
+func MarkStarted(t time.Time) {
+	startMu.Lock()
+	defer startMu.Unlock()
+	startTime = t
+}
+
+// StartTime returns the time recorded by MarkStarted (zero if it was never called).
+func StartTime() time.Time {
+	startMu.RLock()
+	defer startMu.RUnlock()
+	return startTime
+}
+
+// Uptime is the time since MarkStarted, or 0 if it was never called.
+func Uptime() time.Duration {
+	started := StartTime()
+	if started.IsZero() {
+		return 0
+	}
+	return time.Since(started)
+}
+
+// Info is a snapshot of the build information and uptime, e.g. for the /healthz JSON body.
+type Info struct {
+	Version       string    `json:"version"`
+	Commit        string    `json:"commit"`
+	BuildTime     string    `json:"build_time"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+}
+
+// Current returns the build information and the uptime at the moment of the call.
+func Current() Info {
+	return Info{
+		Version:       Version,
+		Commit:        Commit,
+		BuildTime:     BuildTime,
+		StartedAt:     StartTime(),
+		UptimeSeconds: int64(Uptime() / time.Second),
+	}
+}
+
+// FormatUptime renders an uptime for admin-facing messages, e.g. "3 д. 4 ч. 5 мин." or "12 мин.".
+// Seconds are dropped; anything under a minute is "меньше минуты".
+func FormatUptime(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	if d <= 0 {
+		return "меньше минуты"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int((d % (24 * time.Hour)) / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%d д. %d ч. %d мин.", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%d ч. %d мин.", hours, minutes)
+	default:
+		return fmt.Sprintf("%d мин.", minutes)
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE teachers
+DROP COLUMN IF EXISTS notify_manager_on_complete;
@@ -0,0 +1,2 @@
+ALTER TABLE teachers
+ADD COLUMN IF NOT EXISTS notify_manager_on_complete BOOLEAN DEFAULT TRUE NOT NULL;
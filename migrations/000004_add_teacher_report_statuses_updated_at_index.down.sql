@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_teacher_report_statuses_updated_at;
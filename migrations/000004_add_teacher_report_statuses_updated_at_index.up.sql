@@ -0,0 +1,2 @@
+-- Supports incremental polling of changed statuses (ListReportStatusesUpdatedSince)
+CREATE INDEX IF NOT EXISTS idx_teacher_report_statuses_updated_at ON teacher_report_statuses(updated_at);
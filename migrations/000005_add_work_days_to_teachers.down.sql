@@ -0,0 +1,2 @@
+ALTER TABLE teachers
+DROP COLUMN IF EXISTS work_days;
@@ -0,0 +1,3 @@
+-- Bitmask of weekdays (bit N = weekday N, Sunday = 0) the teacher receives reminders on; 0 means every day
+ALTER TABLE teachers
+ADD COLUMN IF NOT EXISTS work_days SMALLINT DEFAULT 0 NOT NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS cycle_teacher_exclusions (
+    cycle_id INTEGER NOT NULL REFERENCES notification_cycles(id) ON DELETE CASCADE,
+    teacher_id BIGINT NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (cycle_id, teacher_id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notification_runs (
+    id BIGSERIAL PRIMARY KEY,
+    cycle_id INTEGER NOT NULL REFERENCES notification_cycles(id) ON DELETE CASCADE,
+    teachers_targeted INTEGER NOT NULL,
+    statuses_created INTEGER NOT NULL,
+    messages_sent INTEGER NOT NULL,
+    messages_failed INTEGER NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_runs_cycle_id ON notification_runs(cycle_id);
@@ -0,0 +1,7 @@
+-- Telegram only lets a bot message users who have started it; set once the teacher has interacted with the bot
+ALTER TABLE teachers
+ADD COLUMN IF NOT EXISTS has_started_bot BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- Teachers who already received a message have evidently started the bot
+UPDATE teachers SET has_started_bot = TRUE
+WHERE id IN (SELECT DISTINCT teacher_id FROM teacher_report_statuses WHERE last_notified_at IS NOT NULL);
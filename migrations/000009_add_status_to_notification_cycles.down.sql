@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notification_cycles_status;
+
+ALTER TABLE notification_cycles
+DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+-- 'OPEN' while reminders are still being sent, 'CLOSED' once no work remains (or an admin closed it)
+ALTER TABLE notification_cycles
+ADD COLUMN IF NOT EXISTS status VARCHAR(20) DEFAULT 'OPEN' NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notification_cycles_status ON notification_cycles(status);
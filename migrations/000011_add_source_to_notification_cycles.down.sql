@@ -0,0 +1,2 @@
+ALTER TABLE notification_cycles
+DROP COLUMN IF EXISTS source;
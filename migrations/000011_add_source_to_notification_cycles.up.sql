@@ -0,0 +1,3 @@
+-- 'SCHEDULED' for regular cycles, 'SIMULATION' for throwaway QA cycles created by /simulate
+ALTER TABLE notification_cycles
+ADD COLUMN IF NOT EXISTS source VARCHAR(20) DEFAULT 'SCHEDULED' NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_cycles_cycle_date_type_round;
+CREATE INDEX IF NOT EXISTS idx_notification_cycles_cycle_date_type ON notification_cycles(cycle_date, cycle_type);
+
+ALTER TABLE notification_cycles
+DROP COLUMN IF EXISTS round;
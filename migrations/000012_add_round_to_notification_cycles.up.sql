@@ -0,0 +1,6 @@
+-- Several initiation rounds may run for the same date and type (e.g. a morning and an afternoon round)
+ALTER TABLE notification_cycles
+ADD COLUMN IF NOT EXISTS round INTEGER DEFAULT 1 NOT NULL;
+
+DROP INDEX IF EXISTS idx_notification_cycles_cycle_date_type;
+CREATE INDEX IF NOT EXISTS idx_notification_cycles_cycle_date_type_round ON notification_cycles(cycle_date, cycle_type, round);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS cycle_teacher_completions (
+    cycle_id INTEGER NOT NULL REFERENCES notification_cycles(id) ON DELETE CASCADE,
+    teacher_id BIGINT NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    notified_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (cycle_id, teacher_id)
+);
+
+-- Teachers who already confirmed everything were notified by the regular flow; don't let /reconcile_cycle repeat it.
+INSERT INTO cycle_teacher_completions (cycle_id, teacher_id)
+SELECT cycle_id, teacher_id
+FROM teacher_report_statuses
+GROUP BY cycle_id, teacher_id
+HAVING BOOL_AND(status = 'ANSWERED_YES')
+ON CONFLICT (cycle_id, teacher_id) DO NOTHING;
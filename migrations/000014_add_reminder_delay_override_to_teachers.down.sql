@@ -0,0 +1,2 @@
+ALTER TABLE teachers
+DROP COLUMN IF EXISTS reminder_delay_override_seconds;
@@ -0,0 +1,3 @@
+-- Per-teacher delay before the reminder that follows a "No"; NULL means the cycle's delay applies
+ALTER TABLE teachers
+ADD COLUMN IF NOT EXISTS reminder_delay_override_seconds BIGINT;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_teacher_report_statuses_last_send_failed_at;
+
+ALTER TABLE teacher_report_statuses
+DROP COLUMN IF EXISTS last_send_failed_at,
+DROP COLUMN IF EXISTS last_send_error,
+DROP COLUMN IF EXISTS send_failure_count;
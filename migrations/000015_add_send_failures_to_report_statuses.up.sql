@@ -0,0 +1,9 @@
+-- Delivery failures of questions and reminders, so operators can see them without reading logs
+ALTER TABLE teacher_report_statuses
+ADD COLUMN IF NOT EXISTS send_failure_count INTEGER DEFAULT 0 NOT NULL,
+ADD COLUMN IF NOT EXISTS last_send_error TEXT,
+ADD COLUMN IF NOT EXISTS last_send_failed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_teacher_report_statuses_last_send_failed_at
+ON teacher_report_statuses (last_send_failed_at)
+WHERE last_send_failed_at IS NOT NULL;
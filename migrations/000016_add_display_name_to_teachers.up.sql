@@ -0,0 +1,3 @@
+-- Name used to address the teacher (e.g. a nickname); NULL means the first name is used
+ALTER TABLE teachers
+ADD COLUMN IF NOT EXISTS display_name VARCHAR(255);
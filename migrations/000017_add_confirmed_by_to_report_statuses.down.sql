@@ -0,0 +1,2 @@
+ALTER TABLE teacher_report_statuses
+DROP COLUMN IF EXISTS confirmed_by_telegram_id;
@@ -0,0 +1,3 @@
+-- Who entered a confirmation on the teacher's behalf (e.g. a manager via /bulk_confirm); NULL means the teacher answered
+ALTER TABLE teacher_report_statuses
+ADD COLUMN IF NOT EXISTS confirmed_by_telegram_id BIGINT;
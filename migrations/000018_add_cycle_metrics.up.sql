@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS cycle_metrics (
+    cycle_id INTEGER PRIMARY KEY REFERENCES notification_cycles(id) ON DELETE CASCADE,
+    teachers_notified INTEGER NOT NULL,
+    total_statuses INTEGER NOT NULL,
+    confirmations INTEGER NOT NULL,
+    reminders_sent INTEGER NOT NULL,
+    avg_confirmation_seconds DOUBLE PRECISION NOT NULL,
+    completion_seconds BIGINT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
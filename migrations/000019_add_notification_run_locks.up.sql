@@ -0,0 +1,5 @@
+-- Held while a cycle's questions are being sent, so overlapping initiations (cron and /run_cycle) send only once
+CREATE TABLE IF NOT EXISTS notification_run_locks (
+    run_key VARCHAR(100) PRIMARY KEY,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_notification_cycles_unique_scheduled;
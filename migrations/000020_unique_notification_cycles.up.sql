@@ -0,0 +1,25 @@
+-- Scheduled cycles are unique per date, type and round. Earlier duplicates (e.g. from concurrent initiations) are
+-- kept, with their statuses, as later rounds of the same date and type. Simulation cycles may repeat.
+WITH duplicates AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY cycle_date, cycle_type, round ORDER BY id) AS rn
+    FROM notification_cycles
+    WHERE source = 'SCHEDULED'
+), moved AS (
+    SELECT nc.id, ROW_NUMBER() OVER (PARTITION BY nc.cycle_date, nc.cycle_type ORDER BY nc.id) AS n
+    FROM notification_cycles nc
+    JOIN duplicates d ON d.id = nc.id
+    WHERE d.rn > 1
+), last_rounds AS (
+    SELECT cycle_date, cycle_type, MAX(round) AS max_round
+    FROM notification_cycles
+    WHERE source = 'SCHEDULED'
+    GROUP BY cycle_date, cycle_type
+)
+UPDATE notification_cycles nc
+SET round = lr.max_round + m.n
+FROM moved m, last_rounds lr
+WHERE nc.id = m.id AND lr.cycle_date = nc.cycle_date AND lr.cycle_type = nc.cycle_type;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_cycles_unique_scheduled
+ON notification_cycles(cycle_date, cycle_type, round, source)
+WHERE source = 'SCHEDULED';
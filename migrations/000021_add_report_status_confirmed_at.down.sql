@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS set_confirmed_at_teacher_report_statuses ON teacher_report_statuses;
+DROP FUNCTION IF EXISTS trigger_set_confirmed_at();
+ALTER TABLE teacher_report_statuses DROP COLUMN IF EXISTS confirmed_at;
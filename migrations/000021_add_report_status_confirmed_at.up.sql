@@ -0,0 +1,24 @@
+-- When a status last became ANSWERED_YES. updated_at cannot serve: later writes (send failures, proxy marks) bump it too.
+ALTER TABLE teacher_report_statuses ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMPTZ;
+
+-- Best available value for statuses confirmed before this column existed.
+UPDATE teacher_report_statuses SET confirmed_at = updated_at WHERE status = 'ANSWERED_YES' AND confirmed_at IS NULL;
+
+CREATE OR REPLACE FUNCTION trigger_set_confirmed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.status = 'ANSWERED_YES' THEN
+    IF OLD.status IS DISTINCT FROM 'ANSWERED_YES' THEN
+      NEW.confirmed_at = NOW();
+    END IF;
+  ELSE
+    NEW.confirmed_at = NULL;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_confirmed_at_teacher_report_statuses
+BEFORE UPDATE ON teacher_report_statuses
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_confirmed_at();
@@ -0,0 +1 @@
+ALTER TABLE teacher_report_statuses DROP COLUMN IF EXISTS reminders_sent;
@@ -0,0 +1,3 @@
+-- Same-day (1-hour and 4-hour) reminders sent for the status. Kept apart from response_attempts, which counts
+-- next-day and /remind reminders only, so the "confirmed on first ask" statistics keep their meaning.
+ALTER TABLE teacher_report_statuses ADD COLUMN IF NOT EXISTS reminders_sent INTEGER NOT NULL DEFAULT 0;
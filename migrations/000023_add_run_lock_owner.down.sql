@@ -0,0 +1 @@
+ALTER TABLE notification_run_locks DROP COLUMN IF EXISTS owner;
@@ -0,0 +1,2 @@
+-- Random token of the current holder, so a holder whose lock went stale and was taken over can't release the new one
+ALTER TABLE notification_run_locks ADD COLUMN IF NOT EXISTS owner VARCHAR(64) NOT NULL DEFAULT '';